@@ -10,4 +10,14 @@ type OAuth2ClientUsecase interface {
 	Get(ctx context.Context, req *dto.OAuth2ClientGetRequest) (*dto.OAuth2ClientGetResponse, error)
 	Create(ctx context.Context, req *dto.OAuth2ClientCreateRequest) (*dto.OAuth2ClientCreateResponse, error)
 	CreateByAdmin(ctx context.Context, req *dto.OAuth2ClientCreateFirstRequest) (*dto.OAuth2ClientCreateByAdminResponse, error)
+	List(ctx context.Context, req *dto.OAuth2ClientListRequest) (*dto.OAuth2ClientListResponse, error)
+	Update(ctx context.Context, req *dto.OAuth2ClientUpdateRequest) (*dto.OAuth2ClientUpdateResponse, error)
+	Disable(ctx context.Context, req *dto.OAuth2ClientDisableRequest) (*dto.OAuth2ClientDisableResponse, error)
+	Delete(ctx context.Context, req *dto.OAuth2ClientDeleteRequest) (*dto.OAuth2ClientDeleteResponse, error)
+	Transfer(ctx context.Context, req *dto.OAuth2ClientTransferRequest) (*dto.OAuth2ClientTransferResponse, error)
+
+	Register(ctx context.Context, req *dto.OAuth2ClientRegisterRequest) (*dto.OAuth2ClientRegistrationResponse, error)
+	ReadRegistration(ctx context.Context, req *dto.OAuth2ClientReadRegistrationRequest) (*dto.OAuth2ClientRegistrationResponse, error)
+	UpdateRegistration(ctx context.Context, req *dto.OAuth2ClientUpdateRegistrationRequest) (*dto.OAuth2ClientRegistrationResponse, error)
+	DeleteRegistration(ctx context.Context, req *dto.OAuth2ClientDeleteRegistrationRequest) (*dto.OAuth2ClientDeleteRegistrationResponse, error)
 }
@@ -8,7 +8,19 @@ import (
 
 type OAuth2Usecase interface {
 	Authorize(ctx context.Context, req *dto.OAuth2AuthorizeRequest) (*dto.OAuth2AuthorizeResponse, error)
+	PushAuthorization(ctx context.Context, req *dto.OAuth2PushAuthorizationRequest) (*dto.OAuth2PushAuthorizationResponse, error)
+	ResolveAuthorization(ctx context.Context, req *dto.OAuth2AuthorizeRequest) (*dto.OAuth2ResolveAuthorizationResponse, error)
 	Token(ctx context.Context, req *dto.OAuth2TokenRequest) (*dto.OAuth2TokenResponse, error)
+	Revoke(ctx context.Context, req *dto.OAuth2RevokeRequest) (*dto.OAuth2RevokeResponse, error)
+	Introspect(ctx context.Context, req *dto.OAuth2IntrospectRequest) (*dto.OAuth2IntrospectResponse, error)
+	CreateDPoPNonce(ctx context.Context, req *dto.OAuth2CreateDPoPNonceRequest) (*dto.OAuth2CreateDPoPNonceResponse, error)
+	ValidateDPoPProof(ctx context.Context, req *dto.OAuth2ValidateDPoPProofRequest) (*dto.OAuth2ValidateDPoPProofResponse, error)
+	IsTokenRevoked(ctx context.Context, req *dto.OAuth2IsTokenRevokedRequest) (*dto.OAuth2IsTokenRevokedResponse, error)
+	DeviceAuthorization(ctx context.Context, req *dto.OAuth2DeviceAuthorizationRequest) (*dto.OAuth2DeviceAuthorizationResponse, error)
+	DeviceVerify(ctx context.Context, req *dto.OAuth2DeviceVerifyRequest) (*dto.OAuth2DeviceVerifyResponse, error)
+	DeviceConfirm(ctx context.Context, req *dto.OAuth2DeviceConfirmRequest) (*dto.OAuth2DeviceConfirmResponse, error)
+	BackchannelAuthorize(ctx context.Context, req *dto.OAuth2BackchannelAuthorizeRequest) (*dto.OAuth2BackchannelAuthorizeResponse, error)
+	BackchannelConsent(ctx context.Context, req *dto.OAuth2BackchannelConsentRequest) (*dto.OAuth2BackchannelConsentResponse, error)
 	AuthenticationCallback(ctx context.Context, req *dto.OAuth2AuthenticationCallbackRequest) (*dto.OAuth2AuthenticationCallbackResponse, error)
 	SessionUpdate(ctx context.Context, req *dto.OAuth2SessionUpdateRequest) (*dto.OAuth2SessionUpdateResponse, error)
 	GetConsent(ctx context.Context, req *dto.OAuth2GetConsentRequest) (*dto.OAuth2GetConsentResponse, error)
@@ -0,0 +1,13 @@
+package abstraction
+
+import (
+	"context"
+
+	"github.com/xybor/todennus-backend/usecase/dto"
+)
+
+type OIDCUsecase interface {
+	GetDiscovery(ctx context.Context, req *dto.OIDCGetDiscoveryRequest) (*dto.OIDCGetDiscoveryResponse, error)
+	GetJWKS(ctx context.Context, req *dto.OIDCGetJWKSRequest) (*dto.OIDCGetJWKSResponse, error)
+	GetUserInfo(ctx context.Context, req *dto.OIDCGetUserInfoRequest) (*dto.OIDCGetUserInfoResponse, error)
+}
@@ -4,12 +4,24 @@ import (
 	"context"
 	"strings"
 
+	"github.com/xybor/todennus-backend/adapter/abstraction"
+	"github.com/xybor/todennus-backend/usecase"
 	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/todennus-backend/usecase/reqctx"
 	"github.com/xybor/x/token"
 	"github.com/xybor/x/xcontext"
 )
 
-func WithAuthenticate(ctx context.Context, authorization string, engine token.Engine) context.Context {
+// WithAuthenticate authenticates the request by the access token in the
+// authorization header. The dpop is nil if the transport does not support DPoP
+// proofs, so DPoP-bound tokens are never accepted there.
+func WithAuthenticate(
+	ctx context.Context,
+	authorization string,
+	dpop *dto.OAuth2DPoP,
+	engine token.Engine,
+	oauth2Usecase abstraction.OAuth2Usecase,
+) context.Context {
 	if authorization == "" {
 		return ctx
 	}
@@ -19,7 +31,7 @@ func WithAuthenticate(ctx context.Context, authorization string, engine token.En
 		return ctx
 	}
 
-	if engine.Type() != tokenType {
+	if engine.Type() != tokenType && usecase.AccessTokenTypeDPoP != tokenType {
 		return ctx
 	}
 
@@ -41,10 +53,52 @@ func WithAuthenticate(ctx context.Context, authorization string, engine token.En
 		return ctx
 	}
 
+	resp, err := oauth2Usecase.IsTokenRevoked(ctx, &dto.OAuth2IsTokenRevokedRequest{TokenID: dtoken.Metadata.ID})
+	if err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-check-revoked-token", "err", err)
+		return ctx
+	}
+
+	if resp.Revoked {
+		xcontext.Logger(ctx).Debug("revoked token", "jti", dtoken.Metadata.ID)
+		return ctx
+	}
+
+	// A certificate-bound token is only accepted from the holder of the
+	// certificate (RFC 8705).
+	if dtoken.CertificateThumbprint != "" {
+		certificate := reqctx.ClientCertificate(ctx)
+		if certificate == nil || certificate.Thumbprint() != dtoken.CertificateThumbprint {
+			xcontext.Logger(ctx).Debug("mismatched certificate of bound token", "jti", dtoken.Metadata.ID)
+			return ctx
+		}
+	}
+
+	// A DPoP-bound token is only accepted along with a proof signed by the
+	// bound key (RFC 9449 Section 7.1).
+	if dtoken.KeyThumbprint != "" || tokenType == usecase.AccessTokenTypeDPoP {
+		if dtoken.KeyThumbprint == "" || tokenType != usecase.AccessTokenTypeDPoP || dpop == nil {
+			xcontext.Logger(ctx).Debug("mismatched scheme of dpop-bound token", "jti", dtoken.Metadata.ID)
+			return ctx
+		}
+
+		_, err := oauth2Usecase.ValidateDPoPProof(ctx, &dto.OAuth2ValidateDPoPProofRequest{
+			DPoP:          *dpop,
+			AccessToken:   token,
+			KeyThumbprint: dtoken.KeyThumbprint,
+		})
+		if err != nil {
+			xcontext.Logger(ctx).Debug("failed-to-validate-dpop-proof", "err", err)
+			return ctx
+		}
+	}
+
 	ctx = xcontext.WithRequestUserID(ctx, dtoken.Metadata.Subject)
 	ctx = xcontext.WithScope(ctx, dtoken.Scope)
+	ctx = reqctx.WithRequestClientID(ctx, dtoken.ClientID)
 
-	xcontext.Logger(ctx).Debug("auth-info", "uid", dtoken.Metadata.Subject, "scope", dtoken.Scope.String())
+	xcontext.Logger(ctx).Debug("auth-info",
+		"uid", dtoken.Metadata.Subject, "cid", dtoken.ClientID, "scope", dtoken.Scope.String())
 
 	return ctx
 }
@@ -10,10 +10,11 @@ import (
 
 func App(config *config.Config, infras *wiring.Infras, usecases *wiring.Usecases) *grpc.Server {
 	s := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptor.UnaryInterceptor(config, infras)),
+		grpc.UnaryInterceptor(interceptor.UnaryInterceptor(config, infras, usecases)),
 	)
 
 	service.RegisterUserServer(s, NewUserServer(usecases.UserUsecase))
+	service.RegisterOAuth2Server(s, NewOAuth2Server(usecases.OAuth2Usecase))
 
 	return s
 }
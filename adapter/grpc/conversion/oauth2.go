@@ -0,0 +1,59 @@
+package conversion
+
+import (
+	"github.com/xybor-x/snowflake"
+	pbdto "github.com/xybor/todennus-backend/adapter/grpc/gen/dto"
+	ucdto "github.com/xybor/todennus-backend/usecase/dto"
+)
+
+func NewUsecaseOAuth2IntrospectRequest(req *pbdto.OAuth2IntrospectRequest) *ucdto.OAuth2IntrospectRequest {
+	return &ucdto.OAuth2IntrospectRequest{
+		OAuth2ClientAuthentication: ucdto.OAuth2ClientAuthentication{
+			ClientID:     snowflake.ID(req.ClientId),
+			ClientSecret: req.ClientSecret,
+		},
+		Token: req.Token,
+	}
+}
+
+func NewPbOAuth2IntrospectResponse(resp *ucdto.OAuth2IntrospectResponse) *pbdto.OAuth2IntrospectResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &pbdto.OAuth2IntrospectResponse{
+		Active:    resp.Active,
+		Scope:     resp.Scope,
+		ClientId:  resp.ClientID,
+		Sub:       resp.Subject,
+		Exp:       int64(resp.ExpiresAt),
+		Iat:       int64(resp.IssuedAt),
+		Jti:       resp.TokenID,
+		TokenType: resp.TokenType,
+		Cnf:       newPbOAuth2Confirmation(resp.CertificateThumbprint, resp.KeyThumbprint),
+		Act:       newPbOAuth2Actor(resp.Actor),
+	}
+}
+
+func newPbOAuth2Confirmation(certificateThumbprint, keyThumbprint string) *pbdto.OAuth2Confirmation {
+	if certificateThumbprint == "" && keyThumbprint == "" {
+		return nil
+	}
+
+	return &pbdto.OAuth2Confirmation{
+		X5TS256: certificateThumbprint,
+		Jkt:     keyThumbprint,
+	}
+}
+
+func newPbOAuth2Actor(actor *ucdto.OAuth2Actor) *pbdto.OAuth2Actor {
+	if actor == nil {
+		return nil
+	}
+
+	return &pbdto.OAuth2Actor{
+		Sub:      actor.Subject,
+		ClientId: actor.ClientID,
+		Act:      newPbOAuth2Actor(actor.Actor),
+	}
+}
@@ -0,0 +1,418 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v3.6.1
+// source: dto/oauth2.proto
+
+package dto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OAuth2IntrospectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientId     int64  `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	Token        string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *OAuth2IntrospectRequest) Reset() {
+	*x = OAuth2IntrospectRequest{}
+	mi := &file_dto_oauth2_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuth2IntrospectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuth2IntrospectRequest) ProtoMessage() {}
+
+func (x *OAuth2IntrospectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dto_oauth2_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuth2IntrospectRequest.ProtoReflect.Descriptor instead.
+func (*OAuth2IntrospectRequest) Descriptor() ([]byte, []int) {
+	return file_dto_oauth2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OAuth2IntrospectRequest) GetClientId() int64 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *OAuth2IntrospectRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type OAuth2IntrospectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Active    bool                `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Scope     string              `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	ClientId  string              `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Sub       string              `protobuf:"bytes,4,opt,name=sub,proto3" json:"sub,omitempty"`
+	Exp       int64               `protobuf:"varint,5,opt,name=exp,proto3" json:"exp,omitempty"`
+	Iat       int64               `protobuf:"varint,6,opt,name=iat,proto3" json:"iat,omitempty"`
+	Jti       string              `protobuf:"bytes,7,opt,name=jti,proto3" json:"jti,omitempty"`
+	TokenType string              `protobuf:"bytes,8,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	Cnf       *OAuth2Confirmation `protobuf:"bytes,9,opt,name=cnf,proto3" json:"cnf,omitempty"`
+	Act       *OAuth2Actor        `protobuf:"bytes,10,opt,name=act,proto3" json:"act,omitempty"`
+}
+
+func (x *OAuth2IntrospectResponse) Reset() {
+	*x = OAuth2IntrospectResponse{}
+	mi := &file_dto_oauth2_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuth2IntrospectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuth2IntrospectResponse) ProtoMessage() {}
+
+func (x *OAuth2IntrospectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dto_oauth2_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuth2IntrospectResponse.ProtoReflect.Descriptor instead.
+func (*OAuth2IntrospectResponse) Descriptor() ([]byte, []int) {
+	return file_dto_oauth2_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OAuth2IntrospectResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *OAuth2IntrospectResponse) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectResponse) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectResponse) GetSub() string {
+	if x != nil {
+		return x.Sub
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectResponse) GetExp() int64 {
+	if x != nil {
+		return x.Exp
+	}
+	return 0
+}
+
+func (x *OAuth2IntrospectResponse) GetIat() int64 {
+	if x != nil {
+		return x.Iat
+	}
+	return 0
+}
+
+func (x *OAuth2IntrospectResponse) GetJti() string {
+	if x != nil {
+		return x.Jti
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectResponse) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *OAuth2IntrospectResponse) GetCnf() *OAuth2Confirmation {
+	if x != nil {
+		return x.Cnf
+	}
+	return nil
+}
+
+func (x *OAuth2IntrospectResponse) GetAct() *OAuth2Actor {
+	if x != nil {
+		return x.Act
+	}
+	return nil
+}
+
+type OAuth2Confirmation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X5TS256 string `protobuf:"bytes,1,opt,name=x5t_s256,json=x5tS256,proto3" json:"x5t_s256,omitempty"`
+	Jkt     string `protobuf:"bytes,2,opt,name=jkt,proto3" json:"jkt,omitempty"`
+}
+
+func (x *OAuth2Confirmation) Reset() {
+	*x = OAuth2Confirmation{}
+	mi := &file_dto_oauth2_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuth2Confirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuth2Confirmation) ProtoMessage() {}
+
+func (x *OAuth2Confirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_dto_oauth2_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuth2Confirmation.ProtoReflect.Descriptor instead.
+func (*OAuth2Confirmation) Descriptor() ([]byte, []int) {
+	return file_dto_oauth2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OAuth2Confirmation) GetX5TS256() string {
+	if x != nil {
+		return x.X5TS256
+	}
+	return ""
+}
+
+func (x *OAuth2Confirmation) GetJkt() string {
+	if x != nil {
+		return x.Jkt
+	}
+	return ""
+}
+
+type OAuth2Actor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sub      string       `protobuf:"bytes,1,opt,name=sub,proto3" json:"sub,omitempty"`
+	ClientId string       `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Act      *OAuth2Actor `protobuf:"bytes,3,opt,name=act,proto3" json:"act,omitempty"`
+}
+
+func (x *OAuth2Actor) Reset() {
+	*x = OAuth2Actor{}
+	mi := &file_dto_oauth2_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OAuth2Actor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OAuth2Actor) ProtoMessage() {}
+
+func (x *OAuth2Actor) ProtoReflect() protoreflect.Message {
+	mi := &file_dto_oauth2_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OAuth2Actor.ProtoReflect.Descriptor instead.
+func (*OAuth2Actor) Descriptor() ([]byte, []int) {
+	return file_dto_oauth2_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OAuth2Actor) GetSub() string {
+	if x != nil {
+		return x.Sub
+	}
+	return ""
+}
+
+func (x *OAuth2Actor) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OAuth2Actor) GetAct() *OAuth2Actor {
+	if x != nil {
+		return x.Act
+	}
+	return nil
+}
+
+var File_dto_oauth2_proto protoreflect.FileDescriptor
+
+var file_dto_oauth2_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x64, 0x74, 0x6f, 0x2f, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x12, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x64, 0x74, 0x6f, 0x22, 0x71, 0x0a, 0x17, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32,
+	0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x23,
+	0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xb9, 0x02, 0x0a, 0x18, 0x4f, 0x41,
+	0x75, 0x74, 0x68, 0x32, 0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x73, 0x75, 0x62, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x78, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x65, 0x78, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x03, 0x69, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x74, 0x69, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x74, 0x69, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x03, 0x63, 0x6e, 0x66, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x64, 0x74, 0x6f, 0x2e, 0x4f, 0x41, 0x75, 0x74, 0x68,
+	0x32, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x63,
+	0x6e, 0x66, 0x12, 0x31, 0x0a, 0x03, 0x61, 0x63, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x64, 0x74, 0x6f, 0x2e, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x41, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x03, 0x61, 0x63, 0x74, 0x22, 0x41, 0x0a, 0x12, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x78,
+	0x35, 0x74, 0x5f, 0x73, 0x32, 0x35, 0x36, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x78,
+	0x35, 0x74, 0x53, 0x32, 0x35, 0x36, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6b, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6b, 0x74, 0x22, 0x6f, 0x0a, 0x0b, 0x4f, 0x41, 0x75, 0x74,
+	0x68, 0x32, 0x41, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x62, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x75, 0x62, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x03, 0x61, 0x63, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x64, 0x74, 0x6f, 0x2e, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x41,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x03, 0x61, 0x63, 0x74, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x79, 0x62, 0x6f, 0x72, 0x2f, 0x74, 0x6f,
+	0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2d, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x61,
+	0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
+	0x64, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_dto_oauth2_proto_rawDescOnce sync.Once
+	file_dto_oauth2_proto_rawDescData = file_dto_oauth2_proto_rawDesc
+)
+
+func file_dto_oauth2_proto_rawDescGZIP() []byte {
+	file_dto_oauth2_proto_rawDescOnce.Do(func() {
+		file_dto_oauth2_proto_rawDescData = protoimpl.X.CompressGZIP(file_dto_oauth2_proto_rawDescData)
+	})
+	return file_dto_oauth2_proto_rawDescData
+}
+
+var file_dto_oauth2_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_dto_oauth2_proto_goTypes = []any{
+	(*OAuth2IntrospectRequest)(nil),  // 0: todennus.proto.dto.OAuth2IntrospectRequest
+	(*OAuth2IntrospectResponse)(nil), // 1: todennus.proto.dto.OAuth2IntrospectResponse
+	(*OAuth2Confirmation)(nil),       // 2: todennus.proto.dto.OAuth2Confirmation
+	(*OAuth2Actor)(nil),              // 3: todennus.proto.dto.OAuth2Actor
+}
+var file_dto_oauth2_proto_depIdxs = []int32{
+	2, // 0: todennus.proto.dto.OAuth2IntrospectResponse.cnf:type_name -> todennus.proto.dto.OAuth2Confirmation
+	3, // 1: todennus.proto.dto.OAuth2IntrospectResponse.act:type_name -> todennus.proto.dto.OAuth2Actor
+	3, // 2: todennus.proto.dto.OAuth2Actor.act:type_name -> todennus.proto.dto.OAuth2Actor
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_dto_oauth2_proto_init() }
+func file_dto_oauth2_proto_init() {
+	if File_dto_oauth2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_dto_oauth2_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_dto_oauth2_proto_goTypes,
+		DependencyIndexes: file_dto_oauth2_proto_depIdxs,
+		MessageInfos:      file_dto_oauth2_proto_msgTypes,
+	}.Build()
+	File_dto_oauth2_proto = out.File
+	file_dto_oauth2_proto_rawDesc = nil
+	file_dto_oauth2_proto_goTypes = nil
+	file_dto_oauth2_proto_depIdxs = nil
+}
@@ -0,0 +1,80 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v3.6.1
+// source: oauth2.proto
+
+package service
+
+import (
+	dto "github.com/xybor/todennus-backend/adapter/grpc/gen/dto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_oauth2_proto protoreflect.FileDescriptor
+
+var file_oauth2_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16,
+	0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x10, 0x64, 0x74, 0x6f, 0x2f, 0x6f, 0x61, 0x75, 0x74,
+	0x68, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x73, 0x0a, 0x06, 0x4f, 0x41, 0x75, 0x74,
+	0x68, 0x32, 0x12, 0x69, 0x0a, 0x0a, 0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74,
+	0x12, 0x2b, 0x2e, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x64, 0x74, 0x6f, 0x2e, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x49, 0x6e, 0x74, 0x72,
+	0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e,
+	0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x64,
+	0x74, 0x6f, 0x2e, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x3c, 0x5a,
+	0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x79, 0x62, 0x6f,
+	0x72, 0x2f, 0x74, 0x6f, 0x64, 0x65, 0x6e, 0x6e, 0x75, 0x73, 0x2d, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x2f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f,
+	0x67, 0x65, 0x6e, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var file_oauth2_proto_goTypes = []any{
+	(*dto.OAuth2IntrospectRequest)(nil),  // 0: todennus.proto.dto.OAuth2IntrospectRequest
+	(*dto.OAuth2IntrospectResponse)(nil), // 1: todennus.proto.dto.OAuth2IntrospectResponse
+}
+var file_oauth2_proto_depIdxs = []int32{
+	0, // 0: todennus.proto.service.OAuth2.Introspect:input_type -> todennus.proto.dto.OAuth2IntrospectRequest
+	1, // 1: todennus.proto.service.OAuth2.Introspect:output_type -> todennus.proto.dto.OAuth2IntrospectResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_oauth2_proto_init() }
+func file_oauth2_proto_init() {
+	if File_oauth2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_oauth2_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_oauth2_proto_goTypes,
+		DependencyIndexes: file_oauth2_proto_depIdxs,
+	}.Build()
+	File_oauth2_proto = out.File
+	file_oauth2_proto_rawDesc = nil
+	file_oauth2_proto_goTypes = nil
+	file_oauth2_proto_depIdxs = nil
+}
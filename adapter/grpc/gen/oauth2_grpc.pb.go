@@ -0,0 +1,122 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.6.1
+// source: oauth2.proto
+
+package service
+
+import (
+	context "context"
+	dto "github.com/xybor/todennus-backend/adapter/grpc/gen/dto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OAuth2_Introspect_FullMethodName = "/todennus.proto.service.OAuth2/Introspect"
+)
+
+// OAuth2Client is the client API for OAuth2 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OAuth2Client interface {
+	Introspect(ctx context.Context, in *dto.OAuth2IntrospectRequest, opts ...grpc.CallOption) (*dto.OAuth2IntrospectResponse, error)
+}
+
+type oAuth2Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOAuth2Client(cc grpc.ClientConnInterface) OAuth2Client {
+	return &oAuth2Client{cc}
+}
+
+func (c *oAuth2Client) Introspect(ctx context.Context, in *dto.OAuth2IntrospectRequest, opts ...grpc.CallOption) (*dto.OAuth2IntrospectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(dto.OAuth2IntrospectResponse)
+	err := c.cc.Invoke(ctx, OAuth2_Introspect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OAuth2Server is the server API for OAuth2 service.
+// All implementations must embed UnimplementedOAuth2Server
+// for forward compatibility.
+type OAuth2Server interface {
+	Introspect(context.Context, *dto.OAuth2IntrospectRequest) (*dto.OAuth2IntrospectResponse, error)
+	mustEmbedUnimplementedOAuth2Server()
+}
+
+// UnimplementedOAuth2Server must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOAuth2Server struct{}
+
+func (UnimplementedOAuth2Server) Introspect(context.Context, *dto.OAuth2IntrospectRequest) (*dto.OAuth2IntrospectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Introspect not implemented")
+}
+func (UnimplementedOAuth2Server) mustEmbedUnimplementedOAuth2Server() {}
+func (UnimplementedOAuth2Server) testEmbeddedByValue()                {}
+
+// UnsafeOAuth2Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OAuth2Server will
+// result in compilation errors.
+type UnsafeOAuth2Server interface {
+	mustEmbedUnimplementedOAuth2Server()
+}
+
+func RegisterOAuth2Server(s grpc.ServiceRegistrar, srv OAuth2Server) {
+	// If the following call pancis, it indicates UnimplementedOAuth2Server was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OAuth2_ServiceDesc, srv)
+}
+
+func _OAuth2_Introspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(dto.OAuth2IntrospectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuth2Server).Introspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OAuth2_Introspect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuth2Server).Introspect(ctx, req.(*dto.OAuth2IntrospectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OAuth2_ServiceDesc is the grpc.ServiceDesc for OAuth2 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OAuth2_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todennus.proto.service.OAuth2",
+	HandlerType: (*OAuth2Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Introspect",
+			Handler:    _OAuth2_Introspect_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "oauth2.proto",
+}
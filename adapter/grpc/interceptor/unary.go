@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/xybor/todennus-backend/adapter/abstraction"
 	"github.com/xybor/todennus-backend/adapter/common"
 	"github.com/xybor/todennus-backend/usecase"
 	"github.com/xybor/todennus-backend/wiring"
@@ -15,7 +16,7 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
-func UnaryInterceptor(config *config.Config, infras *wiring.Infras) grpc.UnaryServerInterceptor {
+func UnaryInterceptor(config *config.Config, infras *wiring.Infras, usecases *wiring.Usecases) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		ctx = wiring.WithInfras(ctx, infras)
 		ctx = withRequestID(ctx)
@@ -24,7 +25,7 @@ func UnaryInterceptor(config *config.Config, infras *wiring.Infras) grpc.UnarySe
 		ctx, cancel := withTimeout(ctx, config)
 		defer cancel()
 
-		ctx = withAuthenticate(ctx, infras.TokenEngine)
+		ctx = withAuthenticate(ctx, infras.TokenEngine, usecases.OAuth2Usecase)
 
 		start := time.Now()
 		resp, err := handler(ctx, req)
@@ -47,7 +48,7 @@ func withTimeout(ctx context.Context, config *config.Config) (context.Context, c
 	return context.WithTimeoutCause(ctx, timeout, usecase.ErrServerTimeout)
 }
 
-func withAuthenticate(ctx context.Context, engine token.Engine) context.Context {
+func withAuthenticate(ctx context.Context, engine token.Engine, oauth2Usecase abstraction.OAuth2Usecase) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		xcontext.Logger(ctx).Debug("not-found-metadata")
@@ -60,5 +61,5 @@ func withAuthenticate(ctx context.Context, engine token.Engine) context.Context
 		return ctx
 	}
 
-	return common.WithAuthenticate(ctx, authorization[0], engine)
+	return common.WithAuthenticate(ctx, authorization[0], nil, engine, oauth2Usecase)
 }
@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/xybor/todennus-backend/adapter/abstraction"
+	"github.com/xybor/todennus-backend/adapter/grpc/conversion"
+	service "github.com/xybor/todennus-backend/adapter/grpc/gen"
+	pbdto "github.com/xybor/todennus-backend/adapter/grpc/gen/dto"
+	"github.com/xybor/todennus-backend/usecase"
+	"google.golang.org/grpc/codes"
+)
+
+var _ service.OAuth2Server = (*OAuth2Server)(nil)
+
+type OAuth2Server struct {
+	service.UnimplementedOAuth2Server
+
+	oauth2Usecase abstraction.OAuth2Usecase
+}
+
+func NewOAuth2Server(oauth2Usecase abstraction.OAuth2Usecase) *OAuth2Server {
+	return &OAuth2Server{
+		oauth2Usecase: oauth2Usecase,
+	}
+}
+
+func (s *OAuth2Server) Introspect(ctx context.Context, req *pbdto.OAuth2IntrospectRequest) (*pbdto.OAuth2IntrospectResponse, error) {
+	ucreq := conversion.NewUsecaseOAuth2IntrospectRequest(req)
+	resp, err := s.oauth2Usecase.Introspect(ctx, ucreq)
+
+	return conversion.NewResponseHandler(ctx, conversion.NewPbOAuth2IntrospectResponse(resp), err).
+		Map(codes.InvalidArgument, usecase.ErrRequestInvalid).
+		Map(codes.Unauthenticated, usecase.ErrClientInvalid).Finalize(ctx)
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"net/http"
+	"strings"
 
 	_ "github.com/xybor/todennus-backend/docs"
 
@@ -20,7 +21,12 @@ func App(
 	config *config.Config,
 	infras *wiring.Infras,
 	usecases *wiring.Usecases,
+	certificateSource middleware.ClientCertificateSource,
 ) chi.Router {
+	// All urls exposed to the clients are built from the issuer, never from
+	// the Host header of the request.
+	baseURL := strings.TrimSuffix(config.Variable.Authentication.TokenIssuer, "/")
+
 	r := chi.NewRouter()
 
 	r.Use(builtinMiddleware.Recoverer)
@@ -29,14 +35,16 @@ func App(
 	r.Use(middleware.WithInfras(infras))
 	r.Use(middleware.Timer(config))
 	r.Use(middleware.Timeout(config))
-	r.Use(middleware.Authentication(infras.TokenEngine))
+	r.Use(middleware.WithClientCertificate(certificateSource))
+	r.Use(middleware.Authentication(baseURL, infras.TokenEngine, usecases.OAuth2Usecase))
 	r.Use(middleware.WithSession(infras.SessionManager))
 
 	r.Get("/specs/*", httpSwagger.WrapHandler)
 
 	userAdapter := NewUserAdapter(usecases.UserUsecase)
-	oauth2FlowAdapter := NewOAuth2Adapter(usecases.OAuth2Usecase)
-	oauth2ClientAdapter := NewOAuth2ClientAdapter(usecases.OAuth2ClientUsecase)
+	oauth2FlowAdapter := NewOAuth2Adapter(baseURL, usecases.OAuth2Usecase)
+	oauth2ClientAdapter := NewOAuth2ClientAdapter(baseURL, usecases.OAuth2ClientUsecase)
+	oidcAdapter := NewOIDCAdapter(baseURL, usecases.OIDCUsecase)
 
 	r.Get("/session/update", oauth2FlowAdapter.SessionUpdate())
 	r.Post("/auth/callback", oauth2FlowAdapter.AuthenticationCallback())
@@ -44,6 +52,10 @@ func App(
 	r.Route("/users", userAdapter.Router)
 	r.Route("/oauth2", oauth2FlowAdapter.OAuth2Router)
 	r.Route("/oauth2_clients", oauth2ClientAdapter.Router)
+	r.Route("/oauth2/register", oauth2ClientAdapter.RegistrationRouter)
+	r.Route("/.well-known", oidcAdapter.WellKnownRouter)
+	r.Get("/oauth2/userinfo", middleware.RequireAuthentication(oidcAdapter.GetUserInfo()))
+	r.Post("/oauth2/userinfo", middleware.RequireAuthentication(oidcAdapter.GetUserInfo()))
 
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) })
 
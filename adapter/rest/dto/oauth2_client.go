@@ -1,20 +1,70 @@
 package dto
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/xybor-x/snowflake"
 	"github.com/xybor/todennus-backend/adapter/rest/dto/resource"
 	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/x/xhttp"
 )
 
 type OAuth2ClientCreateRequest struct {
 	Name           string `json:"name" example:"Example Client"`
 	IsConfidential bool   `json:"is_confidential" example:"true"`
+	RedirectURIs   string `json:"redirect_uris" example:"https://example.com/callback http://127.0.0.1/callback"`
+
+	// AllowImplicitFlow lets the client request access tokens directly from
+	// the authorization endpoint. Only enable it for legacy browser apps.
+	AllowImplicitFlow bool `json:"allow_implicit_flow" example:"false"`
+
+	// RequirePAR only accepts authorization requests which are pushed to the
+	// pushed authorization request endpoint beforehand.
+	RequirePAR bool `json:"require_pushed_authorization_requests" example:"false"`
+
+	// ForbidPlainPKCE only accepts the S256 code challenge method.
+	ForbidPlainPKCE bool `json:"forbid_plain_code_challenge" example:"true"`
+
+	// AuthMethod restricts how the client authenticates itself: none,
+	// client_secret_basic, client_secret_post, private_key_jwt (requires
+	// jwks), tls_client_auth (requires tls_client_auth_subject_dn), or
+	// self_signed_tls_client_auth (requires jwks). Leave it empty to allow
+	// both client_secret_basic and client_secret_post.
+	AuthMethod string `json:"token_endpoint_auth_method" example:"client_secret_basic"`
+
+	// JWKS is the JSON-encoded key set whose keys sign the request objects
+	// of the client.
+	JWKS string `json:"jwks" example:"{\"keys\":[{\"kty\":\"RSA\",\"kid\":\"key-1\",\"n\":\"0vx7ag...\",\"e\":\"AQAB\"}]}"`
+
+	// TLSSubjectDN is the expected subject distinguished name of the client
+	// certificate, in the format of RFC 2253.
+	TLSSubjectDN string `json:"tls_client_auth_subject_dn" example:"CN=example-client,O=Example"`
+
+	// BackchannelMode lets a confidential client initiate backchannel
+	// authentication, the tokens are delivered in poll or ping mode.
+	BackchannelMode string `json:"backchannel_token_delivery_mode" example:"poll"`
+
+	// BackchannelNotify is the https endpoint of the client which is
+	// notified when the backchannel authentication completes in ping mode.
+	BackchannelNotify string `json:"backchannel_client_notification_endpoint" example:"https://example.com/cb"`
 }
 
 func (req OAuth2ClientCreateRequest) To() *dto.OAuth2ClientCreateRequest {
 	return &dto.OAuth2ClientCreateRequest{
-		Name:           req.Name,
-		IsConfidential: req.IsConfidential,
+		Name:              req.Name,
+		IsConfidential:    req.IsConfidential,
+		RedirectURIs:      strings.Fields(req.RedirectURIs),
+		AllowImplicitFlow: req.AllowImplicitFlow,
+		RequirePAR:        req.RequirePAR,
+		ForbidPlainPKCE:   req.ForbidPlainPKCE,
+		AuthMethod:        req.AuthMethod,
+		JWKS:              req.JWKS,
+		TLSSubjectDN:      req.TLSSubjectDN,
+		BackchannelMode:   req.BackchannelMode,
+		BackchannelNotify: req.BackchannelNotify,
 	}
 }
 
@@ -38,13 +88,16 @@ type OAuth2ClientCreateFirstRequest struct {
 	Username string `json:"username" example:"huykingsofm"`
 	Password string `json:"password" example:"s3Cr3tP@ssW0rD"`
 	Name     string `json:"name" example:"First Client"`
+
+	RedirectURIs string `json:"redirect_uris" example:"https://example.com/callback"`
 }
 
 func (req *OAuth2ClientCreateFirstRequest) To() *dto.OAuth2ClientCreateFirstRequest {
 	return &dto.OAuth2ClientCreateFirstRequest{
-		Username: req.Username,
-		Password: req.Password,
-		Name:     req.Name,
+		Username:     req.Username,
+		Password:     req.Password,
+		Name:         req.Name,
+		RedirectURIs: strings.Fields(req.RedirectURIs),
 	}
 }
 
@@ -92,3 +145,359 @@ func NewOAuth2ClientGetResponse(resp *dto.OAuth2ClientGetResponse) *OAuth2Client
 		OAuth2Client: resource.NewOAuth2Client(resp.Client),
 	}
 }
+
+type OAuth2ClientUpdateRequest struct {
+	ClientID string `param:"client_id"`
+
+	// Name is the new name of the client. Leave it empty to keep the current
+	// name.
+	Name string `json:"name" example:"Example Client"`
+
+	// AllowedScope is a space-separated list of scopes which the client can
+	// request. Leave it empty to keep the current allowed scope.
+	AllowedScope string `json:"allowed_scope" example:"read:user"`
+
+	// RedirectURIs is a space-separated list. Leave it empty to keep the
+	// current redirect uris.
+	RedirectURIs string `json:"redirect_uris" example:"https://example.com/callback http://127.0.0.1/callback"`
+
+	// AllowedResources is a space-separated list. Leave it empty to keep the
+	// current allowed resources.
+	AllowedResources string `json:"allowed_resources" example:"https://api.example.com"`
+
+	// JWKS is the JSON-encoded key set. Leave it empty to keep the current
+	// keys.
+	JWKS string `json:"jwks" example:"{\"keys\":[{\"kty\":\"RSA\",\"kid\":\"key-1\",\"n\":\"0vx7ag...\",\"e\":\"AQAB\"}]}"`
+
+	// TLSSubjectDN is the expected subject distinguished name of the client
+	// certificate. Leave it empty to keep the current one.
+	TLSSubjectDN string `json:"tls_client_auth_subject_dn" example:"CN=example-client,O=Example"`
+
+	// BackchannelMode is poll or ping. Leave it empty to keep the current
+	// backchannel token delivery.
+	BackchannelMode string `json:"backchannel_token_delivery_mode" example:"ping"`
+
+	// BackchannelNotify is required in ping mode.
+	BackchannelNotify string `json:"backchannel_client_notification_endpoint" example:"https://example.com/cb"`
+}
+
+func (req *OAuth2ClientUpdateRequest) To() *dto.OAuth2ClientUpdateRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	var redirectURIs []string
+	if req.RedirectURIs != "" {
+		redirectURIs = strings.Fields(req.RedirectURIs)
+	}
+
+	var allowedResources []string
+	if req.AllowedResources != "" {
+		allowedResources = strings.Fields(req.AllowedResources)
+	}
+
+	return &dto.OAuth2ClientUpdateRequest{
+		ClientID:         clientID,
+		Name:             req.Name,
+		AllowedScope:     req.AllowedScope,
+		RedirectURIs:     redirectURIs,
+		AllowedResources: allowedResources,
+		JWKS:             req.JWKS,
+		TLSSubjectDN:     req.TLSSubjectDN,
+
+		BackchannelMode:   req.BackchannelMode,
+		BackchannelNotify: req.BackchannelNotify,
+	}
+}
+
+type OAuth2ClientUpdateResponse struct {
+	*resource.OAuth2Client
+}
+
+func NewOAuth2ClientUpdateResponse(resp *dto.OAuth2ClientUpdateResponse) *OAuth2ClientUpdateResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2ClientUpdateResponse{
+		OAuth2Client: resource.NewOAuth2Client(resp.Client),
+	}
+}
+
+type OAuth2ClientListRequest struct {
+	All     bool `query:"all"`
+	Page    int  `query:"page"`
+	PerPage int  `query:"per_page"`
+}
+
+func (req *OAuth2ClientListRequest) To() *dto.OAuth2ClientListRequest {
+	return &dto.OAuth2ClientListRequest{
+		All:     req.All,
+		Page:    req.Page,
+		PerPage: req.PerPage,
+	}
+}
+
+type OAuth2ClientListResponse struct {
+	Clients []*resource.OAuth2Client `json:"clients"`
+}
+
+func NewOAuth2ClientListResponse(resp *dto.OAuth2ClientListResponse) *OAuth2ClientListResponse {
+	if resp == nil {
+		return nil
+	}
+
+	clients := make([]*resource.OAuth2Client, 0, len(resp.Clients))
+	for _, client := range resp.Clients {
+		clients = append(clients, resource.NewOAuth2Client(client))
+	}
+
+	return &OAuth2ClientListResponse{Clients: clients}
+}
+
+type OAuth2ClientDisableRequest struct {
+	ClientID string
+}
+
+func (req *OAuth2ClientDisableRequest) To(isDisabled bool) *dto.OAuth2ClientDisableRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	return &dto.OAuth2ClientDisableRequest{
+		ClientID:   clientID,
+		IsDisabled: isDisabled,
+	}
+}
+
+type OAuth2ClientDisableResponse struct {
+	*resource.OAuth2Client
+}
+
+func NewOAuth2ClientDisableResponse(resp *dto.OAuth2ClientDisableResponse) *OAuth2ClientDisableResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2ClientDisableResponse{
+		OAuth2Client: resource.NewOAuth2Client(resp.Client),
+	}
+}
+
+type OAuth2ClientDeleteRequest struct {
+	ClientID string
+}
+
+func (req *OAuth2ClientDeleteRequest) To() *dto.OAuth2ClientDeleteRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	return &dto.OAuth2ClientDeleteRequest{
+		ClientID: clientID,
+	}
+}
+
+type OAuth2ClientTransferRequest struct {
+	ClientID string `param:"client_id"`
+	OwnerID  string `json:"owner_id" example:"330559330522759168"`
+}
+
+func (req *OAuth2ClientTransferRequest) To() *dto.OAuth2ClientTransferRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	ownerID, err := snowflake.ParseString(req.OwnerID)
+	if err != nil {
+		ownerID = 0
+	}
+
+	return &dto.OAuth2ClientTransferRequest{
+		ClientID: clientID,
+		OwnerID:  ownerID,
+	}
+}
+
+type OAuth2ClientTransferResponse struct {
+	*resource.OAuth2Client
+}
+
+func NewOAuth2ClientTransferResponse(resp *dto.OAuth2ClientTransferResponse) *OAuth2ClientTransferResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2ClientTransferResponse{
+		OAuth2Client: resource.NewOAuth2Client(resp.Client),
+	}
+}
+
+// OAuth2ClientMetadata is the client metadata of the dynamic client
+// registration (RFC 7591, section 2).
+type OAuth2ClientMetadata struct {
+	Name          string          `json:"client_name" example:"Example Client"`
+	RedirectURIs  []string        `json:"redirect_uris" example:"https://example.com/callback"`
+	GrantTypes    []string        `json:"grant_types" example:"authorization_code,refresh_token"`
+	ResponseTypes []string        `json:"response_types" example:"code"`
+	AuthMethod    string          `json:"token_endpoint_auth_method" example:"client_secret_basic"`
+	JWKS          json.RawMessage `json:"jwks,omitempty" swaggertype:"object"`
+	LogoURI       string          `json:"logo_uri,omitempty" example:"https://example.com/logo.png"`
+	Scope         string          `json:"scope,omitempty" example:"read:user"`
+}
+
+// NewOAuth2ClientMetadata decodes the client metadata from the JSON body,
+// which can not be parsed by xhttp because of the arrays.
+func NewOAuth2ClientMetadata(r *http.Request) (*dto.OAuth2ClientMetadata, error) {
+	metadata := OAuth2ClientMetadata{}
+	if err := json.NewDecoder(r.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("%winvalid client metadata: %s", xhttp.ErrHTTPBadRequest, err.Error())
+	}
+
+	jwks := ""
+	if len(metadata.JWKS) > 0 && string(metadata.JWKS) != "null" {
+		jwks = string(metadata.JWKS)
+	}
+
+	return &dto.OAuth2ClientMetadata{
+		Name:          metadata.Name,
+		RedirectURIs:  metadata.RedirectURIs,
+		GrantTypes:    metadata.GrantTypes,
+		ResponseTypes: metadata.ResponseTypes,
+		AuthMethod:    metadata.AuthMethod,
+		JWKS:          jwks,
+		LogoURI:       metadata.LogoURI,
+		Scope:         metadata.Scope,
+	}, nil
+}
+
+func newOAuth2ClientMetadata(metadata *dto.OAuth2ClientMetadata) OAuth2ClientMetadata {
+	var jwks json.RawMessage
+	if metadata.JWKS != "" {
+		jwks = json.RawMessage(metadata.JWKS)
+	}
+
+	return OAuth2ClientMetadata{
+		Name:          metadata.Name,
+		RedirectURIs:  metadata.RedirectURIs,
+		GrantTypes:    metadata.GrantTypes,
+		ResponseTypes: metadata.ResponseTypes,
+		AuthMethod:    metadata.AuthMethod,
+		JWKS:          jwks,
+		LogoURI:       metadata.LogoURI,
+		Scope:         metadata.Scope,
+	}
+}
+
+// RegistrationAccessToken reads the bearer registration access token from
+// the Authorization header (RFC 7592, section 3).
+func RegistrationAccessToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+func NewOAuth2ClientRegisterRequest(r *http.Request) (*dto.OAuth2ClientRegisterRequest, error) {
+	metadata, err := NewOAuth2ClientMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuth2ClientRegisterRequest{Metadata: metadata}, nil
+}
+
+// OAuth2ClientRegistrationResponse is the client information response (RFC
+// 7591, section 3.2.1).
+type OAuth2ClientRegistrationResponse struct {
+	ClientID                string `json:"client_id" example:"332974701238012989"`
+	ClientSecret            string `json:"client_secret,omitempty" example:"ElBacv..."`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at" example:"1729512000"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty" example:"0"`
+	RegistrationAccessToken string `json:"registration_access_token" example:"kXbOnG..."`
+	RegistrationClientURI   string `json:"registration_client_uri" example:"https://todennus.com/oauth2/register/332974701238012989"`
+	OAuth2ClientMetadata
+}
+
+func NewOAuth2ClientRegistrationResponse(
+	baseURL string,
+	resp *dto.OAuth2ClientRegistrationResponse,
+) *OAuth2ClientRegistrationResponse {
+	if resp == nil {
+		return nil
+	}
+
+	// A zero expiration time means the secret never expires.
+	var secretExpiresAt *int64
+	if resp.ClientSecret != "" {
+		secretExpiresAt = new(int64)
+	}
+
+	return &OAuth2ClientRegistrationResponse{
+		ClientID:                resp.ClientID.String(),
+		ClientSecret:            resp.ClientSecret,
+		ClientIDIssuedAt:        resp.IssuedAt.Unix(),
+		ClientSecretExpiresAt:   secretExpiresAt,
+		RegistrationAccessToken: resp.RegistrationAccessToken,
+		RegistrationClientURI:   baseURL + "/oauth2/register/" + resp.ClientID.String(),
+		OAuth2ClientMetadata:    newOAuth2ClientMetadata(resp.Metadata),
+	}
+}
+
+type OAuth2ClientReadRegistrationRequest struct {
+	ClientID string `param:"client_id"`
+}
+
+func (req *OAuth2ClientReadRegistrationRequest) To(r *http.Request) *dto.OAuth2ClientReadRegistrationRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	return &dto.OAuth2ClientReadRegistrationRequest{
+		ClientID:                clientID,
+		RegistrationAccessToken: RegistrationAccessToken(r),
+	}
+}
+
+type OAuth2ClientUpdateRegistrationRequest struct {
+	ClientID string `param:"client_id"`
+}
+
+func (req *OAuth2ClientUpdateRegistrationRequest) To(
+	r *http.Request,
+	metadata *dto.OAuth2ClientMetadata,
+) *dto.OAuth2ClientUpdateRegistrationRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	return &dto.OAuth2ClientUpdateRegistrationRequest{
+		ClientID:                clientID,
+		RegistrationAccessToken: RegistrationAccessToken(r),
+		Metadata:                metadata,
+	}
+}
+
+type OAuth2ClientDeleteRegistrationRequest struct {
+	ClientID string
+}
+
+func (req *OAuth2ClientDeleteRegistrationRequest) To(r *http.Request) *dto.OAuth2ClientDeleteRegistrationRequest {
+	clientID, err := snowflake.ParseString(req.ClientID)
+	if err != nil {
+		clientID = 0
+	}
+
+	return &dto.OAuth2ClientDeleteRegistrationRequest{
+		ClientID:                clientID,
+		RegistrationAccessToken: RegistrationAccessToken(r),
+	}
+}
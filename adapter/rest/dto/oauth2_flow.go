@@ -2,8 +2,11 @@ package dto
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -12,16 +15,157 @@ import (
 	"github.com/xybor/todennus-backend/adapter/rest/standard"
 	"github.com/xybor/todennus-backend/usecase"
 	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/todennus-backend/usecase/reqctx"
 	"github.com/xybor/x/xerror"
 	"github.com/xybor/x/xhttp"
 )
 
+// ClientAssertionTypeJWTBearer is the only supported client assertion type
+// (RFC 7523).
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// OAuth2ClientAuthenticationRequest is the client credentials in the form body.
+// It is parsed separately from the request of each endpoint since the
+// credentials can also be sent in the Authorization header.
+type OAuth2ClientAuthenticationRequest struct {
+	ClientID            int64  `form:"client_id"`
+	ClientSecret        string `form:"client_secret"`
+	ClientAssertionType string `form:"client_assertion_type"`
+	ClientAssertion     string `form:"client_assertion"`
+}
+
+// NewOAuth2ClientAuthentication reads the client credentials from the
+// Authorization header (client_secret_basic), the client assertion
+// (private_key_jwt), or the form body (client_secret_post). The client can use
+// only one of them. The client certificate (tls_client_auth and
+// self_signed_tls_client_auth) is attached if there is any.
+func NewOAuth2ClientAuthentication(r *http.Request) (*dto.OAuth2ClientAuthentication, error) {
+	req, err := xhttp.ParseHTTPRequest[OAuth2ClientAuthenticationRequest](r)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &dto.OAuth2ClientAuthentication{
+		ClientID:          snowflake.ID(req.ClientID),
+		ClientSecret:      req.ClientSecret,
+		ClientAssertion:   req.ClientAssertion,
+		ClientCertificate: reqctx.ClientCertificate(r.Context()),
+	}
+
+	username, password, hasBasic := r.BasicAuth()
+	hasAssertion := req.ClientAssertionType != "" || req.ClientAssertion != ""
+	if (hasBasic && (req.ClientSecret != "" || hasAssertion)) || (req.ClientSecret != "" && hasAssertion) {
+		return nil, xerror.Enrich(usecase.ErrRequestInvalid, "must not use more than one client authentication method")
+	}
+
+	switch {
+	case hasBasic:
+		// The credentials are form-urlencoded before being encoded in the
+		// header (RFC 6749 Section 2.3.1).
+		username, err := url.QueryUnescape(username)
+		if err != nil {
+			return nil, xerror.Enrich(usecase.ErrRequestInvalid, "invalid client id in authorization header")
+		}
+
+		password, err := url.QueryUnescape(password)
+		if err != nil {
+			return nil, xerror.Enrich(usecase.ErrRequestInvalid, "invalid client secret in authorization header")
+		}
+
+		clientID, err := snowflake.ParseString(username)
+		if err != nil {
+			return nil, xerror.Enrich(usecase.ErrRequestInvalid, "invalid client id in authorization header")
+		}
+
+		if req.ClientID != 0 && snowflake.ID(req.ClientID) != clientID {
+			return nil, xerror.Enrich(usecase.ErrRequestInvalid, "mismatched client id")
+		}
+
+		auth.ClientID = clientID
+		auth.ClientSecret = password
+		auth.AuthMethod = usecase.TokenEndpointAuthMethodClientSecretBasic
+
+	case hasAssertion:
+		if req.ClientAssertionType != ClientAssertionTypeJWTBearer {
+			return nil, xerror.Enrich(usecase.ErrRequestInvalid, "not support client assertion type %s", req.ClientAssertionType)
+		}
+
+		// The client id is optional since it is the subject of the assertion.
+		if auth.ClientID == 0 {
+			auth.ClientID = clientIDFromAssertion(req.ClientAssertion)
+		}
+
+		auth.AuthMethod = usecase.TokenEndpointAuthMethodPrivateKeyJWT
+
+	case req.ClientSecret != "":
+		auth.AuthMethod = usecase.TokenEndpointAuthMethodClientSecretPost
+
+	default:
+		auth.AuthMethod = usecase.TokenEndpointAuthMethodNone
+	}
+
+	return auth, nil
+}
+
+type OAuth2CreateDPoPNonceRequest struct{}
+
+func (req OAuth2CreateDPoPNonceRequest) To() *dto.OAuth2CreateDPoPNonceRequest {
+	return &dto.OAuth2CreateDPoPNonceRequest{}
+}
+
+// NewOAuth2DPoP reads the DPoP proof from the header, along with the method
+// and the uri of the request which the proof must be bound to. The uri is
+// built from the configured base url, the Host header of the request is
+// controlled by the client.
+func NewOAuth2DPoP(r *http.Request, baseURL string) (*dto.OAuth2DPoP, error) {
+	proofs := r.Header.Values("DPoP")
+	if len(proofs) > 1 {
+		return nil, xerror.Enrich(usecase.ErrRequestInvalid, "must not send more than one dpop proof")
+	}
+
+	dpop := &dto.OAuth2DPoP{
+		Method: r.Method,
+		URI:    baseURL + r.URL.Path,
+	}
+
+	if len(proofs) == 1 {
+		dpop.Proof = proofs[0]
+	}
+
+	return dpop, nil
+}
+
+// clientIDFromAssertion reads the subject of the assertion without verifying
+// it, the usecase verifies the assertion later.
+func clientIDFromAssertion(assertion string) snowflake.ID {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0
+	}
+
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return 0
+	}
+
+	clientID, err := snowflake.ParseString(claims.Subject)
+	if err != nil {
+		return 0
+	}
+
+	return clientID
+}
+
 type OAuth2TokenRequest struct {
 	GrantType string `form:"grant_type"`
 
-	ClientID     int64  `form:"client_id"`
-	ClientSecret string `form:"client_secret"`
-
 	// Authorization Code Flow
 	Code         string `form:"code"`
 	RedirectURI  string `form:"redirect_uri"`
@@ -32,16 +176,36 @@ type OAuth2TokenRequest struct {
 	Password string `form:"password"`
 	Scope    string `form:"scope"`
 
+	// RFC 8707 Resource Indicator
+	Resource string `form:"resource"`
+
 	// Refresh Token Flow
 	RefreshToken string `form:"refresh_token"`
+
+	// Device Flow
+	DeviceCode string `form:"device_code"`
+
+	// Client Initiated Backchannel Authentication
+	AuthReqID string `form:"auth_req_id"`
+
+	// JWT Bearer Grant (RFC 7523)
+	Assertion string `form:"assertion"`
+
+	// Token Exchange (RFC 8693)
+	SubjectToken       string `form:"subject_token"`
+	SubjectTokenType   string `form:"subject_token_type"`
+	ActorToken         string `form:"actor_token"`
+	ActorTokenType     string `form:"actor_token_type"`
+	RequestedTokenType string `form:"requested_token_type"`
+	Audience           string `form:"audience"`
 }
 
-func (req OAuth2TokenRequest) To() *dto.OAuth2TokenRequest {
+func (req OAuth2TokenRequest) To(auth *dto.OAuth2ClientAuthentication, dpop *dto.OAuth2DPoP) *dto.OAuth2TokenRequest {
 	return &dto.OAuth2TokenRequest{
 		GrantType: req.GrantType,
 
-		ClientID:     snowflake.ID(req.ClientID),
-		ClientSecret: req.ClientSecret,
+		OAuth2ClientAuthentication: *auth,
+		DPoP:                       *dpop,
 
 		Code:         req.Code,
 		RedirectURI:  req.RedirectURI,
@@ -51,7 +215,21 @@ func (req OAuth2TokenRequest) To() *dto.OAuth2TokenRequest {
 		Password: req.Password,
 		Scope:    req.Scope,
 
+		Resource: req.Resource,
+
 		RefreshToken: req.RefreshToken,
+
+		DeviceCode: req.DeviceCode,
+		AuthReqID:  req.AuthReqID,
+
+		Assertion: req.Assertion,
+
+		SubjectToken:       req.SubjectToken,
+		SubjectTokenType:   req.SubjectTokenType,
+		ActorToken:         req.ActorToken,
+		ActorTokenType:     req.ActorTokenType,
+		RequestedTokenType: req.RequestedTokenType,
+		Audience:           req.Audience,
 	}
 }
 
@@ -61,6 +239,9 @@ type OAuth2TokenResponse struct {
 	ExpiresIn    int    `json:"expires_in"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+
+	IssuedTokenType string `json:"issued_token_type,omitempty" example:"urn:ietf:params:oauth:token-type:access_token"`
 }
 
 func NewOAuth2TokenResponse(resp *dto.OAuth2TokenResponse) *OAuth2TokenResponse {
@@ -74,11 +255,15 @@ func NewOAuth2TokenResponse(resp *dto.OAuth2TokenResponse) *OAuth2TokenResponse
 		ExpiresIn:    resp.ExpiresIn,
 		RefreshToken: resp.RefreshToken,
 		Scope:        resp.Scope,
+		IDToken:      resp.IDToken,
+
+		IssuedTokenType: resp.IssuedTokenType,
 	}
 }
 
 type OAuth2AuthorizeRequest struct {
 	ResponseType string `query:"response_type"`
+	ResponseMode string `query:"response_mode"`
 	ClientID     int64  `query:"client_id"`
 	RedirectURI  string `query:"redirect_uri"`
 	Scope        string `query:"scope"`
@@ -87,95 +272,302 @@ type OAuth2AuthorizeRequest struct {
 	// For PKCE
 	CodeChallenge       string `query:"code_challenge"`
 	CodeChallengeMethod string `query:"code_challenge_method"`
+
+	// For OpenID Connect
+	Nonce string `query:"nonce"`
+
+	// For Pushed Authorization Requests
+	RequestURI string `query:"request_uri"`
+
+	// For JWT-Secured Authorization Requests
+	Request string `query:"request"`
 }
 
 func (req OAuth2AuthorizeRequest) To() *dto.OAuth2AuthorizeRequest {
 	return &dto.OAuth2AuthorizeRequest{
 		ResponseType:        req.ResponseType,
+		ResponseMode:        req.ResponseMode,
 		ClientID:            snowflake.ID(req.ClientID),
 		RedirectURI:         req.RedirectURI,
 		Scope:               req.Scope,
 		State:               req.State,
 		CodeChallenge:       req.CodeChallenge,
 		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+		RequestURI:          req.RequestURI,
+		Request:             req.Request,
 	}
 }
 
+// NewOAuth2AuthorizeRequestFromResolved replaces the query of the
+// authorization endpoint with the parameters of the pushed authorization
+// request or the request object.
+func NewOAuth2AuthorizeRequestFromResolved(resp *dto.OAuth2ResolveAuthorizationResponse) *OAuth2AuthorizeRequest {
+	return &OAuth2AuthorizeRequest{
+		ResponseType:        resp.ResponseType,
+		ResponseMode:        resp.ResponseMode,
+		ClientID:            resp.ClientID.Int64(),
+		RedirectURI:         resp.RedirectURI,
+		Scope:               resp.Scope,
+		State:               resp.State,
+		CodeChallenge:       resp.CodeChallenge,
+		CodeChallengeMethod: resp.CodeChallengeMethod,
+		Nonce:               resp.Nonce,
+		RequestURI:          resp.RequestURI,
+		Request:             resp.Request,
+	}
+}
+
+// IsFormPost returns true if the response must be posted to the redirect uri
+// by an auto-submitting form rather than a redirection.
+func (req OAuth2AuthorizeRequest) IsFormPost() bool {
+	return req.ResponseMode == usecase.ResponseModeFormPost
+}
+
+// responseMode returns the requested response mode. Without it, the tokens
+// are returned in the fragment and the code is returned in the query.
+func (req OAuth2AuthorizeRequest) responseMode() string {
+	switch req.ResponseMode {
+	case usecase.ResponseModeQuery, usecase.ResponseModeFragment, usecase.ResponseModeFormPost:
+		return req.ResponseMode
+	}
+
+	for _, value := range strings.Fields(req.ResponseType) {
+		if value == usecase.ResponseTypeToken || value == usecase.ResponseTypeIDToken {
+			return usecase.ResponseModeFragment
+		}
+	}
+
+	return usecase.ResponseModeQuery
+}
+
 func NewOAuth2AuthorizeRedirectURI(
 	req *OAuth2AuthorizeRequest,
 	resp *dto.OAuth2AuthorizeResponse,
 ) (string, error) {
 	if resp.IdpURL != "" {
-		u, err := url.Parse(resp.IdpURL)
-		if err != nil {
-			return "", usecase.ErrServer.Hide(err, "invalid-idp-url", "url", resp.IdpURL)
-		}
+		return newIdPRedirectURI(resp.IdpURL, resp.AuthorizationID)
+	}
 
-		q := u.Query()
-		q.Set("authorization_id", resp.AuthorizationID)
-		u.RawQuery = q.Encode()
+	if resp.NeedConsent {
+		return newConsentRedirectURI(resp.AuthorizationID), nil
+	}
 
-		return u.String(), nil
+	if req.responseMode() == usecase.ResponseModeFragment {
+		return NewOAuth2AuthorizeRedirectURIWithFragment(req, resp)
 	}
 
-	if resp.NeedConsent {
-		return fmt.Sprintf("/oauth2/consent?authorization_id=%s", resp.AuthorizationID), nil
+	params, err := newOAuth2AuthorizeParams(req, resp)
+	if err != nil {
+		return "", err
 	}
 
-	u, err := url.Parse(req.RedirectURI)
+	return newOAuth2ClientRedirectURI(req, params, false)
+}
+
+// NewOAuth2AuthorizeRedirectURIWithFragment returns the response of the
+// implicit and hybrid flows in the fragment of the redirect uri, so that the
+// tokens are never sent to the server hosting the client.
+func NewOAuth2AuthorizeRedirectURIWithFragment(
+	req *OAuth2AuthorizeRequest,
+	resp *dto.OAuth2AuthorizeResponse,
+) (string, error) {
+	params, err := newOAuth2AuthorizeParams(req, resp)
 	if err != nil {
-		return "", xerror.Enrich(usecase.ErrRequestInvalid, "invalid redirect uri").
-			Hide(err, "invalid-redirect-url", "url", req.RedirectURI)
+		return "", err
 	}
 
-	q := u.Query()
+	return newOAuth2ClientRedirectURI(req, params, true)
+}
+
+func NewOAuth2AuthorizeRedirectURIWithError(
+	ctx context.Context,
+	req *OAuth2AuthorizeRequest,
+	err error,
+) (string, error) {
+	// The error is returned in the same component as the successful response
+	// of the requested response mode.
+	fragment := req.responseMode() == usecase.ResponseModeFragment
+	return newOAuth2ClientRedirectURI(req, newOAuth2AuthorizeErrorParams(ctx, req, err), fragment)
+}
+
+// OAuth2FormPostPageResponse is rendered as an auto-submitting form which
+// posts the response parameters to the redirect uri (OAuth 2.0 Form Post
+// Response Mode), so they never appear in the browser history or in logs.
+type OAuth2FormPostPageResponse struct {
+	RedirectURI string
+	Params      map[string]string
+}
+
+func NewOAuth2FormPostPageResponse(
+	req *OAuth2AuthorizeRequest,
+	resp *dto.OAuth2AuthorizeResponse,
+) (*OAuth2FormPostPageResponse, error) {
+	params, err := newOAuth2AuthorizeParams(req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOAuth2FormPostPageResponse(req, params), nil
+}
+
+func NewOAuth2FormPostPageResponseWithError(
+	ctx context.Context,
+	req *OAuth2AuthorizeRequest,
+	err error,
+) *OAuth2FormPostPageResponse {
+	return newOAuth2FormPostPageResponse(req, newOAuth2AuthorizeErrorParams(ctx, req, err))
+}
+
+func newOAuth2FormPostPageResponse(req *OAuth2AuthorizeRequest, params url.Values) *OAuth2FormPostPageResponse {
+	resp := &OAuth2FormPostPageResponse{
+		RedirectURI: req.RedirectURI,
+		Params:      map[string]string{},
+	}
+
+	for key := range params {
+		resp.Params[key] = params.Get(key)
+	}
+
+	return resp
+}
+
+func newOAuth2AuthorizeParams(req *OAuth2AuthorizeRequest, resp *dto.OAuth2AuthorizeResponse) (url.Values, error) {
+	params := url.Values{}
 
 	if req.State != "" {
-		q.Set("state", req.State)
+		params.Set("state", req.State)
 	}
 
 	if resp.Code != "" {
-		q.Set("code", resp.Code)
-	} else {
-		if resp.AccessToken != "" || resp.ExpiresIn == 0 || resp.TokenType == "" {
-			return "", errors.New("expected access_token, token_type, and expires_in if resp type is token")
+		params.Set("code", resp.Code)
+	}
+
+	if resp.AccessToken != "" {
+		if resp.ExpiresIn == 0 || resp.TokenType == "" {
+			return nil, errors.New("expected token_type and expires_in if access_token is issued")
 		}
 
-		q.Set("access_token", resp.AccessToken)
-		q.Set("token_type", resp.TokenType)
-		q.Set("expires_in", strconv.FormatInt(int64(resp.ExpiresIn), 10))
+		params.Set("access_token", resp.AccessToken)
+		params.Set("token_type", resp.TokenType)
+		params.Set("expires_in", strconv.FormatInt(int64(resp.ExpiresIn), 10))
+
+		if resp.Scope != "" {
+			params.Set("scope", resp.Scope)
+		}
 	}
 
-	u.RawQuery = q.Encode()
+	if resp.IDToken != "" {
+		params.Set("id_token", resp.IDToken)
+	}
 
-	return u.String(), nil
+	return params, nil
 }
 
-func NewOAuth2AuthorizeRedirectURIWithError(
-	ctx context.Context,
-	req *OAuth2AuthorizeRequest,
-	err error,
-) (string, error) {
-	u, uerr := xhttp.ParseURL(req.RedirectURI)
-	if uerr != nil {
+func newOAuth2AuthorizeErrorParams(ctx context.Context, req *OAuth2AuthorizeRequest, err error) url.Values {
+	if timeoutErr := context.Cause(ctx); timeoutErr != nil && errors.Is(timeoutErr, usecase.ErrServerTimeout) {
+		err = usecase.ErrServerTimeout.Hide(err, "timeout")
+	}
+
+	params := url.Values{}
+	standard.SetQuery(ctx, params, err)
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	return params
+}
+
+// newOAuth2ClientRedirectURI appends the params to the query or the fragment
+// of the redirect uri.
+func newOAuth2ClientRedirectURI(req *OAuth2AuthorizeRequest, params url.Values, fragment bool) (string, error) {
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
 		return "", xerror.Enrich(usecase.ErrRequestInvalid, "invalid redirect uri").
 			Hide(err, "invalid-redirect-uri", "uri", req.RedirectURI)
 	}
 
-	if timeoutErr := context.Cause(ctx); timeoutErr != nil && errors.Is(timeoutErr, usecase.ErrServerTimeout) {
-		err = usecase.ErrServerTimeout.Hide(err, "timeout")
+	if fragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+		return u.String() + "#" + params.Encode(), nil
 	}
 
 	q := u.Query()
-	standard.SetQuery(ctx, q, err)
-	if req.State != "" {
-		q.Set("state", req.State)
+	for key := range params {
+		q.Set(key, params.Get(key))
 	}
 
 	u.RawQuery = q.Encode()
 	return u.String(), nil
 }
 
+type OAuth2PushAuthorizationRequest struct {
+	ResponseType string `form:"response_type"`
+	ResponseMode string `form:"response_mode"`
+	RedirectURI  string `form:"redirect_uri"`
+	Scope        string `form:"scope"`
+	State        string `form:"state"`
+
+	// For PKCE
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+
+	// For OpenID Connect
+	Nonce string `form:"nonce"`
+
+	// For JWT-Secured Authorization Requests
+	Request string `form:"request"`
+}
+
+func (req OAuth2PushAuthorizationRequest) To(auth *dto.OAuth2ClientAuthentication) *dto.OAuth2PushAuthorizationRequest {
+	return &dto.OAuth2PushAuthorizationRequest{
+		OAuth2AuthorizeRequest: dto.OAuth2AuthorizeRequest{
+			ResponseType:        req.ResponseType,
+			ResponseMode:        req.ResponseMode,
+			ClientID:            auth.ClientID,
+			RedirectURI:         req.RedirectURI,
+			Scope:               req.Scope,
+			State:               req.State,
+			CodeChallenge:       req.CodeChallenge,
+			CodeChallengeMethod: req.CodeChallengeMethod,
+			Nonce:               req.Nonce,
+			Request:             req.Request,
+		},
+		Authentication: *auth,
+	}
+}
+
+type OAuth2PushAuthorizationResponse struct {
+	RequestURI string `json:"request_uri" example:"urn:ietf:params:oauth:request_uri:6esc_11ACC5bwc014ltc14eY22c"`
+	ExpiresIn  int    `json:"expires_in" example:"90"`
+}
+
+func NewOAuth2PushAuthorizationResponse(resp *dto.OAuth2PushAuthorizationResponse) *OAuth2PushAuthorizationResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2PushAuthorizationResponse{
+		RequestURI: resp.RequestURI,
+		ExpiresIn:  resp.ExpiresIn,
+	}
+}
+
+type OAuth2ErrorPageResponse struct {
+	Error            string
+	ErrorDescription string
+}
+
+func NewOAuth2ErrorPageResponse(ctx context.Context, err error) *OAuth2ErrorPageResponse {
+	errResp := standard.NewErrorResponse(ctx, err)
+
+	return &OAuth2ErrorPageResponse{
+		Error:            errResp.Error,
+		ErrorDescription: errResp.ErrorDescription,
+	}
+}
+
 type OAuth2AuthenticationCallbackRequest struct {
 	IdPSecret       string `json:"idp_secret" example:"Sde3kl..."`
 	AuthorizationID string `json:"authorization_id" example:"djG4l..."`
@@ -231,9 +623,21 @@ func NewOAuth2SessionUpdateRedirectURI(resp *dto.OAuth2SessionUpdateResponse) st
 		return ""
 	}
 
+	if resp.UserCode != "" {
+		return newDeviceVerificationRedirectURI(resp.UserCode)
+	}
+
 	q := url.Values{}
-	q.Set("response_type", resp.ResponseType)
 	q.Set("client_id", resp.ClientID.String())
+
+	// The parameters of a pushed authorization request are never sent in the
+	// query.
+	if resp.RequestURI != "" {
+		q.Set("request_uri", resp.RequestURI)
+		return fmt.Sprintf("/oauth2/authorize?%s", q.Encode())
+	}
+
+	q.Set("response_type", resp.ResponseType)
 	q.Set("redirect_uri", resp.RedirectURI)
 	q.Set("scope", resp.Scope)
 
@@ -249,6 +653,14 @@ func NewOAuth2SessionUpdateRedirectURI(resp *dto.OAuth2SessionUpdateResponse) st
 		q.Set("code_challenge_method", resp.CodeChallengeMethod)
 	}
 
+	if resp.Nonce != "" {
+		q.Set("nonce", resp.Nonce)
+	}
+
+	if resp.ResponseMode != "" {
+		q.Set("response_mode", resp.ResponseMode)
+	}
+
 	return fmt.Sprintf("/oauth2/authorize?%s", q.Encode())
 }
 
@@ -314,9 +726,21 @@ func NewOAuth2ConsentUpdateRedirectURI(resp *dto.OAUth2UpdateConsentResponse) st
 		return ""
 	}
 
+	if resp.UserCode != "" {
+		return newDeviceVerificationRedirectURI(resp.UserCode)
+	}
+
 	q := url.Values{}
-	q.Set("response_type", resp.ResponseType)
 	q.Set("client_id", resp.ClientID.String())
+
+	// The parameters of a pushed authorization request are never sent in the
+	// query.
+	if resp.RequestURI != "" {
+		q.Set("request_uri", resp.RequestURI)
+		return fmt.Sprintf("/oauth2/authorize?%s", q.Encode())
+	}
+
+	q.Set("response_type", resp.ResponseType)
 	q.Set("redirect_uri", resp.RedirectURI)
 	q.Set("scope", resp.Scope)
 
@@ -332,5 +756,327 @@ func NewOAuth2ConsentUpdateRedirectURI(resp *dto.OAUth2UpdateConsentResponse) st
 		q.Set("code_challenge_method", resp.CodeChallengeMethod)
 	}
 
+	if resp.Nonce != "" {
+		q.Set("nonce", resp.Nonce)
+	}
+
+	if resp.ResponseMode != "" {
+		q.Set("response_mode", resp.ResponseMode)
+	}
+
 	return fmt.Sprintf("/oauth2/authorize?%s", q.Encode())
 }
+
+type OAuth2RevokeRequest struct {
+	Token string `form:"token"`
+}
+
+func (req OAuth2RevokeRequest) To(auth *dto.OAuth2ClientAuthentication) *dto.OAuth2RevokeRequest {
+	return &dto.OAuth2RevokeRequest{
+		OAuth2ClientAuthentication: *auth,
+		Token:                      req.Token,
+	}
+}
+
+type OAuth2RevokeResponse struct{}
+
+func NewOAuth2RevokeResponse(resp *dto.OAuth2RevokeResponse) *OAuth2RevokeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2RevokeResponse{}
+}
+
+type OAuth2IntrospectRequest struct {
+	Token string `form:"token"`
+}
+
+func (req OAuth2IntrospectRequest) To(auth *dto.OAuth2ClientAuthentication) *dto.OAuth2IntrospectRequest {
+	return &dto.OAuth2IntrospectRequest{
+		OAuth2ClientAuthentication: *auth,
+		Token:                      req.Token,
+	}
+}
+
+type OAuth2IntrospectResponse struct {
+	Active    bool   `json:"active" example:"true"`
+	Scope     string `json:"scope,omitempty" example:"read:user"`
+	ClientID  string `json:"client_id,omitempty" example:"330559330522759168"`
+	Subject   string `json:"sub,omitempty" example:"330559330522759168"`
+	ExpiresAt int    `json:"exp,omitempty" example:"1729440000"`
+	IssuedAt  int    `json:"iat,omitempty" example:"1729436400"`
+	TokenID   string `json:"jti,omitempty" example:"330559330522759169"`
+	TokenType string `json:"token_type,omitempty" example:"access_token"`
+
+	Confirmation *OAuth2Confirmation `json:"cnf,omitempty"`
+	Actor        *OAuth2Actor        `json:"act,omitempty"`
+}
+
+// OAuth2Actor is the party acting on behalf of the subject of a token issued
+// by the token exchange (RFC 8693), the nested actor is the prior one.
+type OAuth2Actor struct {
+	Subject  string       `json:"sub" example:"330559330522759170"`
+	ClientID string       `json:"client_id,omitempty" example:"330559330522759170"`
+	Actor    *OAuth2Actor `json:"act,omitempty"`
+}
+
+func NewOAuth2Actor(actor *dto.OAuth2Actor) *OAuth2Actor {
+	if actor == nil {
+		return nil
+	}
+
+	return &OAuth2Actor{
+		Subject:  actor.Subject,
+		ClientID: actor.ClientID,
+		Actor:    NewOAuth2Actor(actor.Actor),
+	}
+}
+
+// OAuth2Confirmation is the key which the token is bound to (RFC 7800).
+type OAuth2Confirmation struct {
+	CertificateThumbprint string `json:"x5t#S256,omitempty" example:"bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"`
+	KeyThumbprint         string `json:"jkt,omitempty" example:"0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"`
+}
+
+func NewOAuth2IntrospectResponse(resp *dto.OAuth2IntrospectResponse) *OAuth2IntrospectResponse {
+	if resp == nil {
+		return nil
+	}
+
+	introspection := &OAuth2IntrospectResponse{
+		Active:    resp.Active,
+		Scope:     resp.Scope,
+		ClientID:  resp.ClientID,
+		Subject:   resp.Subject,
+		ExpiresAt: resp.ExpiresAt,
+		IssuedAt:  resp.IssuedAt,
+		TokenID:   resp.TokenID,
+		TokenType: resp.TokenType,
+
+		Actor: NewOAuth2Actor(resp.Actor),
+	}
+
+	if resp.CertificateThumbprint != "" || resp.KeyThumbprint != "" {
+		introspection.Confirmation = &OAuth2Confirmation{
+			CertificateThumbprint: resp.CertificateThumbprint,
+			KeyThumbprint:         resp.KeyThumbprint,
+		}
+	}
+
+	return introspection
+}
+
+type OAuth2DeviceAuthorizationRequest struct {
+	Scope string `form:"scope"`
+}
+
+func (req OAuth2DeviceAuthorizationRequest) To(auth *dto.OAuth2ClientAuthentication) *dto.OAuth2DeviceAuthorizationRequest {
+	return &dto.OAuth2DeviceAuthorizationRequest{
+		OAuth2ClientAuthentication: *auth,
+		Scope:                      req.Scope,
+	}
+}
+
+type OAuth2DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code" example:"GmRhmhcxhwAzkoEqiMEg..."`
+	UserCode                string `json:"user_code" example:"WDJB-MJHT"`
+	VerificationURI         string `json:"verification_uri" example:"https://todennus.com/oauth2/device"`
+	VerificationURIComplete string `json:"verification_uri_complete" example:"https://todennus.com/oauth2/device?user_code=WDJB-MJHT"`
+	ExpiresIn               int    `json:"expires_in" example:"600"`
+	Interval                int    `json:"interval" example:"5"`
+}
+
+func NewOAuth2DeviceAuthorizationResponse(
+	verificationURI string,
+	resp *dto.OAuth2DeviceAuthorizationResponse,
+) *OAuth2DeviceAuthorizationResponse {
+	if resp == nil {
+		return nil
+	}
+
+	q := url.Values{}
+	q.Set("user_code", resp.UserCode)
+
+	return &OAuth2DeviceAuthorizationResponse{
+		DeviceCode:              resp.DeviceCode,
+		UserCode:                resp.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: fmt.Sprintf("%s?%s", verificationURI, q.Encode()),
+		ExpiresIn:               resp.ExpiresIn,
+		Interval:                resp.Interval,
+	}
+}
+
+type OAuth2DeviceVerifyRequest struct {
+	UserCode string `query:"user_code"`
+}
+
+func (req OAuth2DeviceVerifyRequest) To(clientIP string) *dto.OAuth2DeviceVerifyRequest {
+	return &dto.OAuth2DeviceVerifyRequest{
+		UserCode: req.UserCode,
+		ClientIP: clientIP,
+	}
+}
+
+type OAuth2DeviceConfirmRequest struct {
+	AuthorizationID string `form:"authorization_id"`
+	UserCode        string `form:"user_code"`
+	Accept          bool   `form:"accept"`
+}
+
+func (req OAuth2DeviceConfirmRequest) To() *dto.OAuth2DeviceConfirmRequest {
+	return &dto.OAuth2DeviceConfirmRequest{
+		AuthorizationID: req.AuthorizationID,
+		UserCode:        req.UserCode,
+		Accept:          req.Accept,
+	}
+}
+
+// NewOAuth2DeviceVerifyRedirectURI returns an empty string if the user does not
+// need to be redirected to anywhere.
+func NewOAuth2DeviceVerifyRedirectURI(resp *dto.OAuth2DeviceVerifyResponse) (string, error) {
+	if resp.IdpURL != "" {
+		return newIdPRedirectURI(resp.IdpURL, resp.AuthorizationID)
+	}
+
+	if resp.NeedConsent {
+		return newConsentRedirectURI(resp.AuthorizationID), nil
+	}
+
+	return "", nil
+}
+
+type OAuth2DevicePageResponse struct {
+	UserCode         string
+	Approved         bool
+	Denied           bool
+	Error            string
+	ErrorDescription string
+
+	// The user must confirm the user code of the client.
+	Confirm         bool
+	AuthorizationID string
+	ClientName      string
+	Scope           string
+}
+
+func NewOAuth2DevicePageResponse(userCode string, resp *dto.OAuth2DeviceVerifyResponse) *OAuth2DevicePageResponse {
+	page := &OAuth2DevicePageResponse{UserCode: userCode}
+	if resp != nil && resp.Confirm {
+		page.UserCode = resp.UserCode
+		page.Confirm = true
+		page.AuthorizationID = resp.AuthorizationID
+		page.ClientName = resp.ClientName
+		page.Scope = resp.Scope
+	}
+
+	return page
+}
+
+func NewOAuth2DeviceConfirmPageResponse(resp *dto.OAuth2DeviceConfirmResponse) *OAuth2DevicePageResponse {
+	return &OAuth2DevicePageResponse{
+		Approved: resp.Approved,
+		Denied:   resp.Denied,
+	}
+}
+
+func NewOAuth2DevicePageErrorResponse(ctx context.Context, userCode string, err error) *OAuth2DevicePageResponse {
+	errResp := standard.NewErrorResponse(ctx, err)
+
+	return &OAuth2DevicePageResponse{
+		UserCode:         userCode,
+		Error:            errResp.Error,
+		ErrorDescription: errResp.ErrorDescription,
+	}
+}
+
+func newIdPRedirectURI(idpURL, authorizationID string) (string, error) {
+	u, err := url.Parse(idpURL)
+	if err != nil {
+		return "", usecase.ErrServer.Hide(err, "invalid-idp-url", "url", idpURL)
+	}
+
+	q := u.Query()
+	q.Set("authorization_id", authorizationID)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func newConsentRedirectURI(authorizationID string) string {
+	return fmt.Sprintf("/oauth2/consent?authorization_id=%s", authorizationID)
+}
+
+func newDeviceVerificationRedirectURI(userCode string) string {
+	q := url.Values{}
+	q.Set("user_code", userCode)
+	return fmt.Sprintf("/oauth2/device?%s", q.Encode())
+}
+
+type OAuth2BackchannelAuthorizeRequest struct {
+	Scope                   string `form:"scope"`
+	LoginHint               string `form:"login_hint"`
+	BindingMessage          string `form:"binding_message"`
+	ClientNotificationToken string `form:"client_notification_token"`
+	RequestedExpiry         int    `form:"requested_expiry"`
+}
+
+func (req OAuth2BackchannelAuthorizeRequest) To(
+	auth *dto.OAuth2ClientAuthentication,
+) *dto.OAuth2BackchannelAuthorizeRequest {
+	return &dto.OAuth2BackchannelAuthorizeRequest{
+		OAuth2ClientAuthentication: *auth,
+		Scope:                      req.Scope,
+		LoginHint:                  req.LoginHint,
+		BindingMessage:             req.BindingMessage,
+		ClientNotificationToken:    req.ClientNotificationToken,
+		RequestedExpiry:            req.RequestedExpiry,
+	}
+}
+
+type OAuth2BackchannelAuthorizeResponse struct {
+	AuthReqID string `json:"auth_req_id" example:"1c266114-a1be-4252-8ad1-04986c5b9ac1"`
+	ExpiresIn int    `json:"expires_in" example:"300"`
+	Interval  int    `json:"interval,omitempty" example:"5"`
+}
+
+func NewOAuth2BackchannelAuthorizeResponse(
+	resp *dto.OAuth2BackchannelAuthorizeResponse,
+) *OAuth2BackchannelAuthorizeResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2BackchannelAuthorizeResponse{
+		AuthReqID: resp.AuthReqID,
+		ExpiresIn: resp.ExpiresIn,
+		Interval:  resp.Interval,
+	}
+}
+
+type OAuth2BackchannelConsentRequest struct {
+	AuthorizationID string `query:"authorization_id"`
+	Consent         string `form:"consent"`
+	UserScope       string `form:"scope"`
+}
+
+func (req OAuth2BackchannelConsentRequest) To() *dto.OAuth2BackchannelConsentRequest {
+	return &dto.OAuth2BackchannelConsentRequest{
+		Accept:          strings.ToLower(req.Consent) == "accepted",
+		AuthorizationID: req.AuthorizationID,
+		UserScope:       req.UserScope,
+	}
+}
+
+type OAuth2BackchannelConsentResponse struct {
+	Approved bool `json:"approved" example:"true"`
+}
+
+func NewOAuth2BackchannelConsentResponse(resp *dto.OAuth2BackchannelConsentResponse) *OAuth2BackchannelConsentResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OAuth2BackchannelConsentResponse{Approved: resp.Approved}
+}
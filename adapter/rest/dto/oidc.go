@@ -0,0 +1,142 @@
+package dto
+
+import (
+	"github.com/xybor/todennus-backend/usecase/dto"
+)
+
+type OIDCGetDiscoveryRequest struct{}
+
+func (req OIDCGetDiscoveryRequest) To() *dto.OIDCGetDiscoveryRequest {
+	return &dto.OIDCGetDiscoveryRequest{}
+}
+
+type OIDCGetDiscoveryResponse struct {
+	Issuer                            string   `json:"issuer" example:"https://todennus.example.com"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint" example:"https://todennus.example.com/oauth2/authorize"`
+	TokenEndpoint                     string   `json:"token_endpoint" example:"https://todennus.example.com/oauth2/token"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint" example:"https://todennus.example.com/oauth2/device_authorization"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint" example:"https://todennus.example.com/oauth2/userinfo"`
+	RevocationEndpoint                string   `json:"revocation_endpoint" example:"https://todennus.example.com/oauth2/revoke"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint" example:"https://todennus.example.com/oauth2/introspect"`
+	PAREndpoint                       string   `json:"pushed_authorization_request_endpoint" example:"https://todennus.example.com/oauth2/par"`
+	RegistrationEndpoint              string   `json:"registration_endpoint" example:"https://todennus.example.com/oauth2/register"`
+	JWKSURI                           string   `json:"jwks_uri" example:"https://todennus.example.com/.well-known/jwks.json"`
+	ScopesSupported                   []string `json:"scopes_supported" example:"openid"`
+	ResponseTypesSupported            []string `json:"response_types_supported" example:"code"`
+	ResponseModesSupported            []string `json:"response_modes_supported" example:"query"`
+	GrantTypesSupported               []string `json:"grant_types_supported" example:"authorization_code"`
+	SubjectTypesSupported             []string `json:"subject_types_supported" example:"public"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported" example:"RS256"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported" example:"S256"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported" example:"client_secret_post"`
+
+	RequestParameterSupported              bool     `json:"request_parameter_supported" example:"true"`
+	RequestObjectSigningAlgValuesSupported []string `json:"request_object_signing_alg_values_supported" example:"RS256"`
+
+	TLSClientCertificateBoundAccessTokens bool     `json:"tls_client_certificate_bound_access_tokens" example:"true"`
+	DPoPSigningAlgValuesSupported         []string `json:"dpop_signing_alg_values_supported" example:"ES256"`
+
+	BackchannelAuthenticationEndpoint     string   `json:"backchannel_authentication_endpoint" example:"https://todennus.example.com/oauth2/bc-authorize"`
+	BackchannelTokenDeliveryModes         []string `json:"backchannel_token_delivery_modes_supported" example:"poll"`
+	BackchannelUserCodeParameterSupported bool     `json:"backchannel_user_code_parameter_supported" example:"false"`
+}
+
+func NewOIDCGetDiscoveryResponse(baseURL string, resp *dto.OIDCGetDiscoveryResponse) *OIDCGetDiscoveryResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OIDCGetDiscoveryResponse{
+		Issuer:                            resp.Issuer,
+		AuthorizationEndpoint:             baseURL + "/oauth2/authorize",
+		TokenEndpoint:                     baseURL + "/oauth2/token",
+		DeviceAuthorizationEndpoint:       baseURL + "/oauth2/device_authorization",
+		UserInfoEndpoint:                  baseURL + "/oauth2/userinfo",
+		RevocationEndpoint:                baseURL + "/oauth2/revoke",
+		IntrospectionEndpoint:             baseURL + "/oauth2/introspect",
+		PAREndpoint:                       baseURL + "/oauth2/par",
+		RegistrationEndpoint:              baseURL + "/oauth2/register",
+		JWKSURI:                           baseURL + "/.well-known/jwks.json",
+		ScopesSupported:                   resp.Scopes,
+		ResponseTypesSupported:            resp.ResponseTypes,
+		ResponseModesSupported:            resp.ResponseModes,
+		GrantTypesSupported:               resp.GrantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  resp.SigningAlgorithms,
+		CodeChallengeMethodsSupported:     resp.CodeChallengeMethods,
+		TokenEndpointAuthMethodsSupported: resp.TokenEndpointAuthMethods,
+
+		RequestParameterSupported:              true,
+		RequestObjectSigningAlgValuesSupported: resp.RequestObjectSigningAlgorithms,
+
+		TLSClientCertificateBoundAccessTokens: true,
+		DPoPSigningAlgValuesSupported:         resp.DPoPSigningAlgorithms,
+
+		BackchannelAuthenticationEndpoint:     baseURL + "/oauth2/bc-authorize",
+		BackchannelTokenDeliveryModes:         resp.BackchannelTokenDeliveryModes,
+		BackchannelUserCodeParameterSupported: false,
+	}
+}
+
+type OIDCGetJWKSRequest struct{}
+
+func (req OIDCGetJWKSRequest) To() *dto.OIDCGetJWKSRequest {
+	return &dto.OIDCGetJWKSRequest{}
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	KeyID     string `json:"kid" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+	N         string `json:"n" example:"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4..."`
+	E         string `json:"e" example:"AQAB"`
+}
+
+type OIDCGetJWKSResponse struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func NewOIDCGetJWKSResponse(resp *dto.OIDCGetJWKSResponse) *OIDCGetJWKSResponse {
+	if resp == nil {
+		return nil
+	}
+
+	keys := []JSONWebKey{}
+	for _, key := range resp.Keys {
+		keys = append(keys, JSONWebKey{
+			KeyType:   key.KeyType,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			KeyID:     key.KeyID,
+			N:         key.N,
+			E:         key.E,
+		})
+	}
+
+	return &OIDCGetJWKSResponse{Keys: keys}
+}
+
+type OIDCGetUserInfoRequest struct{}
+
+func (req OIDCGetUserInfoRequest) To() *dto.OIDCGetUserInfoRequest {
+	return &dto.OIDCGetUserInfoRequest{}
+}
+
+type OIDCGetUserInfoResponse struct {
+	Subject           string `json:"sub" example:"330559330522759168"`
+	PreferredUsername string `json:"preferred_username,omitempty" example:"huykingsofm"`
+	Name              string `json:"name,omitempty" example:"Huy Le Ngoc"`
+}
+
+func NewOIDCGetUserInfoResponse(resp *dto.OIDCGetUserInfoResponse) *OIDCGetUserInfoResponse {
+	if resp == nil {
+		return nil
+	}
+
+	return &OIDCGetUserInfoResponse{
+		Subject:           resp.Subject.String(),
+		PreferredUsername: resp.PreferredUsername,
+		Name:              resp.Name,
+	}
+}
@@ -1,6 +1,8 @@
 package resource
 
 import (
+	"strings"
+
 	"github.com/xybor/todennus-backend/usecase/dto/resource"
 )
 
@@ -9,6 +11,18 @@ type OAuth2Client struct {
 	ClientID     string `json:"client_id,omitempty" example:"332974701238012989"`
 	Name         string `json:"name,omitempty" example:"Example Client"`
 	AllowedScope string `json:"allowed_scope,omitempty" example:"read:user"`
+	RedirectURIs string `json:"redirect_uris,omitempty" example:"https://example.com/callback http://127.0.0.1/callback"`
+
+	AllowedResources  string `json:"allowed_resources,omitempty" example:"https://api.example.com"`
+	AllowImplicitFlow bool   `json:"allow_implicit_flow,omitempty" example:"false"`
+	RequirePAR        bool   `json:"require_pushed_authorization_requests,omitempty" example:"false"`
+	ForbidPlainPKCE   bool   `json:"forbid_plain_code_challenge,omitempty" example:"true"`
+	AuthMethod        string `json:"token_endpoint_auth_method,omitempty" example:"client_secret_basic"`
+	JWKS              string `json:"jwks,omitempty" example:"{\"keys\":[{\"kty\":\"RSA\",\"kid\":\"key-1\",\"n\":\"0vx7ag...\",\"e\":\"AQAB\"}]}"`
+	TLSSubjectDN      string `json:"tls_client_auth_subject_dn,omitempty" example:"CN=example-client,O=Example"`
+	BackchannelMode   string `json:"backchannel_token_delivery_mode,omitempty" example:"poll"`
+	BackchannelNotify string `json:"backchannel_client_notification_endpoint,omitempty" example:"https://example.com/cb"`
+	IsDisabled        bool   `json:"is_disabled,omitempty" example:"false"`
 }
 
 func NewOAuth2Client(client *resource.OAuth2Client) *OAuth2Client {
@@ -17,5 +31,17 @@ func NewOAuth2Client(client *resource.OAuth2Client) *OAuth2Client {
 		ClientID:     client.ClientID.String(),
 		Name:         client.Name,
 		AllowedScope: client.AllowedScope,
+		RedirectURIs: strings.Join(client.RedirectURIs, " "),
+
+		AllowedResources:  strings.Join(client.AllowedResources, " "),
+		AllowImplicitFlow: client.AllowImplicitFlow,
+		RequirePAR:        client.RequirePAR,
+		ForbidPlainPKCE:   client.ForbidPlainPKCE,
+		AuthMethod:        client.AuthMethod,
+		JWKS:              client.JWKS,
+		TLSSubjectDN:      client.TLSSubjectDN,
+		BackchannelMode:   client.BackchannelMode,
+		BackchannelNotify: client.BackchannelNotify,
+		IsDisabled:        client.IsDisabled,
 	}
 }
@@ -3,20 +3,31 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/xybor/todennus-backend/adapter/abstraction"
 	"github.com/xybor/todennus-backend/adapter/common"
+	"github.com/xybor/todennus-backend/adapter/rest/dto"
 	"github.com/xybor/todennus-backend/adapter/rest/response"
 	"github.com/xybor/todennus-backend/adapter/rest/standard"
 	"github.com/xybor/x/token"
 	"github.com/xybor/x/xcontext"
 )
 
-func Authentication(engine token.Engine) func(http.Handler) http.Handler {
+func Authentication(
+	baseURL string,
+	engine token.Engine,
+	oauth2Usecase abstraction.OAuth2Usecase,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 			authorization := r.Header.Get("Authorization")
 
-			next.ServeHTTP(w, r.WithContext(common.WithAuthenticate(ctx, authorization, engine)))
+			dpop, err := dto.NewOAuth2DPoP(r, baseURL)
+			if err != nil {
+				xcontext.Logger(ctx).Debug("failed-to-get-dpop-proof", "err", err)
+			}
+
+			next.ServeHTTP(w, r.WithContext(common.WithAuthenticate(ctx, authorization, dpop, engine, oauth2Usecase)))
 		})
 	}
 }
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/todennus-backend/usecase/reqctx"
+	"github.com/xybor/x/xcontext"
+)
+
+// ClientCertificateSource reads the certificate which the client presents in
+// the mutual-TLS connection (RFC 8705). It returns nil if the client presents
+// no certificate.
+type ClientCertificateSource interface {
+	ClientCertificate(r *http.Request) (*dto.OAuth2ClientCertificate, error)
+}
+
+// TLSClientCertificateSource reads the certificate from the TLS connection
+// terminated by this server. The server must request the client certificate
+// without verifying it, otherwise self-signed certificates are rejected in
+// the handshake.
+type TLSClientCertificateSource struct {
+	roots *x509.CertPool
+}
+
+// NewTLSClientCertificateSource creates a source which trusts certificates
+// chaining to the roots. If roots is nil, no certificate is trusted, so only
+// self_signed_tls_client_auth works.
+func NewTLSClientCertificateSource(roots *x509.CertPool) *TLSClientCertificateSource {
+	return &TLSClientCertificateSource{roots: roots}
+}
+
+func (source *TLSClientCertificateSource) ClientCertificate(r *http.Request) (*dto.OAuth2ClientCertificate, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, nil
+	}
+
+	return newClientCertificate(source.roots, r.TLS.PeerCertificates[0], r.TLS.PeerCertificates[1:]), nil
+}
+
+// HeaderClientCertificateSource reads the URL-encoded PEM certificate from
+// the header set by the proxy terminating the mutual-TLS connection, such as
+// the sidecar of a service mesh. The proxy must always overwrite the header,
+// otherwise any client can forge its certificate.
+type HeaderClientCertificateSource struct {
+	header string
+	roots  *x509.CertPool
+}
+
+// NewHeaderClientCertificateSource creates a source which trusts certificates
+// chaining to the roots. If roots is nil, no certificate is trusted, so only
+// self_signed_tls_client_auth works.
+func NewHeaderClientCertificateSource(header string, roots *x509.CertPool) *HeaderClientCertificateSource {
+	return &HeaderClientCertificateSource{header: header, roots: roots}
+}
+
+func (source *HeaderClientCertificateSource) ClientCertificate(r *http.Request) (*dto.OAuth2ClientCertificate, error) {
+	value := r.Header.Get(source.header)
+	if value == "" {
+		return nil, nil
+	}
+
+	value, err := url.QueryUnescape(value)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(value))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("require a pem-encoded certificate")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClientCertificate(source.roots, certificate, nil), nil
+}
+
+func WithClientCertificate(source ClientCertificateSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			certificate, err := source.ClientCertificate(r)
+			if err != nil {
+				xcontext.Logger(ctx).Debug("failed-to-get-client-certificate", "err", err)
+			} else if certificate != nil {
+				ctx = reqctx.WithClientCertificate(ctx, certificate)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func newClientCertificate(
+	roots *x509.CertPool,
+	certificate *x509.Certificate,
+	intermediates []*x509.Certificate,
+) *dto.OAuth2ClientCertificate {
+	trusted := false
+	if roots != nil {
+		pool := x509.NewCertPool()
+		for _, intermediate := range intermediates {
+			pool.AddCert(intermediate)
+		}
+
+		_, err := certificate.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: pool,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		trusted = err == nil
+	}
+
+	return &dto.OAuth2ClientCertificate{Certificate: certificate, Trusted: trusted}
+}
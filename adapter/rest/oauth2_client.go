@@ -15,22 +15,39 @@ import (
 )
 
 type OAuth2ClientAdapter struct {
+	baseURL             string
 	oauth2ClientUsecase abstraction.OAuth2ClientUsecase
 }
 
-func NewOAuth2ClientAdapter(oauth2ClientUsecase abstraction.OAuth2ClientUsecase) *OAuth2ClientAdapter {
+func NewOAuth2ClientAdapter(baseURL string, oauth2ClientUsecase abstraction.OAuth2ClientUsecase) *OAuth2ClientAdapter {
 	return &OAuth2ClientAdapter{
+		baseURL:             baseURL,
 		oauth2ClientUsecase: oauth2ClientUsecase,
 	}
 }
 
 func (a *OAuth2ClientAdapter) Router(r chi.Router) {
+	r.Get("/", middleware.RequireAuthentication(a.List()))
 	r.Get("/{client_id}", middleware.RequireAuthentication(a.Get()))
+	r.Put("/{client_id}", middleware.RequireAuthentication(a.Update()))
+	r.Delete("/{client_id}", middleware.RequireAuthentication(a.Delete()))
+
+	r.Post("/{client_id}/disable", middleware.RequireAuthentication(a.Disable(true)))
+	r.Post("/{client_id}/enable", middleware.RequireAuthentication(a.Disable(false)))
+	r.Post("/{client_id}/transfer", middleware.RequireAuthentication(a.Transfer()))
 
 	r.Post("/", middleware.RequireAuthentication(a.Create()))
 	r.Post("/first", a.CreateByAdmin())
 }
 
+func (a *OAuth2ClientAdapter) RegistrationRouter(r chi.Router) {
+	r.Post("/", middleware.RequireAuthentication(a.Register()))
+
+	r.Get("/{client_id}", a.ReadRegistration())
+	r.Put("/{client_id}", a.UpdateRegistration())
+	r.Delete("/{client_id}", a.DeleteRegistration())
+}
+
 // @Summary Get oauth2 client by id
 // @Description Get OAuth2 Client information by ClientID. <br>
 // @Tags OAuth2 Client
@@ -117,3 +134,279 @@ func (a *OAuth2ClientAdapter) CreateByAdmin() http.HandlerFunc {
 			WriteHTTPResponse(ctx, w)
 	}
 }
+
+// @Summary Update oauth2 client
+// @Description Update an OAuth2 Client owned by the current user, admins can update any client. The `redirect_uris` field is a space-separated list of redirect uris which replaces the current one. <br>
+// @Description The `allowed_resources` field is a space-separated list of resource indicators (RFC 8707) which the client can request tokens for. <br>
+// @Description The `allowed_scope` field is a space-separated list of scopes which replaces the current one, it can not exceed the default scope of the client. <br>
+// @Description Require scope `[todennus]update:client`, and `[todennus]update:client.allowed_scope` to update the allowed scope.
+// @Tags OAuth2 Client
+// @Accept json
+// @Produce json
+// @Param id path string true "ClientID"
+// @Param body body dto.OAuth2ClientUpdateRequest true "Client Information"
+// @Success 200 {object} standard.SwaggerSuccessResponse[dto.OAuth2ClientUpdateResponse] "Update client successfully"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} standard.SwaggerNotFoundErrorResponse "Not found"
+// @Router /oauth2_clients/{client_id} [put]
+func (a *OAuth2ClientAdapter) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2ClientUpdateRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.Update(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientUpdateResponse(resp), err).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid, usecase.ErrScopeInvalid).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			Map(http.StatusNotFound, usecase.ErrClientInvalid).
+			WriteHTTPResponse(ctx, w)
+	}
+}
+
+// @Summary List oauth2 clients
+// @Description List OAuth2 Clients owned by the current user, ordered by ClientID. If `all` is true, list clients of all users, which is only allowed for admins. <br>
+// @Description Require scope `[todennus]read:client`.
+// @Tags OAuth2 Client
+// @Produce json
+// @Param all query bool false "List clients of all users (admin only)"
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Number of clients per page (default 20, at most 100)"
+// @Success 200 {object} standard.SwaggerSuccessResponse[dto.OAuth2ClientListResponse] "List clients successfully"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Router /oauth2_clients [get]
+func (a *OAuth2ClientAdapter) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2ClientListRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.List(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientListResponse(resp), err).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			WriteHTTPResponse(ctx, w)
+	}
+}
+
+// @Summary Disable or enable oauth2 client
+// @Description Disable an OAuth2 Client, a disabled client can not start any flow or obtain new tokens until it is enabled again. Access tokens already issued to it stay valid until they expire. Its consents and refresh tokens are kept. <br>
+// @Description Only the owner and admins can disable the client. Require scope `[todennus]update:client`.
+// @Tags OAuth2 Client
+// @Produce json
+// @Param id path string true "ClientID"
+// @Success 200 {object} standard.SwaggerSuccessResponse[dto.OAuth2ClientDisableResponse] "Disable client successfully"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} standard.SwaggerNotFoundErrorResponse "Not found"
+// @Router /oauth2_clients/{client_id}/disable [post]
+// @Router /oauth2_clients/{client_id}/enable [post]
+func (a *OAuth2ClientAdapter) Disable(isDisabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// The request has no body, xhttp rejects post requests without a
+		// content type.
+		req := dto.OAuth2ClientDisableRequest{ClientID: chi.URLParam(r, "client_id")}
+
+		resp, err := a.oauth2ClientUsecase.Disable(ctx, req.To(isDisabled))
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientDisableResponse(resp), err).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			Map(http.StatusNotFound, usecase.ErrClientInvalid).
+			WriteHTTPResponse(ctx, w)
+	}
+}
+
+// @Summary Delete oauth2 client
+// @Description Delete an OAuth2 Client along with the consents given to it and the refresh tokens issued to it. <br>
+// @Description Only the owner and admins can delete the client. Require scope `[todennus]delete:client`.
+// @Tags OAuth2 Client
+// @Param id path string true "ClientID"
+// @Success 204 "Delete client successfully"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} standard.SwaggerNotFoundErrorResponse "Not found"
+// @Router /oauth2_clients/{client_id} [delete]
+func (a *OAuth2ClientAdapter) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// The request has no body, xhttp rejects delete requests without a
+		// content type.
+		req := dto.OAuth2ClientDeleteRequest{ClientID: chi.URLParam(r, "client_id")}
+
+		_, err := a.oauth2ClientUsecase.Delete(ctx, req.To())
+		if err != nil {
+			response.NewResponseHandler(ctx, nil, err).
+				Map(http.StatusForbidden, usecase.ErrForbidden).
+				Map(http.StatusNotFound, usecase.ErrClientInvalid).
+				WriteHTTPResponse(ctx, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary Transfer oauth2 client
+// @Description Transfer the ownership of an OAuth2 Client to another user. <br>
+// @Description Only the owner and admins can transfer the client. Require scope `[todennus]update:client.owner`.
+// @Tags OAuth2 Client
+// @Accept json
+// @Produce json
+// @Param id path string true "ClientID"
+// @Param body body dto.OAuth2ClientTransferRequest true "New Owner"
+// @Success 200 {object} standard.SwaggerSuccessResponse[dto.OAuth2ClientTransferResponse] "Transfer client successfully"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} standard.SwaggerNotFoundErrorResponse "Not found"
+// @Router /oauth2_clients/{client_id}/transfer [post]
+func (a *OAuth2ClientAdapter) Transfer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2ClientTransferRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.Transfer(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientTransferResponse(resp), err).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			Map(http.StatusNotFound, usecase.ErrClientInvalid).
+			WriteHTTPResponse(ctx, w)
+	}
+}
+
+// @Summary Dynamic client registration endpoint
+// @Description Register an OAuth2 Client with the standard client metadata (RFC 7591). The request is authorized by an initial access token, which is an access token of the owner with scope `[todennus]create:client`. <br>
+// @Description The `token_endpoint_auth_method` defaults to `client_secret_basic`, the client is public if it is `none`. The `grant_types` defaults to `authorization_code` and the `response_types` defaults to `code`. <br>
+// @Description The returned `registration_access_token` authorizes the client configuration endpoint `registration_client_uri` (RFC 7592). Please carefully store it along with the `client_secret`, they will never be retrieved by anyway.
+// @Tags OAuth2 Client
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer initial access token"
+// @Param body body dto.OAuth2ClientMetadata true "Client Metadata"
+// @Success 201 {object} dto.OAuth2ClientRegistrationResponse "Register client successfully"
+// @Failure 400 {object} standard.Error "Bad request"
+// @Failure 401 {object} standard.Error "Unauthenticated"
+// @Failure 403 {object} standard.Error "Forbidden"
+// @Router /oauth2/register [post]
+func (a *OAuth2ClientAdapter) Register() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := dto.NewOAuth2ClientRegisterRequest(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.Register(ctx, req)
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientRegistrationResponse(a.baseURL, resp), err).
+			Map(http.StatusBadRequest, usecase.ErrClientMetadataInvalid, usecase.ErrRedirectURIInvalid).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			WithDefaultCode(http.StatusCreated).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary Read client configuration
+// @Description Read the current metadata of a dynamically registered OAuth2 Client (RFC 7592). The client secret is not returned.
+// @Tags OAuth2 Client
+// @Produce json
+// @Param Authorization header string true "Bearer registration access token"
+// @Param client_id path string true "ClientID"
+// @Success 200 {object} dto.OAuth2ClientRegistrationResponse "Read client successfully"
+// @Failure 401 {object} standard.Error "Invalid registration access token"
+// @Router /oauth2/register/{client_id} [get]
+func (a *OAuth2ClientAdapter) ReadRegistration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2ClientReadRegistrationRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.ReadRegistration(ctx, req.To(r))
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientRegistrationResponse(a.baseURL, resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrUnauthenticated).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary Update client configuration
+// @Description Replace the metadata of a dynamically registered OAuth2 Client (RFC 7592), the omitted fields are reset to their default values. A confidential client can not become public and vice versa.
+// @Tags OAuth2 Client
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer registration access token"
+// @Param client_id path string true "ClientID"
+// @Param body body dto.OAuth2ClientMetadata true "Client Metadata"
+// @Success 200 {object} dto.OAuth2ClientRegistrationResponse "Update client successfully"
+// @Failure 400 {object} standard.Error "Bad request"
+// @Failure 401 {object} standard.Error "Invalid registration access token"
+// @Router /oauth2/register/{client_id} [put]
+func (a *OAuth2ClientAdapter) UpdateRegistration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2ClientUpdateRegistrationRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		metadata, err := dto.NewOAuth2ClientMetadata(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2ClientUsecase.UpdateRegistration(ctx, req.To(r, metadata))
+		response.NewResponseHandler(ctx, dto.NewOAuth2ClientRegistrationResponse(a.baseURL, resp), err).
+			Map(http.StatusBadRequest, usecase.ErrClientMetadataInvalid, usecase.ErrRedirectURIInvalid).
+			Map(http.StatusUnauthorized, usecase.ErrUnauthenticated).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary Delete client configuration
+// @Description Deregister a dynamically registered OAuth2 Client (RFC 7592). The client can not be used anymore.
+// @Tags OAuth2 Client
+// @Param Authorization header string true "Bearer registration access token"
+// @Param client_id path string true "ClientID"
+// @Success 204 "Delete client successfully"
+// @Failure 401 {object} standard.Error "Invalid registration access token"
+// @Router /oauth2/register/{client_id} [delete]
+func (a *OAuth2ClientAdapter) DeleteRegistration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// The request has no body, xhttp rejects delete requests without a
+		// content type.
+		req := dto.OAuth2ClientDeleteRegistrationRequest{ClientID: chi.URLParam(r, "client_id")}
+
+		_, err := a.oauth2ClientUsecase.DeleteRegistration(ctx, req.To(r))
+		if err != nil {
+			response.NewResponseHandler(ctx, nil, err).
+				Map(http.StatusUnauthorized, usecase.ErrUnauthenticated).
+				WriteHTTPResponseWithoutWrap(ctx, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,29 +1,45 @@
 package rest
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"html/template"
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/xybor/todennus-backend/adapter/abstraction"
 	"github.com/xybor/todennus-backend/adapter/rest/dto"
+	"github.com/xybor/todennus-backend/adapter/rest/middleware"
 	"github.com/xybor/todennus-backend/adapter/rest/response"
 	"github.com/xybor/todennus-backend/usecase"
+	"github.com/xybor/x/xcontext"
 	"github.com/xybor/x/xhttp"
 )
 
 type OAuth2Adapter struct {
+	baseURL       string
 	oauth2Usecase abstraction.OAuth2Usecase
 }
 
-func NewOAuth2Adapter(oauth2Usecase abstraction.OAuth2Usecase) *OAuth2Adapter {
-	return &OAuth2Adapter{oauth2Usecase: oauth2Usecase}
+func NewOAuth2Adapter(baseURL string, oauth2Usecase abstraction.OAuth2Usecase) *OAuth2Adapter {
+	return &OAuth2Adapter{baseURL: baseURL, oauth2Usecase: oauth2Usecase}
 }
 
 func (a *OAuth2Adapter) OAuth2Router(r chi.Router) {
 	r.Get("/authorize", a.Authorize())
+	r.Post("/par", a.PushAuthorization())
 	r.Post("/token", a.Token())
+	r.Post("/revoke", a.Revoke())
+	r.Post("/introspect", a.Introspect())
+
+	r.Post("/device_authorization", a.DeviceAuthorization())
+	r.Get("/device", a.GetDevicePage())
+	r.Post("/device", a.ConfirmDevice())
+
+	r.Post("/bc-authorize", a.BackchannelAuthorize())
+	r.Post("/bc-authorize/consent", middleware.RequireAuthentication(a.BackchannelConsent()))
 
 	r.Get("/consent", a.GetConsentPage())
 	r.Post("/consent", a.UpdateConsent())
@@ -33,13 +49,21 @@ func (a *OAuth2Adapter) OAuth2Router(r chi.Router) {
 // @Description The authorization endpoint is used to interact with the resource owner and obtain an authorization grant.
 // @Description This is the entry point for starting an OAuth2 flow, such as Authorization Code or Implicit.
 // @Tags OAuth2
-// @Param response_type query string true "The type of response requested, typically 'code' or 'token'."
+// @Param response_type query string true "The type of response requested: 'code', 'id_token', 'code id_token', or 'token' (only for clients allowing implicit flow). Tokens are returned in the fragment of the redirect URI."
 // @Param client_id query string true "The client ID of the application making the authorization request."
-// @Param redirect_uri query string true "The URI to which the response will be sent after the authorization."
+// @Param redirect_uri query string true "The URI to which the response will be sent after the authorization. It must be one of the redirect URIs registered by the client."
 // @Param scope query string false "The scope of the access request. It defines the level of access the application is requesting."
 // @Param state query string false "An opaque value used by the client to maintain state between the request and callback."
-// @Success 303 "Redirect to client application with authorization code or error"
-// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Param response_mode query string false "How the response is returned to the client: 'query', 'fragment', or 'form_post'. The default is 'query' for 'code' and 'fragment' for the others. The 'query' mode is not allowed if tokens are issued."
+// @Param code_challenge query string false "PKCE code challenge. Required for public clients if the response type contains 'code'."
+// @Param code_challenge_method query string false "PKCE code challenge method: 'S256' or 'plain' (the default, unless it is forbidden by the server or the client)."
+// @Param nonce query string false "OpenID Connect only. The value is passed through unmodified to the ID token to mitigate replay attacks. Required if the response type contains 'id_token'."
+// @Param request_uri query string false "The request URI returned by the pushed authorization request endpoint. If it is provided, all parameters other than client_id are loaded from the pushed request."
+// @Param request query string false "A request object (RFC 9101), which is a JWT signed with RS256 by a key in the JWKS of the client. Only its claims are used, the other query parameters are ignored. It must be issued by the client to the issuer and expire within an hour. It must not be used with request_uri."
+// @Produce html
+// @Success 200 "Render an auto-submitting form posting the response to the client if response_mode is 'form_post'"
+// @Success 303 "Redirect to client application with authorization code, tokens, or error"
+// @Failure 400 "Render an error page if the client or the redirect URI is invalid"
 // @Router /oauth2/authorize [get]
 func (a *OAuth2Adapter) Authorize() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -51,8 +75,43 @@ func (a *OAuth2Adapter) Authorize() http.HandlerFunc {
 			return
 		}
 
+		// The response is sent according to the pushed parameters or the
+		// request object rather than the query.
+		if req.RequestURI != "" || req.Request != "" {
+			resolved, err := a.oauth2Usecase.ResolveAuthorization(ctx, req.To())
+			if err != nil {
+				code := http.StatusBadRequest
+				if errors.Is(err, usecase.ErrServer) {
+					code = http.StatusInternalServerError
+				}
+
+				renderErrorPage(w, r, code, dto.NewOAuth2ErrorPageResponse(ctx, err))
+				return
+			}
+
+			req = dto.NewOAuth2AuthorizeRequestFromResolved(resolved)
+		}
+
 		resp, err := a.oauth2Usecase.Authorize(ctx, req.To())
 		if err != nil {
+			// Never redirect to the redirect uri before it is verified to be
+			// registered by the client.
+			if errors.Is(err, usecase.ErrServer) {
+				renderErrorPage(w, r, http.StatusInternalServerError, dto.NewOAuth2ErrorPageResponse(ctx, err))
+				return
+			}
+
+			if errors.Is(err, usecase.ErrClientInvalid) || errors.Is(err, usecase.ErrRedirectURIInvalid) ||
+				errors.Is(err, usecase.ErrRequestURIInvalid) || errors.Is(err, usecase.ErrRequestObjectInvalid) {
+				renderErrorPage(w, r, http.StatusBadRequest, dto.NewOAuth2ErrorPageResponse(ctx, err))
+				return
+			}
+
+			if req.IsFormPost() {
+				renderFormPostPage(w, r, dto.NewOAuth2FormPostPageResponseWithError(ctx, req, err))
+				return
+			}
+
 			if url, err := dto.NewOAuth2AuthorizeRedirectURIWithError(ctx, req, err); err != nil {
 				response.HandleError(ctx, w, err)
 			} else {
@@ -62,6 +121,19 @@ func (a *OAuth2Adapter) Authorize() http.HandlerFunc {
 			return
 		}
 
+		// Redirections to the IdP and the consent page are not affected by the
+		// response mode.
+		if req.IsFormPost() && resp.IdpURL == "" && !resp.NeedConsent {
+			data, err := dto.NewOAuth2FormPostPageResponse(req, resp)
+			if err != nil {
+				response.HandleError(ctx, w, err)
+				return
+			}
+
+			renderFormPostPage(w, r, data)
+			return
+		}
+
 		redirectURI, err := dto.NewOAuth2AuthorizeRedirectURI(req, resp)
 		if err != nil {
 			response.HandleError(ctx, w, err)
@@ -72,21 +144,90 @@ func (a *OAuth2Adapter) Authorize() http.HandlerFunc {
 	}
 }
 
+// @Summary OAuth2 Pushed Authorization Request Endpoint
+// @Description The pushed authorization request endpoint allows the client to push the parameters of an authorization request directly to the server (RFC 9126). <br>
+// @Description The returned request URI is used once at the authorization endpoint along with the client ID, instead of the parameters in the query.
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
+// @Param response_type formData string true "The type of response requested, the same as the authorization endpoint"
+// @Param response_mode formData string false "How the response is returned to the client, the same as the authorization endpoint"
+// @Param redirect_uri formData string true "The URI to which the response will be sent after the authorization"
+// @Param scope formData string false "The scope of the access request"
+// @Param state formData string false "An opaque value used by the client to maintain state between the request and callback"
+// @Param code_challenge formData string false "PKCE code challenge"
+// @Param code_challenge_method formData string false "PKCE code challenge method"
+// @Param nonce formData string false "OpenID Connect only. The value is passed through unmodified to the ID token"
+// @Param request formData string false "A request object signed by the client, the same as the authorization endpoint"
+// @Success 201 {object} dto.OAuth2PushAuthorizationResponse "Successfully pushed the authorization request"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
+// @Router /oauth2/par [post]
+func (a *OAuth2Adapter) PushAuthorization() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2PushAuthorizationRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.PushAuthorization(ctx, req.To(auth))
+		setClientAuthenticateHeader(w, r, err)
+		response.NewResponseHandler(ctx, dto.NewOAuth2PushAuthorizationResponse(resp), err).
+			WithDefaultCode(http.StatusCreated).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
+			Map(http.StatusBadRequest,
+				usecase.ErrRequestInvalid, usecase.ErrRedirectURIInvalid,
+				usecase.ErrScopeInvalid, usecase.ErrUnauthorizedClient,
+			).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
 // @Summary OAuth2 Token Endpoint
 // @Description The token endpoint is used to exchange an authorization code, client credentials, or refresh token for an access token and optionally a refresh token.
+// @Description Confidential clients can also exchange an access token of a user for another one with a narrower scope or another audience (RFC 8693).
 // @Description This is part of the OAuth2 flow to grant access tokens to clients.
 // @Tags OAuth2
 // @Accept application/x-www-form-urlencoded
 // @Produce json
-// @Param grant_type formData string true "The OAuth2 grant type (authorization_code, client_credentials, refresh_token)"
+// @Param grant_type formData string true "The OAuth2 grant type (authorization_code, client_credentials, refresh_token, urn:ietf:params:oauth:grant-type:device_code, urn:ietf:params:oauth:grant-type:token-exchange, urn:ietf:params:oauth:grant-type:jwt-bearer, urn:openid:params:grant-type:ciba)"
 // @Param code formData string false "The authorization code received from the authorize endpoint (required for authorization_code grant type)"
 // @Param redirect_uri formData string false "The redirect URI used in the authorization request (required for authorization_code grant type)"
-// @Param client_id formData string true "The client ID of the application"
-// @Param client_secret formData string true "The client secret of the application"
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
 // @Param refresh_token formData string false "The refresh token (required for refresh_token grant type)"
-// @Param scope formData string false "The scope of the access request (optional, space-separated)"
+// @Param device_code formData string false "The device code (required for urn:ietf:params:oauth:grant-type:device_code grant type)"
+// @Param auth_req_id formData string false "The id returned by the backchannel authentication endpoint (required for urn:openid:params:grant-type:ciba grant type)"
+// @Param assertion formData string false "A JWT signed by a trusted issuer, whose subject identifies the user by the user ID or the username (required for jwt-bearer grant type). Its audience must identify this server and its lifetime must not exceed one hour"
+// @Param subject_token formData string false "The access token of the party on behalf of whom the token is requested (required for token-exchange grant type)"
+// @Param subject_token_type formData string false "urn:ietf:params:oauth:token-type:access_token (required for token-exchange grant type)"
+// @Param actor_token formData string false "The access token of the acting party. If it is provided, the issued token records the actor in the act claim, otherwise the client impersonates the subject"
+// @Param actor_token_type formData string false "urn:ietf:params:oauth:token-type:access_token (required if actor_token is provided)"
+// @Param requested_token_type formData string false "urn:ietf:params:oauth:token-type:access_token, the only supported type"
+// @Param scope formData string false "The scope of the access request (optional, space-separated). For refresh_token grant type, it must not exceed the originally granted scope. For token-exchange grant type, it must not exceed the scope of the subject token and is narrowed to the allowed scope of the client"
+// @Param resource formData string false "The resource server where the access token is used (RFC 8707). It must be one of the allowed resources of the client, the audience is the client itself if it is omitted"
+// @Param audience formData string false "The same as resource, only for token-exchange grant type. It must equal resource if both are provided"
+// @Param DPoP header string false "A DPoP proof (RFC 9449) signed with RS256 or ES256. The issued access token has the DPoP type and is bound to the key of the proof, so are the refresh tokens of public clients. The proof must contain a nonce provided by the DPoP-Nonce response header"
 // @Success 200 {object} dto.OAuth2TokenResponse "Successfully generated access token"
-// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request, or use_dpop_nonce with a fresh nonce in the DPoP-Nonce header"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
 // @Router /oauth2/token [post]
 func (a *OAuth2Adapter) Token() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -98,16 +239,320 @@ func (a *OAuth2Adapter) Token() http.HandlerFunc {
 			return
 		}
 
-		resp, err := a.oauth2Usecase.Token(ctx, req.To())
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		dpop, err := dto.NewOAuth2DPoP(r, a.baseURL)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.Token(ctx, req.To(auth, dpop))
+		setClientAuthenticateHeader(w, r, err)
+		a.setDPoPNonceHeader(ctx, w, err)
 		response.NewResponseHandler(ctx, dto.NewOAuth2TokenResponse(resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
+			Map(http.StatusBadRequest,
+				usecase.ErrRequestInvalid,
+				usecase.ErrScopeInvalid, usecase.ErrTargetInvalid, usecase.ErrTokenInvalidGrant,
+				usecase.ErrAuthorizationAccessDenied, usecase.ErrTokenAuthorizationPending,
+				usecase.ErrTokenSlowDown, usecase.ErrTokenExpired,
+				usecase.ErrDPoPProofInvalid, usecase.ErrUseDPoPNonce, usecase.ErrUnauthorizedClient,
+			).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary OAuth2 Token Revocation Endpoint
+// @Description The revocation endpoint is used by clients to notify the server that a refresh token or an access token is no longer needed (RFC 7009). <br>
+// @Description Revoking a refresh token also revokes all refresh tokens rotated from the same grant. Invalid or unknown tokens do not cause an error.
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "The refresh token or access token to be revoked"
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
+// @Success 200 "Successfully revoked the token"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
+// @Router /oauth2/revoke [post]
+func (a *OAuth2Adapter) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2RevokeRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.Revoke(ctx, req.To(auth))
+		setClientAuthenticateHeader(w, r, err)
+		response.NewResponseHandler(ctx, dto.NewOAuth2RevokeResponse(resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary OAuth2 Token Introspection Endpoint
+// @Description The introspection endpoint is used by resource servers to query the state and the metadata of a refresh token or an access token (RFC 7662). <br>
+// @Description Only confidential clients can call this endpoint. Expired, revoked, or rotated tokens are reported as inactive.
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "The refresh token or access token to be introspected"
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
+// @Success 200 {object} dto.OAuth2IntrospectResponse "Successfully introspected the token"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
+// @Router /oauth2/introspect [post]
+func (a *OAuth2Adapter) Introspect() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2IntrospectRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.Introspect(ctx, req.To(auth))
+		setClientAuthenticateHeader(w, r, err)
+		response.NewResponseHandler(ctx, dto.NewOAuth2IntrospectResponse(resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary OAuth2 Device Authorization Endpoint
+// @Description The device authorization endpoint is used by devices with limited input capabilities (CLI, TV, ...) to start the Device Flow (RFC 8628). <br>
+// @Description The device displays the `user_code` and the `verification_uri` to the user, then polls the token endpoint with the `device_code`.
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
+// @Param scope formData string false "The scope of the access request (optional, space-separated)"
+// @Success 200 {object} dto.OAuth2DeviceAuthorizationResponse "Successfully issued device code"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
+// @Router /oauth2/device_authorization [post]
+func (a *OAuth2Adapter) DeviceAuthorization() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2DeviceAuthorizationRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		verificationURI := fmt.Sprintf("%s/oauth2/device", a.baseURL)
+
+		resp, err := a.oauth2Usecase.DeviceAuthorization(ctx, req.To(auth))
+		setClientAuthenticateHeader(w, r, err)
+		response.NewResponseHandler(ctx, dto.NewOAuth2DeviceAuthorizationResponse(verificationURI, resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid, usecase.ErrScopeInvalid, usecase.ErrUnauthorizedClient).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary CIBA Backchannel Authentication Endpoint
+// @Description The backchannel authentication endpoint lets a confidential client start the authentication of a user without redirecting the user (OpenID Connect CIBA). <br>
+// @Description The user is notified and approves the request on their own device, then the client gets the tokens from the token endpoint with the `auth_req_id`, by polling (poll mode) or after being notified at its client notification endpoint (ping mode).
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param Authorization header string false "HTTP Basic authentication with the client ID and the client secret (client_secret_basic)"
+// @Param client_id formData string false "The client ID of the application, unless it is sent in the Authorization header or the client assertion. It is required for tls_client_auth and self_signed_tls_client_auth, which authenticate the client by the certificate of the mutual-TLS connection"
+// @Param client_secret formData string false "The client secret of the application (client_secret_post)"
+// @Param client_assertion_type formData string false "urn:ietf:params:oauth:client-assertion-type:jwt-bearer (private_key_jwt)"
+// @Param client_assertion formData string false "A JWT signed by a key in the JWKS of the client (private_key_jwt)"
+// @Param scope formData string true "The scope of the access request (space-separated), which must contain openid"
+// @Param login_hint formData string true "The username of the user to be authenticated"
+// @Param binding_message formData string false "A short message shown on both the client and the device of the user, so that the user can check the request (at most 64 characters)"
+// @Param client_notification_token formData string false "The bearer token the server uses to notify the client (required in ping mode)"
+// @Param requested_expiry formData int false "The requested lifetime of the request in seconds, at most 1800"
+// @Success 200 {object} dto.OAuth2BackchannelAuthorizeResponse "Successfully started the backchannel authentication"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request, unknown_user_id, or invalid_binding_message"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Invalid client credentials"
+// @Router /oauth2/bc-authorize [post]
+func (a *OAuth2Adapter) BackchannelAuthorize() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2BackchannelAuthorizeRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		auth, err := dto.NewOAuth2ClientAuthentication(r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.BackchannelAuthorize(ctx, req.To(auth))
+		setClientAuthenticateHeader(w, r, err)
+		response.NewResponseHandler(ctx, dto.NewOAuth2BackchannelAuthorizeResponse(resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrClientInvalid).
 			Map(http.StatusBadRequest,
-				usecase.ErrRequestInvalid, usecase.ErrClientInvalid,
-				usecase.ErrScopeInvalid, usecase.ErrTokenInvalidGrant,
+				usecase.ErrRequestInvalid, usecase.ErrScopeInvalid, usecase.ErrUnauthorizedClient,
+				usecase.ErrUnknownUserID, usecase.ErrBindingMessageInvalid,
 			).
 			WriteHTTPResponseWithoutWrap(ctx, w)
 	}
 }
 
+// @Summary CIBA consent
+// @Description The user approves or denies the backchannel authentication request which they are notified of. The authorization ID is delivered in the notification. <br>
+// @Description The accepted scope is remembered as the consent of the user to the client, the same as the consent page. <br>
+// @Description Require scope `[todennus]update:consent`.
+// @Tags OAuth2
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param authorization_id query string true "Authorization ID"
+// @Param consent formData string false "The consent result (accepted or denied)"
+// @Param scope formData string false "The accepted scopes of user (usually less than the requested scope)."
+// @Success 200 {object} dto.OAuth2BackchannelConsentResponse "Successfully recorded the decision of the user"
+// @Failure 400 {object} standard.SwaggerBadRequestErrorResponse "Bad request"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Unauthenticated"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "The request is not sent to this user or insufficient scope"
+// @Router /oauth2/bc-authorize/consent [post]
+func (a *OAuth2Adapter) BackchannelConsent() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2BackchannelConsentRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.BackchannelConsent(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOAuth2BackchannelConsentResponse(resp), err).
+			Map(http.StatusBadRequest, usecase.ErrRequestInvalid, usecase.ErrScopeInvalid).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary Device verification page
+// @Description This endpoint serves the page where the user enters the user code displayed on the device. <br>
+// @Description The user is redirected to the IdP and the consent page if needed, then back to this page to confirm the user code and the client.
+// @Description The user codes entered from the same address are rate-limited.
+// @Tags OAuth2
+// @Produce text/html
+// @Param user_code query string false "The user code displayed on the device"
+// @Success 200 {string} string "Device page rendered successfully"
+// @Success 303 "Redirect to IdP or consent page"
+// @Failure 400 {string} string "Device page rendered with error"
+// @Failure 429 {string} string "Too many user codes were entered"
+// @Router /oauth2/device [get]
+func (a *OAuth2Adapter) GetDevicePage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2DeviceVerifyRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		if req.UserCode == "" {
+			renderDevicePage(w, r, http.StatusOK, dto.NewOAuth2DevicePageResponse("", nil))
+			return
+		}
+
+		resp, err := a.oauth2Usecase.DeviceVerify(ctx, req.To(clientIP(r)))
+		if err != nil {
+			renderDevicePage(w, r, devicePageErrorCode(err), dto.NewOAuth2DevicePageErrorResponse(ctx, req.UserCode, err))
+			return
+		}
+
+		redirectURI, err := dto.NewOAuth2DeviceVerifyRedirectURI(resp)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		if redirectURI != "" {
+			response.Redirect(ctx, w, r, redirectURI, http.StatusSeeOther)
+			return
+		}
+
+		renderDevicePage(w, r, http.StatusOK, dto.NewOAuth2DevicePageResponse(req.UserCode, resp))
+	}
+}
+
+// @Summary Device confirmation
+// @Description This endpoint is submitted by the device verification page after the user confirmed the user code and the client. <br>
+// @Description The device is approved or denied only after this confirmation (RFC 8628, section 5.4).
+// @Tags OAuth2
+// @Accept x-www-form-urlencoded
+// @Produce text/html
+// @Param authorization_id formData string true "The authorization id of the confirmation"
+// @Param user_code formData string true "The user code displayed on the device"
+// @Param accept formData bool true "Whether the user approves the device"
+// @Success 200 {string} string "Device page rendered successfully"
+// @Failure 400 {string} string "Device page rendered with error"
+// @Router /oauth2/device [post]
+func (a *OAuth2Adapter) ConfirmDevice() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, err := xhttp.ParseHTTPRequest[dto.OAuth2DeviceConfirmRequest](r)
+		if err != nil {
+			response.HandleError(ctx, w, err)
+			return
+		}
+
+		resp, err := a.oauth2Usecase.DeviceConfirm(ctx, req.To())
+		if err != nil {
+			renderDevicePage(w, r, devicePageErrorCode(err), dto.NewOAuth2DevicePageErrorResponse(ctx, req.UserCode, err))
+			return
+		}
+
+		renderDevicePage(w, r, http.StatusOK, dto.NewOAuth2DeviceConfirmPageResponse(resp))
+	}
+}
+
 // @Summary Authentication Callback Endpoint
 // @Description This endpoint is called by the IdP after it validated the user.
 // @Description It notifies to the server about the authentication result (success or failure) and the inforamtion of user.
@@ -237,3 +682,101 @@ func (a *OAuth2Adapter) UpdateConsent() http.HandlerFunc {
 			Redirect(ctx, w, r, http.StatusSeeOther)
 	}
 }
+
+func devicePageErrorCode(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrRequestInvalid), errors.Is(err, usecase.ErrAuthorizationAccessDenied),
+		errors.Is(err, usecase.ErrScopeInvalid):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// clientIP returns the address of the user agent, the RealIP middleware has
+// already replaced the remote address if the server is behind a proxy.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func renderDevicePage(w http.ResponseWriter, r *http.Request, code int, data *dto.OAuth2DevicePageResponse) {
+	ctx := r.Context()
+
+	tmpl, err := template.ParseFiles("template/device.html")
+	if err != nil {
+		response.WriteError(ctx, w, http.StatusInternalServerError,
+			usecase.ErrServer.Hide(err, "failed-to-parse-template"))
+		return
+	}
+
+	w.WriteHeader(code)
+	if err = tmpl.Execute(w, data); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-render-template", "err", err)
+	}
+}
+
+func renderFormPostPage(w http.ResponseWriter, r *http.Request, data *dto.OAuth2FormPostPageResponse) {
+	ctx := r.Context()
+
+	tmpl, err := template.ParseFiles("template/form_post.html")
+	if err != nil {
+		response.WriteError(ctx, w, http.StatusInternalServerError,
+			usecase.ErrServer.Hide(err, "failed-to-parse-template"))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err = tmpl.Execute(w, data); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-render-template", "err", err)
+	}
+}
+
+func renderErrorPage(w http.ResponseWriter, r *http.Request, code int, data *dto.OAuth2ErrorPageResponse) {
+	ctx := r.Context()
+
+	tmpl, err := template.ParseFiles("template/error.html")
+	if err != nil {
+		response.WriteError(ctx, w, http.StatusInternalServerError,
+			usecase.ErrServer.Hide(err, "failed-to-parse-template"))
+		return
+	}
+
+	w.WriteHeader(code)
+	if err = tmpl.Execute(w, data); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-render-template", "err", err)
+	}
+}
+
+// setClientAuthenticateHeader challenges the client to authenticate again if
+// it failed to authenticate with the Authorization header (RFC 6749 Section
+// 5.2).
+func setClientAuthenticateHeader(w http.ResponseWriter, r *http.Request, err error) {
+	if _, _, ok := r.BasicAuth(); ok && errors.Is(err, usecase.ErrClientInvalid) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth2"`)
+	}
+}
+
+// setDPoPNonceHeader provides a fresh nonce if the client is challenged to
+// use one in its DPoP proof (RFC 9449 Section 8).
+func (a *OAuth2Adapter) setDPoPNonceHeader(ctx context.Context, w http.ResponseWriter, err error) {
+	if !errors.Is(err, usecase.ErrUseDPoPNonce) {
+		return
+	}
+
+	req := dto.OAuth2CreateDPoPNonceRequest{}
+	resp, err := a.oauth2Usecase.CreateDPoPNonce(ctx, req.To())
+	if err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-create-dpop-nonce", "err", err)
+		return
+	}
+
+	w.Header().Set("DPoP-Nonce", resp.Nonce)
+}
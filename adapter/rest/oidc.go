@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/xybor/todennus-backend/adapter/abstraction"
+	"github.com/xybor/todennus-backend/adapter/rest/dto"
+	"github.com/xybor/todennus-backend/adapter/rest/response"
+	"github.com/xybor/todennus-backend/usecase"
+)
+
+type OIDCAdapter struct {
+	baseURL     string
+	oidcUsecase abstraction.OIDCUsecase
+}
+
+func NewOIDCAdapter(baseURL string, oidcUsecase abstraction.OIDCUsecase) *OIDCAdapter {
+	return &OIDCAdapter{baseURL: baseURL, oidcUsecase: oidcUsecase}
+}
+
+func (a *OIDCAdapter) WellKnownRouter(r chi.Router) {
+	r.Get("/openid-configuration", a.GetDiscovery())
+	r.Get("/jwks.json", a.GetJWKS())
+}
+
+// @Summary OpenID Connect Discovery
+// @Description Get the OpenID Provider metadata, which describes the endpoints and capabilities of the server.
+// @Tags OpenID Connect
+// @Produce json
+// @Success 200 {object} dto.OIDCGetDiscoveryResponse "Provider metadata"
+// @Router /.well-known/openid-configuration [get]
+func (a *OIDCAdapter) GetDiscovery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req := dto.OIDCGetDiscoveryRequest{}
+		resp, err := a.oidcUsecase.GetDiscovery(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOIDCGetDiscoveryResponse(a.baseURL, resp), err).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary JSON Web Key Set
+// @Description Get the public keys which are used to verify the tokens issued by the server.
+// @Tags OpenID Connect
+// @Produce json
+// @Success 200 {object} dto.OIDCGetJWKSResponse "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (a *OIDCAdapter) GetJWKS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req := dto.OIDCGetJWKSRequest{}
+		resp, err := a.oidcUsecase.GetJWKS(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOIDCGetJWKSResponse(resp), err).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
+
+// @Summary OpenID Connect UserInfo Endpoint
+// @Description Get the claims of the authenticated user. The access token must have the `openid` scope, the `profile` scope is required to get `preferred_username` and `name`.
+// @Tags OpenID Connect
+// @Produce json
+// @Success 200 {object} dto.OIDCGetUserInfoResponse "User claims"
+// @Failure 401 {object} standard.SwaggerUnauthorizedErrorResponse "Unauthorized"
+// @Failure 403 {object} standard.SwaggerForbiddenErrorResponse "Forbidden"
+// @Router /oauth2/userinfo [get]
+// @Router /oauth2/userinfo [post]
+func (a *OIDCAdapter) GetUserInfo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req := dto.OIDCGetUserInfoRequest{}
+		resp, err := a.oidcUsecase.GetUserInfo(ctx, req.To())
+		response.NewResponseHandler(ctx, dto.NewOIDCGetUserInfoResponse(resp), err).
+			Map(http.StatusUnauthorized, usecase.ErrUnauthenticated).
+			Map(http.StatusForbidden, usecase.ErrForbidden).
+			WriteHTTPResponseWithoutWrap(ctx, w)
+	}
+}
@@ -19,7 +19,17 @@ var Command = &cobra.Command{
 			panic(err)
 		}
 
-		system, ctx, err := wiring.InitializeSystem(envPaths...)
+		trustedIssuers, err := cmd.Flags().GetString("trusted-issuers")
+		if err != nil {
+			panic(err)
+		}
+
+		allowPlainPKCE, err := cmd.Flags().GetBool("allow-plain-pkce")
+		if err != nil {
+			panic(err)
+		}
+
+		system, ctx, err := wiring.InitializeSystem(trustedIssuers, allowPlainPKCE, envPaths...)
 		if err != nil {
 			panic(err)
 		}
@@ -14,6 +14,8 @@ var rootCommand = &cobra.Command{
 
 func main() {
 	rootCommand.PersistentFlags().StringArray("env", []string{".env"}, "environment file paths")
+	rootCommand.PersistentFlags().String("trusted-issuers", "", "json file of the external issuers trusted for the jwt bearer grant")
+	rootCommand.PersistentFlags().Bool("allow-plain-pkce", false, "allow the plain code challenge method of pkce")
 	rootCommand.AddCommand(rest.Command)
 	rootCommand.AddCommand(grpc.Command)
 	rootCommand.AddCommand(swagger.Command)
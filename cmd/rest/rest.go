@@ -1,11 +1,16 @@
 package rest
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/xybor/todennus-backend/adapter/rest"
+	"github.com/xybor/todennus-backend/adapter/rest/middleware"
 	"github.com/xybor/todennus-backend/wiring"
 	"github.com/xybor/x/xcontext"
 )
@@ -19,17 +24,102 @@ var Command = &cobra.Command{
 			panic(err)
 		}
 
-		system, ctx, err := wiring.InitializeSystem(envPaths...)
+		tlsCert, err := cmd.Flags().GetString("tls-cert")
 		if err != nil {
 			panic(err)
 		}
 
+		tlsKey, err := cmd.Flags().GetString("tls-key")
+		if err != nil {
+			panic(err)
+		}
+
+		clientCA, err := cmd.Flags().GetString("client-ca")
+		if err != nil {
+			panic(err)
+		}
+
+		clientCertHeader, err := cmd.Flags().GetString("client-cert-header")
+		if err != nil {
+			panic(err)
+		}
+
+		trustedIssuers, err := cmd.Flags().GetString("trusted-issuers")
+		if err != nil {
+			panic(err)
+		}
+
+		allowPlainPKCE, err := cmd.Flags().GetBool("allow-plain-pkce")
+		if err != nil {
+			panic(err)
+		}
+
+		system, ctx, err := wiring.InitializeSystem(trustedIssuers, allowPlainPKCE, envPaths...)
+		if err != nil {
+			panic(err)
+		}
+
+		roots, err := loadCertPool(clientCA)
+		if err != nil {
+			panic(err)
+		}
+
+		var certificateSource middleware.ClientCertificateSource
+		if clientCertHeader != "" {
+			certificateSource = middleware.NewHeaderClientCertificateSource(clientCertHeader, roots)
+		} else {
+			certificateSource = middleware.NewTLSClientCertificateSource(roots)
+		}
+
 		address := fmt.Sprintf("%s:%d", system.Config.Variable.Server.Host, system.Config.Variable.Server.Port)
-		app := rest.App(system.Config, system.Infras, system.Usecases)
+		app := rest.App(system.Config, system.Infras, system.Usecases, certificateSource)
 
-		xcontext.Logger(ctx).Info("Server started", "address", address)
-		if err := http.ListenAndServe(address, app); err != nil {
+		if tlsCert == "" && tlsKey == "" {
+			xcontext.Logger(ctx).Info("Server started", "address", address)
+			if err := http.ListenAndServe(address, app); err != nil {
+				panic(err)
+			}
+
+			return
+		}
+
+		// Client certificates are verified by the certificate source, so
+		// that self-signed certificates can pass the handshake.
+		server := &http.Server{
+			Addr:      address,
+			Handler:   app,
+			TLSConfig: &tls.Config{ClientAuth: tls.RequestClientCert},
+		}
+
+		xcontext.Logger(ctx).Info("Server started with TLS", "address", address)
+		if err := server.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
 			panic(err)
 		}
 	},
 }
+
+func init() {
+	Command.Flags().String("tls-cert", "", "certificate file to serve https, client certificates are requested for mutual-tls")
+	Command.Flags().String("tls-key", "", "private key file of the tls certificate")
+	Command.Flags().String("client-ca", "", "pem file of the cas trusted to issue client certificates for tls_client_auth")
+	Command.Flags().String("client-cert-header", "",
+		"read the url-encoded pem client certificate from this header set by a trusted proxy instead of the tls connection")
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("not found any certificate in the client ca file")
+	}
+
+	return pool, nil
+}
@@ -1,7 +1,15 @@
 package domain
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -27,3 +35,118 @@ func ValidatePassword(hashedSecret, secret string) error {
 
 	return nil
 }
+
+// JSONWebKey is a public key in the JWK format (RFC 7517). Only RSA keys and
+// P-256 EC keys are supported.
+type JSONWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid,omitempty"`
+	Use     string `json:"use,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// ParseJSONWebKeySet parses the keys of a JSON Web Key Set, the keys are not
+// checked here.
+func ParseJSONWebKeySet(jwks string) ([]JSONWebKey, error) {
+	set := struct {
+		Keys []JSONWebKey `json:"keys"`
+	}{}
+
+	if err := json.Unmarshal([]byte(jwks), &set); err != nil {
+		return nil, Wrap(ErrJWKSInvalid, "failed to parse: %s", err)
+	}
+
+	return set.Keys, nil
+}
+
+func (jwk JSONWebKey) RSAPublicKey() (*rsa.PublicKey, error) {
+	if jwk.KeyType != "RSA" {
+		return nil, Wrap(ErrJWKSInvalid, "require an RSA key, but got %s", jwk.KeyType)
+	}
+
+	n, err := decodeJWKMember("n", jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := decodeJWKMember("e", jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	if key.N.Sign() <= 0 || key.E <= 1 {
+		return nil, Wrap(ErrJWKSInvalid, "invalid RSA key")
+	}
+
+	return key, nil
+}
+
+func (jwk JSONWebKey) ECPublicKey() (*ecdsa.PublicKey, error) {
+	if jwk.KeyType != "EC" {
+		return nil, Wrap(ErrJWKSInvalid, "require an EC key, but got %s", jwk.KeyType)
+	}
+
+	if jwk.Curve != "P-256" {
+		return nil, Wrap(ErrJWKSInvalid, "require the P-256 curve, but got %s", jwk.Curve)
+	}
+
+	x, err := decodeJWKMember("x", jwk.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeJWKMember("y", jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, Wrap(ErrJWKSInvalid, "the EC key is not on the curve")
+	}
+
+	return key, nil
+}
+
+// RSAKeyThumbprint calculates the JWK thumbprint (RFC 7638) of the RSA public
+// key.
+func RSAKeyThumbprint(key *rsa.PublicKey) string {
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n)))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// ECKeyThumbprint calculates the JWK thumbprint (RFC 7638) of a P-256 public
+// key, whose coordinates are padded to the size of the curve.
+func ECKeyThumbprint(key *ecdsa.PublicKey) string {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	x := base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+	y := base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, x, y)))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func decodeJWKMember(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, Wrap(ErrJWKSInvalid, "require the %s member of the jwk", name)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, Wrap(ErrJWKSInvalid, "invalid %s member of the jwk: %s", name, err)
+	}
+
+	return decoded, nil
+}
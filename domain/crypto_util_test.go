@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// The example key of RFC 7638, section 3.1.
+const (
+	testRSAKeyN          = "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+	testRSAKeyE          = "AQAB"
+	testRSAKeyThumbprint = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+)
+
+func TestRSAKeyThumbprint(t *testing.T) {
+	jwk := JSONWebKey{KeyType: "RSA", N: testRSAKeyN, E: testRSAKeyE}
+
+	key, err := jwk.RSAPublicKey()
+	if err != nil {
+		t.Fatalf("failed to parse the RSA key: %v", err)
+	}
+
+	if got := RSAKeyThumbprint(key); got != testRSAKeyThumbprint {
+		t.Fatalf("expect thumbprint %s, but got %s", testRSAKeyThumbprint, got)
+	}
+}
+
+func TestECKeyThumbprint(t *testing.T) {
+	// A coordinate with leading zero bytes must be padded to 32 bytes.
+	padded := new(big.Int).SetBytes([]byte{0x01, 0x02})
+	paddedKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: padded, Y: padded}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate an EC key: %v", err)
+	}
+
+	testcases := []struct {
+		name string
+		key  *ecdsa.PublicKey
+	}{
+		{"generated key", &privateKey.PublicKey},
+		{"short coordinates", paddedKey},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			x := base64.RawURLEncoding.EncodeToString(tc.key.X.FillBytes(make([]byte, 32)))
+			y := base64.RawURLEncoding.EncodeToString(tc.key.Y.FillBytes(make([]byte, 32)))
+
+			// The members are sorted lexicographically when marshaling a map,
+			// which is the canonical form of RFC 7638.
+			canonical, err := json.Marshal(map[string]string{"crv": "P-256", "kty": "EC", "x": x, "y": y})
+			if err != nil {
+				t.Fatalf("failed to marshal the key: %v", err)
+			}
+
+			hash := sha256.Sum256(canonical)
+			expected := base64.RawURLEncoding.EncodeToString(hash[:])
+
+			if got := ECKeyThumbprint(tc.key); got != expected {
+				t.Fatalf("expect thumbprint %s, but got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestParseJSONWebKeySet(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate an EC key: %v", err)
+	}
+
+	ecX := base64.RawURLEncoding.EncodeToString(privateKey.X.FillBytes(make([]byte, 32)))
+	ecY := base64.RawURLEncoding.EncodeToString(privateKey.Y.FillBytes(make([]byte, 32)))
+
+	testcases := []struct {
+		name      string
+		jwks      string
+		expectErr bool
+	}{
+		{
+			name: "rsa key",
+			jwks: `{"keys":[{"kty":"RSA","kid":"1","n":"` + testRSAKeyN + `","e":"` + testRSAKeyE + `"}]}`,
+		},
+		{
+			name: "ec key",
+			jwks: `{"keys":[{"kty":"EC","crv":"P-256","x":"` + ecX + `","y":"` + ecY + `"}]}`,
+		},
+		{
+			name:      "malformed json",
+			jwks:      `{"keys":`,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported key type",
+			jwks:      `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported curve",
+			jwks:      `{"keys":[{"kty":"EC","crv":"P-384","x":"` + ecX + `","y":"` + ecY + `"}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "point not on the curve",
+			jwks:      `{"keys":[{"kty":"EC","crv":"P-256","x":"` + ecX + `","y":"` + ecX + `"}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid base64",
+			jwks:      `{"keys":[{"kty":"RSA","n":"!!!","e":"AQAB"}]}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := parseTestJSONWebKeySet(tc.jwks)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expect error %v, but got %v", tc.expectErr, err)
+			}
+
+			if err != nil && !errors.Is(err, ErrJWKSInvalid) {
+				t.Fatalf("expect ErrJWKSInvalid, but got %v", err)
+			}
+		})
+	}
+}
+
+func parseTestJSONWebKeySet(jwks string) error {
+	keys, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		switch key.KeyType {
+		case "EC":
+			_, err = key.ECPublicKey()
+		default:
+			_, err = key.RSAPublicKey()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,6 +9,10 @@ type Resource struct {
 
 	User   *UserResource
 	Client *OAuth2ClientResource
+
+	// Consent is the decision of the user on the requests of clients, such as
+	// the backchannel authentication requests.
+	Consent *scope.BaseResource
 }
 
 type UserResource struct {
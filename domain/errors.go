@@ -15,8 +15,17 @@ var (
 
 	ErrMismatchedPassword = fmt.Errorf("%w%s", ErrKnown, "mismatched password")
 
-	ErrClientInvalid     = fmt.Errorf("%w%s", ErrKnown, "invalid client")
-	ErrClientNameInvalid = fmt.Errorf("%w%s", ErrKnown, "invalid client name")
+	ErrClientInvalid         = fmt.Errorf("%w%s", ErrKnown, "invalid client")
+	ErrClientNameInvalid     = fmt.Errorf("%w%s", ErrKnown, "invalid client name")
+	ErrRedirectURIInvalid    = fmt.Errorf("%w%s", ErrKnown, "invalid redirect uri")
+	ErrResourceInvalid       = fmt.Errorf("%w%s", ErrKnown, "invalid resource")
+	ErrRequestURIInvalid     = fmt.Errorf("%w%s", ErrKnown, "invalid request uri")
+	ErrJWKSInvalid           = fmt.Errorf("%w%s", ErrKnown, "invalid jwks")
+	ErrDPoPProofInvalid      = fmt.Errorf("%w%s", ErrKnown, "invalid dpop proof")
+	ErrAssertionInvalid      = fmt.Errorf("%w%s", ErrKnown, "invalid assertion")
+	ErrBindingMessageInvalid = fmt.Errorf("%w%s", ErrKnown, "invalid binding message")
+
+	ErrCodeChallengeInvalid = fmt.Errorf("%w%s", ErrKnown, "invalid code challenge")
 )
 
 func Wrap(err error, format string, a ...any) error {
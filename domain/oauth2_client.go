@@ -1,18 +1,47 @@
 package domain
 
 import (
-	"errors"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/xybor-x/snowflake"
 	"github.com/xybor/x/scope"
 	"github.com/xybor/x/xcrypto"
+	"github.com/xybor/x/xhttp"
 	"github.com/xybor/x/xstring"
 )
 
 const (
 	MaximumClientNameLength int = 64
 	MinimumClientNameLength int = 3
+
+	// RegistrationAccessTokenLength is the length of the token which manages
+	// the dynamically registered client (RFC 7592).
+	RegistrationAccessTokenLength int = 48
+)
+
+const (
+	TokenEndpointAuthMethodNone              = "none"
+	TokenEndpointAuthMethodClientSecretBasic = "client_secret_basic"
+	TokenEndpointAuthMethodClientSecretPost  = "client_secret_post"
+	TokenEndpointAuthMethodClientSecretJWT   = "client_secret_jwt"
+	TokenEndpointAuthMethodPrivateKeyJWT     = "private_key_jwt"
+
+	// Mutual-TLS client authentication methods (RFC 8705).
+	TokenEndpointAuthMethodTLSClientAuth           = "tls_client_auth"
+	TokenEndpointAuthMethodSelfSignedTLSClientAuth = "self_signed_tls_client_auth"
+)
+
+// Token delivery modes of the client initiated backchannel authentication.
+const (
+	BackchannelTokenDeliveryModePoll = "poll"
+	BackchannelTokenDeliveryModePing = "ping"
 )
 
 type ConfidentialRequirementType int
@@ -30,7 +59,64 @@ type OAuth2Client struct {
 	HashedSecret   string
 	IsConfidential bool
 	AllowedScope   scope.Scopes
+	RedirectURIs   []string
 	UpdatedAt      time.Time
+
+	// AllowedResources are the resource indicators (RFC 8707) which the
+	// client is allowed to request tokens for.
+	AllowedResources []string
+
+	// AllowImplicitFlow permits the client to receive access tokens directly
+	// from the authorization endpoint (response_type=token).
+	AllowImplicitFlow bool
+
+	// RequirePushedAuthorizationRequests rejects authorization requests of
+	// the client which are not pushed to the server beforehand (RFC 9126).
+	RequirePushedAuthorizationRequests bool
+
+	// JWKS is the JSON Web Key Set containing the public keys of the client.
+	// It is used to validate the request objects signed by the client.
+	JWKS string
+
+	// ForbidPlainCodeChallenge only accepts the S256 code challenge method
+	// from the client, even if the server allows the plain method.
+	ForbidPlainCodeChallenge bool
+
+	// TokenEndpointAuthMethod is the only method the client can use to
+	// authenticate itself. If it is empty, confidential clients can use both
+	// client_secret_basic and client_secret_post.
+	TokenEndpointAuthMethod string
+
+	// TLSClientAuthSubjectDN is the expected subject distinguished name of
+	// the certificate of the client using tls_client_auth.
+	TLSClientAuthSubjectDN string
+
+	// BackchannelTokenDeliveryMode is how the client gets the tokens of the
+	// backchannel authentication. If it is empty, the client is not allowed
+	// to initiate backchannel authentication.
+	BackchannelTokenDeliveryMode string
+
+	// BackchannelClientNotificationEndpoint is where the server notifies the
+	// client when the backchannel authentication completes in ping mode.
+	BackchannelClientNotificationEndpoint string
+
+	// GrantTypes and ResponseTypes restrict the flows the client can use.
+	// They are only registered by the dynamic client registration, the
+	// client can use all flows if GrantTypes is empty.
+	GrantTypes    []string
+	ResponseTypes []string
+
+	// LogoURI is the logo of the client shown on the consent page.
+	LogoURI string
+
+	// HashedRegistrationAccessToken is the hash of the token which manages
+	// the client through the client configuration endpoint (RFC 7592). It is
+	// empty if the client is not registered dynamically.
+	HashedRegistrationAccessToken string
+
+	// IsDisabled rejects all flows of the client until it is enabled again,
+	// the client is kept along with its consents and refresh tokens.
+	IsDisabled bool
 }
 
 type OAuth2ClientDomain struct {
@@ -48,14 +134,22 @@ func NewOAuth2ClientDomain(
 	}, nil
 }
 
-func (domain *OAuth2ClientDomain) CreateClient(ownerID snowflake.ID, name string, isConfidential bool) (*OAuth2Client, string, error) {
+func (domain *OAuth2ClientDomain) CreateClient(
+	ownerID snowflake.ID,
+	name string,
+	isConfidential bool,
+	redirectURIs []string,
+) (*OAuth2Client, string, error) {
 	err := domain.validateClientName(name)
 	if err != nil {
 		return nil, "", err
 	}
 
+	if err := domain.validateRedirectURIs(redirectURIs); err != nil {
+		return nil, "", err
+	}
+
 	secret := ""
-	allowedScope := ScopeEngine.New(Actions.Read, Resources).AsScopes()
 	hashedSecret := []byte{}
 	if isConfidential {
 		secret = xcrypto.RandString(domain.ClientSecretLength)
@@ -63,8 +157,6 @@ func (domain *OAuth2ClientDomain) CreateClient(ownerID snowflake.ID, name string
 		if err != nil {
 			return nil, "", err
 		}
-
-		allowedScope = ScopeEngine.New(Actions, Resources).AsScopes()
 	}
 
 	return &OAuth2Client{
@@ -72,11 +164,319 @@ func (domain *OAuth2ClientDomain) CreateClient(ownerID snowflake.ID, name string
 		Name:           name,
 		OwnerUserID:    ownerID,
 		IsConfidential: isConfidential,
-		AllowedScope:   allowedScope,
+		AllowedScope:   domain.defaultAllowedScope(isConfidential),
+		RedirectURIs:   redirectURIs,
 		HashedSecret:   string(hashedSecret),
 	}, secret, nil
 }
 
+func (domain *OAuth2ClientDomain) SetName(client *OAuth2Client, name string) error {
+	if err := domain.validateClientName(name); err != nil {
+		return err
+	}
+
+	client.Name = name
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetOwner transfers the client to another user.
+func (domain *OAuth2ClientDomain) SetOwner(client *OAuth2Client, ownerID snowflake.ID) {
+	client.OwnerUserID = ownerID
+	client.UpdatedAt = time.Now()
+}
+
+// SetDisabled disables or enables all flows of the client.
+func (domain *OAuth2ClientDomain) SetDisabled(client *OAuth2Client, disabled bool) {
+	client.IsDisabled = disabled
+	client.UpdatedAt = time.Now()
+}
+
+// SetAllowedScope narrows the scope which the client is allowed to request.
+// It can not exceed the default scope of the client, an empty scope resets it
+// to the default one.
+func (domain *OAuth2ClientDomain) SetAllowedScope(client *OAuth2Client, allowedScope scope.Scopes) error {
+	// OpenID Connect scopes are always allowed, they are not stored.
+	allowedScope = slices.DeleteFunc(slices.Clone(allowedScope), func(s scope.Scoper) bool {
+		return OIDCScopes.Contains(s)
+	})
+
+	defaultScope := domain.defaultAllowedScope(client.IsConfidential)
+	if len(allowedScope) == 0 {
+		allowedScope = defaultScope
+	}
+
+	if !allowedScope.LessThanOrEqual(defaultScope) {
+		return Wrap(ErrClientInvalid, "the allowed scope exceeds the default scope of the client")
+	}
+
+	client.AllowedScope = allowedScope
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) SetGrantTypes(client *OAuth2Client, grantTypes, responseTypes []string) {
+	client.GrantTypes = grantTypes
+	client.ResponseTypes = responseTypes
+	client.UpdatedAt = time.Now()
+}
+
+func (domain *OAuth2ClientDomain) SetLogoURI(client *OAuth2Client, logoURI string) error {
+	if logoURI != "" {
+		u, err := url.Parse(logoURI)
+		if err != nil {
+			return Wrap(ErrClientInvalid, "failed to parse logo uri: %s", err)
+		}
+
+		if !u.IsAbs() || (u.Scheme != "https" && u.Scheme != "http") {
+			return Wrap(ErrClientInvalid, "require an absolute http or https logo uri")
+		}
+	}
+
+	client.LogoURI = logoURI
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+// CreateRegistrationAccessToken issues a new registration access token to the
+// client, the previous one is no longer valid.
+func (domain *OAuth2ClientDomain) CreateRegistrationAccessToken(client *OAuth2Client) (string, error) {
+	token := xcrypto.RandString(RegistrationAccessTokenLength)
+	hashedToken, err := HashPassword(token)
+	if err != nil {
+		return "", err
+	}
+
+	client.HashedRegistrationAccessToken = string(hashedToken)
+	client.UpdatedAt = time.Now()
+	return token, nil
+}
+
+func (domain *OAuth2ClientDomain) SetRedirectURIs(client *OAuth2Client, redirectURIs []string) error {
+	if err := domain.validateRedirectURIs(redirectURIs); err != nil {
+		return err
+	}
+
+	client.RedirectURIs = redirectURIs
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) SetAllowedResources(client *OAuth2Client, resources []string) error {
+	if err := domain.validateResources(resources); err != nil {
+		return err
+	}
+
+	client.AllowedResources = resources
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) SetAllowImplicitFlow(client *OAuth2Client, allow bool) {
+	client.AllowImplicitFlow = allow
+	client.UpdatedAt = time.Now()
+}
+
+func (domain *OAuth2ClientDomain) SetRequirePushedAuthorizationRequests(client *OAuth2Client, require bool) {
+	client.RequirePushedAuthorizationRequests = require
+	client.UpdatedAt = time.Now()
+}
+
+// SetJWKS replaces the key set of the client, an empty one removes the keys.
+func (domain *OAuth2ClientDomain) SetJWKS(client *OAuth2Client, jwks string) error {
+	if jwks != "" {
+		if err := domain.validateJWKS(jwks); err != nil {
+			return err
+		}
+	}
+
+	client.JWKS = jwks
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) SetForbidPlainCodeChallenge(client *OAuth2Client, forbid bool) {
+	client.ForbidPlainCodeChallenge = forbid
+	client.UpdatedAt = time.Now()
+}
+
+func (domain *OAuth2ClientDomain) SetTLSClientAuthSubjectDN(client *OAuth2Client, subjectDN string) {
+	client.TLSClientAuthSubjectDN = subjectDN
+	client.UpdatedAt = time.Now()
+}
+
+func (domain *OAuth2ClientDomain) SetTokenEndpointAuthMethod(client *OAuth2Client, method string) error {
+	switch method {
+	case TokenEndpointAuthMethodNone:
+		if client.IsConfidential {
+			return Wrap(ErrClientInvalid, "a confidential client must authenticate itself")
+		}
+
+	case TokenEndpointAuthMethodClientSecretBasic, TokenEndpointAuthMethodClientSecretPost:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "a public client has no secret, use %s instead", TokenEndpointAuthMethodNone)
+		}
+
+	case TokenEndpointAuthMethodPrivateKeyJWT:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "require a confidential client for %s", method)
+		}
+
+		if client.JWKS == "" {
+			return Wrap(ErrClientInvalid, "require the jwks of the client for %s", method)
+		}
+
+	case TokenEndpointAuthMethodTLSClientAuth:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "require a confidential client for %s", method)
+		}
+
+		if client.TLSClientAuthSubjectDN == "" {
+			return Wrap(ErrClientInvalid, "require the subject dn of the client certificate for %s", method)
+		}
+
+	case TokenEndpointAuthMethodSelfSignedTLSClientAuth:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "require a confidential client for %s", method)
+		}
+
+		if client.JWKS == "" {
+			return Wrap(ErrClientInvalid, "require the jwks of the client for %s", method)
+		}
+
+	case TokenEndpointAuthMethodClientSecretJWT:
+		// The assertion is signed with the plain client secret, but only the
+		// hash of the secret is stored.
+		return Wrap(ErrClientInvalid, "not support %s, use %s instead", method, TokenEndpointAuthMethodPrivateKeyJWT)
+
+	default:
+		return Wrap(ErrClientInvalid, "not support token endpoint auth method %s", method)
+	}
+
+	client.TokenEndpointAuthMethod = method
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) SetBackchannelTokenDelivery(client *OAuth2Client, mode, endpoint string) error {
+	switch mode {
+	case "":
+		if endpoint != "" {
+			return Wrap(ErrClientInvalid, "require no client notification endpoint if backchannel is disabled")
+		}
+
+	case BackchannelTokenDeliveryModePoll:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "require a confidential client for backchannel authentication")
+		}
+
+		if endpoint != "" {
+			return Wrap(ErrClientInvalid, "require no client notification endpoint in %s mode", mode)
+		}
+
+	case BackchannelTokenDeliveryModePing:
+		if !client.IsConfidential {
+			return Wrap(ErrClientInvalid, "require a confidential client for backchannel authentication")
+		}
+
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return Wrap(ErrClientInvalid, "failed to parse client notification endpoint: %s", err)
+		}
+
+		if !u.IsAbs() || u.Scheme != "https" {
+			return Wrap(ErrClientInvalid, "require an absolute https client notification endpoint in %s mode", mode)
+		}
+
+		if !isPublicHost(u.Hostname()) {
+			return Wrap(ErrClientInvalid, "require a public host for the client notification endpoint, but got %s", u.Hostname())
+		}
+
+	default:
+		return Wrap(ErrClientInvalid, "not support backchannel token delivery mode %s", mode)
+	}
+
+	client.BackchannelTokenDeliveryMode = mode
+	client.BackchannelClientNotificationEndpoint = endpoint
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+// ValidateTokenEndpointAuthMethod checks if the client is allowed to
+// authenticate itself with the method.
+func (domain *OAuth2ClientDomain) ValidateTokenEndpointAuthMethod(client *OAuth2Client, method string) error {
+	if client.TokenEndpointAuthMethod == "" {
+		switch method {
+		case TokenEndpointAuthMethodNone, TokenEndpointAuthMethodClientSecretBasic,
+			TokenEndpointAuthMethodClientSecretPost:
+			return nil
+		default:
+			return Wrap(ErrClientInvalid, "the client has not registered %s", method)
+		}
+	}
+
+	if client.TokenEndpointAuthMethod != method {
+		return Wrap(ErrClientInvalid, "require %s, but got %s", client.TokenEndpointAuthMethod, method)
+	}
+
+	return nil
+}
+
+// ValidateGrantType checks if the client has registered the grant type.
+func (domain *OAuth2ClientDomain) ValidateGrantType(client *OAuth2Client, grantType string) error {
+	if len(client.GrantTypes) > 0 && !slices.Contains(client.GrantTypes, grantType) {
+		return Wrap(ErrClientInvalid, "the client has not registered grant type %s", grantType)
+	}
+
+	return nil
+}
+
+// ValidateResponseType checks if the client has registered the response type.
+// A registered client without response types can not use the authorization
+// endpoint at all.
+func (domain *OAuth2ClientDomain) ValidateResponseType(client *OAuth2Client, responseType string) error {
+	if len(client.GrantTypes) > 0 && !slices.Contains(client.ResponseTypes, responseType) {
+		return Wrap(ErrClientInvalid, "the client has not registered response type %s", responseType)
+	}
+
+	return nil
+}
+
+// ValidateRegistrationAccessToken authenticates the request to the client
+// configuration endpoint.
+func (domain *OAuth2ClientDomain) ValidateRegistrationAccessToken(client *OAuth2Client, token string) error {
+	if client.HashedRegistrationAccessToken == "" {
+		return Wrap(ErrClientInvalid, "the client is not registered dynamically")
+	}
+
+	return ValidatePassword(client.HashedRegistrationAccessToken, token)
+}
+
+// ValidateRedirectURI checks if the redirect uri is exactly one of the
+// registered redirect uris of the client. As an exception for native apps
+// (RFC 8252), the port of loopback redirect uris is allowed to be different.
+func (domain *OAuth2ClientDomain) ValidateRedirectURI(client *OAuth2Client, redirectURI string) error {
+	for _, registered := range client.RedirectURIs {
+		if registered == redirectURI || matchLoopbackRedirectURI(registered, redirectURI) {
+			return nil
+		}
+	}
+
+	return Wrap(ErrRedirectURIInvalid, "the redirect uri is not registered for the client")
+}
+
+// ValidateResource checks if the resource indicator is exactly one of the
+// allowed resources of the client.
+func (domain *OAuth2ClientDomain) ValidateResource(client *OAuth2Client, resource string) error {
+	for _, allowed := range client.AllowedResources {
+		if allowed == resource {
+			return nil
+		}
+	}
+
+	return Wrap(ErrResourceInvalid, "the resource is not allowed for the client")
+}
+
 func (domain *OAuth2ClientDomain) ValidateClient(
 	client *OAuth2Client,
 	clientID snowflake.ID,
@@ -84,7 +484,7 @@ func (domain *OAuth2ClientDomain) ValidateClient(
 	confidentialRequirement ConfidentialRequirementType,
 ) error {
 	if client.ID != clientID {
-		return errors.New("mismatched client id")
+		return Wrap(ErrClientInvalid, "mismatched client id")
 	}
 
 	switch confidentialRequirement {
@@ -110,6 +510,61 @@ func (domain *OAuth2ClientDomain) ValidateClient(
 	return nil
 }
 
+// ValidateClientCertificate authenticates the client by the certificate of
+// the mutual-TLS connection (RFC 8705). The certificate of tls_client_auth
+// must be issued by a trusted CA, while the certificate of
+// self_signed_tls_client_auth must hold one of the keys in the client jwks.
+func (domain *OAuth2ClientDomain) ValidateClientCertificate(
+	client *OAuth2Client,
+	clientID snowflake.ID,
+	certificate *x509.Certificate,
+	trusted bool,
+) error {
+	if client.ID != clientID {
+		return Wrap(ErrClientInvalid, "mismatched client id")
+	}
+
+	if certificate == nil {
+		return Wrap(ErrClientInvalid, "require a client certificate")
+	}
+
+	now := time.Now()
+	if now.Before(certificate.NotBefore) || now.After(certificate.NotAfter) {
+		return Wrap(ErrClientInvalid, "the client certificate is expired or not yet valid")
+	}
+
+	switch client.TokenEndpointAuthMethod {
+	case TokenEndpointAuthMethodTLSClientAuth:
+		if !trusted {
+			return Wrap(ErrClientInvalid, "the client certificate is not issued by a trusted ca")
+		}
+
+		if certificate.Subject.String() != client.TLSClientAuthSubjectDN {
+			return Wrap(ErrClientInvalid, "mismatched subject dn %s", certificate.Subject)
+		}
+
+	case TokenEndpointAuthMethodSelfSignedTLSClientAuth:
+		if !matchJWKSKey(client.JWKS, certificate.PublicKey) {
+			return Wrap(ErrClientInvalid, "the key of the client certificate is not in the jwks")
+		}
+
+	default:
+		return Wrap(ErrClientInvalid, "the client has not registered mutual-tls authentication")
+	}
+
+	return nil
+}
+
+// defaultAllowedScope returns the scope of a new client. Public clients can not
+// keep their credentials secret, so they are only allowed to read.
+func (domain *OAuth2ClientDomain) defaultAllowedScope(isConfidential bool) scope.Scopes {
+	if isConfidential {
+		return ScopeEngine.New(Actions, Resources).AsScopes()
+	}
+
+	return ScopeEngine.New(Actions.Read, Resources).AsScopes()
+}
+
 func (domain *OAuth2ClientDomain) validateClientName(clientName string) error {
 	if len(clientName) > MaximumClientNameLength {
 		return Wrap(ErrClientNameInvalid, "require at most %d characters", MaximumClientNameLength)
@@ -127,3 +582,140 @@ func (domain *OAuth2ClientDomain) validateClientName(clientName string) error {
 
 	return nil
 }
+
+func (domain *OAuth2ClientDomain) validateRedirectURIs(redirectURIs []string) error {
+	for _, uri := range redirectURIs {
+		u, err := xhttp.ParseURL(uri)
+		if err != nil {
+			return Wrap(ErrRedirectURIInvalid, "failed to parse %s: %s", uri, err)
+		}
+
+		if u.Fragment != "" {
+			return Wrap(ErrRedirectURIInvalid, "require no fragment component, but got %s", uri)
+		}
+	}
+
+	return nil
+}
+
+func (domain *OAuth2ClientDomain) validateResources(resources []string) error {
+	for _, resource := range resources {
+		u, err := url.Parse(resource)
+		if err != nil {
+			return Wrap(ErrResourceInvalid, "failed to parse %s: %s", resource, err)
+		}
+
+		if !u.IsAbs() {
+			return Wrap(ErrResourceInvalid, "require an absolute uri, but got %s", resource)
+		}
+
+		if u.Fragment != "" {
+			return Wrap(ErrResourceInvalid, "require no fragment component, but got %s", resource)
+		}
+	}
+
+	return nil
+}
+
+// validateJWKS checks if the key set has at least one key and all of its keys
+// are valid RSA keys.
+func (domain *OAuth2ClientDomain) validateJWKS(jwks string) error {
+	keys, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return Wrap(ErrJWKSInvalid, "require at least one key")
+	}
+
+	for i, key := range keys {
+		if _, err := key.RSAPublicKey(); err != nil {
+			return fmt.Errorf("%w at index %d", err, i)
+		}
+	}
+
+	return nil
+}
+
+// IsPublicIP returns true if the ip is routable on the internet. The server
+// must not send requests on behalf of clients to its internal network.
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+// isPublicHost rejects the hosts which obviously point to the internal
+// network, the resolved addresses are checked again when connecting.
+func isPublicHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+
+	return host != ""
+}
+
+// IsSecretClientAuthMethod returns true if the method authenticates the client
+// by the client secret sent in the request.
+func IsSecretClientAuthMethod(method string) bool {
+	return method == TokenEndpointAuthMethodClientSecretBasic || method == TokenEndpointAuthMethodClientSecretPost
+}
+
+// IsTLSClientAuthMethod returns true if the method authenticates the client by
+// the certificate of the mutual-TLS connection.
+func IsTLSClientAuthMethod(method string) bool {
+	return method == TokenEndpointAuthMethodTLSClientAuth || method == TokenEndpointAuthMethodSelfSignedTLSClientAuth
+}
+
+// matchJWKSKey returns true if the public key is one of the RSA keys in the
+// key set.
+func matchJWKSKey(jwks string, publicKey any) bool {
+	rsaKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return false
+	}
+
+	keys, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return false
+	}
+
+	for _, key := range keys {
+		if candidate, err := key.RSAPublicKey(); err == nil && rsaKey.Equal(candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchLoopbackRedirectURI(registered, requested string) bool {
+	r, err := url.Parse(registered)
+	if err != nil {
+		return false
+	}
+
+	q, err := url.Parse(requested)
+	if err != nil {
+		return false
+	}
+
+	if r.Scheme != "http" || q.Scheme != "http" {
+		return false
+	}
+
+	ip := net.ParseIP(r.Hostname())
+	if ip == nil || !ip.IsLoopback() {
+		return false
+	}
+
+	return r.Hostname() == q.Hostname() &&
+		r.Path == q.Path &&
+		r.RawQuery == q.RawQuery &&
+		r.Fragment == q.Fragment
+}
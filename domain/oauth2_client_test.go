@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateRedirectURI(t *testing.T) {
+	client := &OAuth2Client{
+		RedirectURIs: []string{
+			"https://app.example/callback",
+			"http://127.0.0.1:8080/callback?mode=native",
+			"http://[::1]/callback",
+			"http://localhost:8080/callback",
+		},
+	}
+
+	testcases := []struct {
+		name        string
+		redirectURI string
+		expectErr   bool
+	}{
+		{"exact uri", "https://app.example/callback", false},
+		{"different path", "https://app.example/other", true},
+		{"different port of non-loopback uri", "https://app.example:8443/callback", true},
+		{"exact loopback uri", "http://127.0.0.1:8080/callback?mode=native", false},
+		{"ipv4 loopback with another port", "http://127.0.0.1:51234/callback?mode=native", false},
+		{"ipv4 loopback without port", "http://127.0.0.1/callback?mode=native", false},
+		{"ipv6 loopback with a port", "http://[::1]:51234/callback", false},
+		{"loopback with another path", "http://127.0.0.1:51234/other?mode=native", true},
+		{"loopback with another query", "http://127.0.0.1:51234/callback?mode=web", true},
+		{"loopback with a fragment", "http://127.0.0.1:51234/callback?mode=native#x", true},
+		{"loopback with another ip", "http://127.0.0.2:51234/callback?mode=native", true},
+		{"loopback with https", "https://127.0.0.1:51234/callback?mode=native", true},
+		{"localhost with another port", "http://localhost:51234/callback", true},
+	}
+
+	domain := &OAuth2ClientDomain{}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := domain.ValidateRedirectURI(client, tc.redirectURI)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expect error %v, but got %v", tc.expectErr, err)
+			}
+
+			if err != nil && !errors.Is(err, ErrRedirectURIInvalid) {
+				t.Fatalf("expect ErrRedirectURIInvalid, but got %v", err)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package domain
 
 import (
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/xybor-x/snowflake"
@@ -22,12 +26,101 @@ const (
 const (
 	CodeChallengeMethodPlain = "plain"
 	CodeChallengeMethodS256  = "S256"
+
+	// The code challenge and code verifier are 43 to 128 characters long
+	// (RFC 7636).
+	MinimumCodeChallengeLength = 43
+	MaximumCodeChallengeLength = 128
+)
+
+type DeviceCodeStatus int
+
+const (
+	DeviceCodeStatusPending DeviceCodeStatus = iota
+	DeviceCodeStatusApproved
+	DeviceCodeStatusDenied
+)
+
+type BackchannelAuthenticationStatus int
+
+const (
+	BackchannelAuthenticationStatusPending BackchannelAuthenticationStatus = iota
+	BackchannelAuthenticationStatusApproved
+	BackchannelAuthenticationStatusDenied
+)
+
+const (
+	DeviceCodeExpiration      = 10 * time.Minute
+	DeviceCodePollingInterval = 5 * time.Second
+
+	// MaximumUserCodeAttempts bounds how many user codes can be looked up
+	// from the same address in UserCodeAttemptWindow, so that the user codes
+	// can not be brute forced (RFC 8628, section 5.1).
+	MaximumUserCodeAttempts = 20
+	UserCodeAttemptWindow   = 10 * time.Minute
+
+	// PushedAuthorizationRequestExpiration is the lifetime of a request_uri
+	// returned by the pushed authorization request endpoint (RFC 9126).
+	PushedAuthorizationRequestExpiration = 90 * time.Second
+	RequestURIPrefix                     = "urn:ietf:params:oauth:request_uri:"
+
+	// DPoPProofLifetime bounds how far the issued time of a DPoP proof can be
+	// from now, and how long its id is remembered to detect replays.
+	DPoPProofLifetime   = 5 * time.Minute
+	DPoPNonceExpiration = 5 * time.Minute
+
+	// JWTBearerMaximumLifetime bounds the lifetime of assertions presented in
+	// the JWT bearer grant (RFC 7523), so that a leaked one is not usable for
+	// long.
+	JWTBearerMaximumLifetime = time.Hour
+
+	// RequestObjectMaximumLifetime bounds how long a request object is
+	// accepted after it is signed by the client (RFC 9101).
+	RequestObjectMaximumLifetime = time.Hour
+
+	// MaximumActorChainLength bounds how many times a token can be exchanged
+	// for another one on behalf of its subject (RFC 8693).
+	MaximumActorChainLength = 5
+
+	// The client can shorten the lifetime of a backchannel authentication
+	// request by requested_expiry, but not extend it beyond the maximum.
+	BackchannelAuthenticationExpiration        = 5 * time.Minute
+	MaximumBackchannelAuthenticationExpiration = 30 * time.Minute
+	BackchannelPollingInterval                 = 5 * time.Second
+
+	// MaximumBindingMessageLength keeps the binding message short enough to
+	// be shown on both the consumption and authentication devices.
+	MaximumBindingMessageLength = 64
+
+	// The user code alphabet excludes vowels and ambiguous characters, as
+	// recommended by RFC 8628.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
 )
 
+// How the subject of an assertion issued by a trusted issuer identifies the
+// local user.
+const (
+	UserMappingSubject  = "subject"
+	UserMappingUsername = "username"
+)
+
+// OAuth2TrustedIssuer is an external issuer whose assertions are exchanged
+// for access tokens by the JWT bearer grant (RFC 7523).
+type OAuth2TrustedIssuer struct {
+	Issuer      string
+	UserMapping string
+
+	// ClientIDs are the clients allowed to present the assertions of this
+	// issuer.
+	ClientIDs []snowflake.ID
+}
+
 type Session struct {
-	State     SessionState
-	UserID    snowflake.ID
-	ExpiresAt time.Time
+	State           SessionState
+	UserID          snowflake.ID
+	AuthenticatedAt time.Time
+	ExpiresAt       time.Time
 }
 
 type OAuth2AuthorizationCode struct {
@@ -35,22 +128,71 @@ type OAuth2AuthorizationCode struct {
 	UserID              snowflake.ID
 	ClientID            snowflake.ID
 	Scope               scope.Scopes
+	RedirectURI         string
 	CodeChallenge       string
 	CodeChallengeMethod string
+	Nonce               string
+	AuthTime            time.Time
 	ExpiresAt           time.Time
 }
 
+type OAuth2DeviceCode struct {
+	DeviceCode   string
+	UserCode     string
+	ClientID     snowflake.ID
+	Scope        scope.Scopes
+	Status       DeviceCodeStatus
+	UserID       snowflake.ID
+	AuthTime     time.Time
+	Interval     time.Duration
+	LastPolledAt time.Time
+	ExpiresAt    time.Time
+}
+
+// OAuth2BackchannelAuthentication is a request of the client initiated
+// backchannel authentication, which is approved by the user out of band.
+type OAuth2BackchannelAuthentication struct {
+	AuthReqID               string
+	AuthorizationID         string
+	ClientID                snowflake.ID
+	UserID                  snowflake.ID
+	Scope                   scope.Scopes
+	BindingMessage          string
+	ClientNotificationToken string
+	Status                  BackchannelAuthenticationStatus
+	AuthTime                time.Time
+	Interval                time.Duration
+	LastPolledAt            time.Time
+	ExpiresAt               time.Time
+}
+
 type OAuth2AuthorizationStore struct {
 	ID                  string
 	IsOpen              bool
 	ResponseType        string
+	ResponseMode        string
 	ClientID            snowflake.ID
 	RedirectURI         string
 	Scope               scope.Scopes
 	State               string
 	CodeChallenge       string
 	CodeChallengeMethod string
+	Nonce               string
+	UserCode            string
 	ExpiresAt           time.Time
+
+	// Pushed is true if the authorization was requested through a pushed
+	// authorization request rather than the query of the authorization
+	// endpoint.
+	Pushed bool
+
+	// AuthReqID is the backchannel authentication request which is consented
+	// through this store. Such stores are not opened by the browser.
+	AuthReqID string
+
+	// UserID is the user who is asked to confirm the device of UserCode.
+	// Only this user can complete the confirmation (RFC 8628, section 5.4).
+	UserID snowflake.ID
 }
 
 type OAuth2AuthenticationResult struct {
@@ -74,28 +216,112 @@ type OAuth2TokenMedata struct {
 
 type OAuth2AccessToken struct {
 	Metadata *OAuth2TokenMedata
+	ClientID snowflake.ID
 	Scope    scope.Scopes
+
+	// CertificateThumbprint is the SHA-256 thumbprint of the client
+	// certificate which the token is bound to (RFC 8705).
+	CertificateThumbprint string
+
+	// KeyThumbprint is the JWK thumbprint of the DPoP key which the token is
+	// bound to (RFC 9449).
+	KeyThumbprint string
+
+	// Actor is the party acting on behalf of the subject, it is only set for
+	// tokens issued by the token exchange (RFC 8693).
+	Actor *OAuth2Actor
+}
+
+// OAuth2Actor identifies the party acting on behalf of the subject of a token.
+// The nested actor is the prior one in the delegation chain.
+type OAuth2Actor struct {
+	Subject  snowflake.ID
+	ClientID snowflake.ID
+	Actor    *OAuth2Actor
+}
+
+// Length returns the number of actors in the delegation chain.
+func (actor *OAuth2Actor) Length() int {
+	length := 0
+	for ; actor != nil; actor = actor.Actor {
+		length++
+	}
+
+	return length
 }
 
 type OAuth2RefreshToken struct {
 	Metadata       *OAuth2TokenMedata
 	SequenceNumber int
+	ClientID       snowflake.ID
 	Scope          scope.Scopes
+
+	// KeyThumbprint is the JWK thumbprint of the DPoP key which the token is
+	// bound to, only refresh tokens of public clients are bound.
+	KeyThumbprint string
+}
+
+// OAuth2DPoPProof is a proof of possession of the key of the client, which is
+// sent along with each request (RFC 9449).
+type OAuth2DPoPProof struct {
+	ID              string
+	Method          string
+	URI             string
+	IssuedAt        time.Time
+	AccessTokenHash string
+	Nonce           string
+
+	// KeyThumbprint is the JWK thumbprint of the key signing the proof.
+	KeyThumbprint string
+}
+
+// OAuth2DPoPNonce is provided by the server to limit the lifetime of DPoP
+// proofs, the client must include a recent nonce in its proofs.
+type OAuth2DPoPNonce struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// OAuth2RefreshTokenFamily tracks the refresh tokens rotated from the same
+// grant. All of them share the id of the first refresh token, only the one
+// with the latest sequence number can be used.
+type OAuth2RefreshTokenFamily struct {
+	ID             snowflake.ID
+	AccessTokenID  snowflake.ID
+	SequenceNumber int
+	UserID         snowflake.ID
+	ClientID       snowflake.ID
+	Scope          scope.Scopes
+	CreatedAt      time.Time
+	LastUsedAt     time.Time
 }
 
 type OAuth2IDToken struct {
-	Metadata *OAuth2TokenMedata
-	User     *User
+	Metadata        *OAuth2TokenMedata
+	User            *User
+	AuthTime        time.Time
+	Nonce           string
+	AccessTokenHash string
+	CodeHash        string
 }
 
 type OAuth2FlowDomain struct {
 	Snowflake *snowflake.Node
 	Issuer    string
 
-	AuthorizationCodeFlowExpiration  time.Duration
-	AuthenticationCallbackExpiration time.Duration
-	SessionUpdateExpiration          time.Duration
-	SessionExpiration                time.Duration
+	AuthorizationCodeFlowExpiration      time.Duration
+	AuthenticationCallbackExpiration     time.Duration
+	SessionUpdateExpiration              time.Duration
+	SessionExpiration                    time.Duration
+	DeviceCodeExpiration                 time.Duration
+	DeviceCodePollingInterval            time.Duration
+	PushedAuthorizationRequestExpiration time.Duration
+	DPoPProofLifetime                    time.Duration
+	DPoPNonceExpiration                  time.Duration
+
+	// AllowPlainCodeChallenge is the server-wide policy of the plain code
+	// challenge method. Clients can also forbid it individually.
+	AllowPlainCodeChallenge bool
 
 	AccessTokenExpiration  time.Duration
 	RefreshTokenExpiration time.Duration
@@ -113,15 +339,23 @@ func NewOAuth2FlowDomain(
 	accessTokenExpiration time.Duration,
 	refreshTokenExpiration time.Duration,
 	idTokenExpiration time.Duration,
+	allowPlainCodeChallenge bool,
 ) (*OAuth2FlowDomain, error) {
 	return &OAuth2FlowDomain{
 		Snowflake: snowflake,
 		Issuer:    issuer,
 
-		AuthorizationCodeFlowExpiration:  accessTokenExpiration,
-		AuthenticationCallbackExpiration: authenticationCallbackExpiration,
-		SessionUpdateExpiration:          sessionUpdateExpiration,
-		SessionExpiration:                sessionExpiration,
+		AuthorizationCodeFlowExpiration:      accessTokenExpiration,
+		AuthenticationCallbackExpiration:     authenticationCallbackExpiration,
+		SessionUpdateExpiration:              sessionUpdateExpiration,
+		SessionExpiration:                    sessionExpiration,
+		DeviceCodeExpiration:                 DeviceCodeExpiration,
+		DeviceCodePollingInterval:            DeviceCodePollingInterval,
+		PushedAuthorizationRequestExpiration: PushedAuthorizationRequestExpiration,
+		DPoPProofLifetime:                    DPoPProofLifetime,
+		DPoPNonceExpiration:                  DPoPNonceExpiration,
+
+		AllowPlainCodeChallenge: allowPlainCodeChallenge,
 
 		AccessTokenExpiration:  accessTokenExpiration,
 		RefreshTokenExpiration: refreshTokenExpiration,
@@ -132,28 +366,187 @@ func NewOAuth2FlowDomain(
 func (domain *OAuth2FlowDomain) CreateAuthorizationCode(
 	userID, clientID snowflake.ID,
 	scope scope.Scopes,
-	codeChallenge, codeChallengeMethod string,
+	redirectURI, codeChallenge, codeChallengeMethod, nonce string,
+	authTime time.Time,
 ) *OAuth2AuthorizationCode {
 	return &OAuth2AuthorizationCode{
 		Code:                xcrypto.RandString(32),
 		Scope:               scope,
 		UserID:              userID,
 		ClientID:            clientID,
+		RedirectURI:         redirectURI,
 		CodeChallenge:       codeChallenge,
 		CodeChallengeMethod: codeChallengeMethod,
+		Nonce:               nonce,
+		AuthTime:            authTime,
 		ExpiresAt:           time.Now().Add(domain.AuthorizationCodeFlowExpiration),
 	}
 }
 
+func (domain *OAuth2FlowDomain) CreateDeviceCode(clientID snowflake.ID, scope scope.Scopes) *OAuth2DeviceCode {
+	return &OAuth2DeviceCode{
+		DeviceCode: xcrypto.RandString(32),
+		UserCode:   randUserCode(),
+		ClientID:   clientID,
+		Scope:      scope,
+		Status:     DeviceCodeStatusPending,
+		Interval:   domain.DeviceCodePollingInterval,
+		ExpiresAt:  time.Now().Add(domain.DeviceCodeExpiration),
+	}
+}
+
+// PollDeviceCode records a polling request of the device. It returns false if
+// the device polls faster than the allowed interval, in this case the interval
+// is increased by 5 seconds as RFC 8628 requires.
+func (domain *OAuth2FlowDomain) PollDeviceCode(code *OAuth2DeviceCode) bool {
+	now := time.Now()
+	tooFast := now.Sub(code.LastPolledAt) < code.Interval
+
+	code.LastPolledAt = now
+	if tooFast {
+		code.Interval += 5 * time.Second
+	}
+
+	return !tooFast
+}
+
+func (domain *OAuth2FlowDomain) ApproveDeviceCode(
+	code *OAuth2DeviceCode,
+	userID snowflake.ID,
+	scope scope.Scopes,
+	authTime time.Time,
+) {
+	code.Status = DeviceCodeStatusApproved
+	code.UserID = userID
+	code.Scope = scope
+	code.AuthTime = authTime
+}
+
+func (domain *OAuth2FlowDomain) DenyDeviceCode(code *OAuth2DeviceCode) {
+	code.Status = DeviceCodeStatusDenied
+}
+
+// ValidateBackchannelAuthenticationRequest checks the request of the client
+// before the user is asked for approval.
+func (domain *OAuth2FlowDomain) ValidateBackchannelAuthenticationRequest(
+	client *OAuth2Client,
+	scope scope.Scopes,
+	bindingMessage string,
+	clientNotificationToken string,
+) error {
+	if !scope.Contains(ScopeOpenID) {
+		return fmt.Errorf("%w%s", ErrKnown, "require the openid scope")
+	}
+
+	if len(bindingMessage) > MaximumBindingMessageLength {
+		return Wrap(ErrBindingMessageInvalid, "require at most %d characters", MaximumBindingMessageLength)
+	}
+
+	for _, c := range bindingMessage {
+		if c < ' ' || c == 0x7f {
+			return Wrap(ErrBindingMessageInvalid, "got a control character")
+		}
+	}
+
+	if client.BackchannelTokenDeliveryMode == BackchannelTokenDeliveryModePing && clientNotificationToken == "" {
+		return fmt.Errorf("%w%s", ErrKnown, "require the client notification token in ping mode")
+	}
+
+	return nil
+}
+
+// CreateBackchannelAuthentication creates a pending request. The requested
+// expiry is bounded by the maximum, it is the default one if zero.
+func (domain *OAuth2FlowDomain) CreateBackchannelAuthentication(
+	client *OAuth2Client,
+	userID snowflake.ID,
+	scope scope.Scopes,
+	bindingMessage string,
+	clientNotificationToken string,
+	requestedExpiry time.Duration,
+) *OAuth2BackchannelAuthentication {
+	expiration := BackchannelAuthenticationExpiration
+	if requestedExpiry > 0 {
+		expiration = min(requestedExpiry, MaximumBackchannelAuthenticationExpiration)
+	}
+
+	req := &OAuth2BackchannelAuthentication{
+		AuthReqID:      xcrypto.RandString(32),
+		ClientID:       client.ID,
+		UserID:         userID,
+		Scope:          scope,
+		BindingMessage: bindingMessage,
+		Status:         BackchannelAuthenticationStatusPending,
+		ExpiresAt:      time.Now().Add(expiration),
+	}
+
+	if client.BackchannelTokenDeliveryMode == BackchannelTokenDeliveryModePing {
+		req.ClientNotificationToken = clientNotificationToken
+	} else {
+		req.Interval = BackchannelPollingInterval
+	}
+
+	return req
+}
+
+// CreateBackchannelAuthorizationStore creates the store through which the
+// user consents the backchannel authentication request. Both expire together.
+func (domain *OAuth2FlowDomain) CreateBackchannelAuthorizationStore(
+	req *OAuth2BackchannelAuthentication,
+) *OAuth2AuthorizationStore {
+	store := &OAuth2AuthorizationStore{
+		ID:        xcrypto.RandString(32),
+		ClientID:  req.ClientID,
+		Scope:     req.Scope,
+		AuthReqID: req.AuthReqID,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	req.AuthorizationID = store.ID
+	return store
+}
+
+// PollBackchannelAuthentication records a polling request of the client. It
+// returns false if the client polls faster than the allowed interval, in this
+// case the interval is increased by 5 seconds. In ping mode, the client only
+// calls the token endpoint after being notified, so there is no interval.
+func (domain *OAuth2FlowDomain) PollBackchannelAuthentication(req *OAuth2BackchannelAuthentication) bool {
+	now := time.Now()
+	tooFast := now.Sub(req.LastPolledAt) < req.Interval
+
+	req.LastPolledAt = now
+	if tooFast {
+		req.Interval += 5 * time.Second
+	}
+
+	return !tooFast
+}
+
+func (domain *OAuth2FlowDomain) ApproveBackchannelAuthentication(
+	req *OAuth2BackchannelAuthentication,
+	scope scope.Scopes,
+	authTime time.Time,
+) {
+	req.Status = BackchannelAuthenticationStatusApproved
+	req.Scope = scope
+	req.AuthTime = authTime
+}
+
+func (domain *OAuth2FlowDomain) DenyBackchannelAuthentication(req *OAuth2BackchannelAuthentication) {
+	req.Status = BackchannelAuthenticationStatusDenied
+}
+
 func (domain *OAuth2FlowDomain) CreateAuthorizationStore(
-	respType string,
+	respType, respMode string,
 	clientID snowflake.ID,
 	scope scope.Scopes,
-	redirectURI, state, codeChallenge, codeChallengeMethod string,
+	redirectURI, state, codeChallenge, codeChallengeMethod, nonce, userCode string,
+	pushed bool,
 ) *OAuth2AuthorizationStore {
 	return &OAuth2AuthorizationStore{
 		ID:                  xcrypto.RandString(32),
 		ResponseType:        respType,
+		ResponseMode:        respMode,
 		IsOpen:              true,
 		Scope:               scope,
 		ClientID:            clientID,
@@ -161,10 +554,78 @@ func (domain *OAuth2FlowDomain) CreateAuthorizationStore(
 		State:               state,
 		CodeChallenge:       codeChallenge,
 		CodeChallengeMethod: codeChallengeMethod,
+		Nonce:               nonce,
+		UserCode:            userCode,
 		ExpiresAt:           time.Now().Add(domain.AuthenticationCallbackExpiration),
+		Pushed:              pushed,
+	}
+}
+
+// CreateDeviceConfirmationStore creates the store which is confirmed by the
+// user on the device verification page before the device is approved.
+func (domain *OAuth2FlowDomain) CreateDeviceConfirmationStore(
+	code *OAuth2DeviceCode,
+	userID snowflake.ID,
+	scope scope.Scopes,
+) *OAuth2AuthorizationStore {
+	return &OAuth2AuthorizationStore{
+		ID:        xcrypto.RandString(32),
+		ClientID:  code.ClientID,
+		Scope:     scope,
+		UserCode:  code.UserCode,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(domain.AuthenticationCallbackExpiration),
 	}
 }
 
+// ValidateDeviceConfirmationStore checks if the store is a device
+// confirmation of the user for the user code.
+func (domain *OAuth2FlowDomain) ValidateDeviceConfirmationStore(
+	store *OAuth2AuthorizationStore,
+	userID snowflake.ID,
+	userCode string,
+) error {
+	if store.UserCode == "" || store.UserID == 0 {
+		return fmt.Errorf("%w%s", ErrKnown, "the authorization is not a device confirmation")
+	}
+
+	if store.UserID != userID {
+		return fmt.Errorf("%w%s", ErrKnown, "the device confirmation belongs to another user")
+	}
+
+	if store.UserCode != NormalizeUserCode(userCode) {
+		return fmt.Errorf("%w%s", ErrKnown, "the device confirmation is for another user code")
+	}
+
+	if store.ExpiresAt.Before(time.Now()) {
+		return fmt.Errorf("%w%s", ErrKnown, "the device confirmation is expired")
+	}
+
+	return nil
+}
+
+// PushAuthorizationStore creates a short-lived copy of the authorization
+// store, which is referenced by a request_uri (RFC 9126).
+func (domain *OAuth2FlowDomain) PushAuthorizationStore(store *OAuth2AuthorizationStore) *OAuth2AuthorizationStore {
+	pushed := *store
+	pushed.ID = xcrypto.RandString(32)
+	pushed.IsOpen = true
+	pushed.Pushed = true
+	pushed.ExpiresAt = time.Now().Add(domain.PushedAuthorizationRequestExpiration)
+	return &pushed
+}
+
+// ParseRequestURI returns the id of the pushed authorization store which is
+// referenced by the request_uri.
+func (domain *OAuth2FlowDomain) ParseRequestURI(requestURI string) (string, error) {
+	id, ok := strings.CutPrefix(requestURI, RequestURIPrefix)
+	if !ok || id == "" {
+		return "", Wrap(ErrRequestURIInvalid, "the request uri is not issued by this server")
+	}
+
+	return id, nil
+}
+
 func (domain *OAuth2FlowDomain) CreateAuthenticationResultSuccess(
 	authID string,
 	userID snowflake.ID,
@@ -190,47 +651,327 @@ func (domain *OAuth2FlowDomain) CreateAuthenticationResultFailure(authID string,
 	}
 }
 
-func (domain *OAuth2FlowDomain) CreateAccessToken(aud string, scope scope.Scopes, user *User) *OAuth2AccessToken {
+func (domain *OAuth2FlowDomain) CreateAccessToken(
+	aud string,
+	scope scope.Scopes,
+	user *User,
+	clientID snowflake.ID,
+) *OAuth2AccessToken {
 	return &OAuth2AccessToken{
 		Metadata: domain.createMedata(aud, user.ID, domain.AccessTokenExpiration),
+		ClientID: clientID,
+		Scope:    scope,
+	}
+}
+
+func (domain *OAuth2FlowDomain) CreateClientAccessToken(aud string, scope scope.Scopes, client *OAuth2Client) *OAuth2AccessToken {
+	return &OAuth2AccessToken{
+		Metadata: domain.createMedata(aud, client.ID, domain.AccessTokenExpiration),
+		ClientID: client.ID,
+		Scope:    scope,
+	}
+}
+
+// CreateExchangedAccessToken issues a token for the subject of the subject
+// token to the client (RFC 8693). With the actor token, its subject becomes
+// the current actor and the actors of the subject token are kept as prior
+// ones. Without it, the client impersonates the subject. The issued token
+// never outlives the subject token.
+func (domain *OAuth2FlowDomain) CreateExchangedAccessToken(
+	aud string,
+	scope scope.Scopes,
+	subjectToken *OAuth2AccessToken,
+	actorToken *OAuth2AccessToken,
+	client *OAuth2Client,
+) *OAuth2AccessToken {
+	token := &OAuth2AccessToken{
+		Metadata: domain.createMedata(aud, subjectToken.Metadata.Subject, domain.AccessTokenExpiration),
+		ClientID: client.ID,
 		Scope:    scope,
+		Actor:    subjectToken.Actor,
 	}
+
+	if actorToken != nil {
+		token.Actor = &OAuth2Actor{
+			Subject:  actorToken.Metadata.Subject,
+			ClientID: actorToken.ClientID,
+			Actor:    subjectToken.Actor,
+		}
+	}
+
+	if token.Metadata.ExpiresAt > subjectToken.Metadata.ExpiresAt {
+		token.Metadata.ExpiresAt = subjectToken.Metadata.ExpiresAt
+	}
+
+	return token
 }
 
-func (domain *OAuth2FlowDomain) CreateRefreshToken(aud string, scope scope.Scopes, userID snowflake.ID) *OAuth2RefreshToken {
+func (domain *OAuth2FlowDomain) CreateRefreshToken(
+	aud string,
+	scope scope.Scopes,
+	userID snowflake.ID,
+	clientID snowflake.ID,
+) *OAuth2RefreshToken {
 	return &OAuth2RefreshToken{
 		Metadata:       domain.createMedata(aud, userID, domain.RefreshTokenExpiration),
 		SequenceNumber: 0,
+		ClientID:       clientID,
 		Scope:          scope,
 	}
 }
 
 func (domain *OAuth2FlowDomain) NextRefreshToken(current *OAuth2RefreshToken) *OAuth2RefreshToken {
-	next := domain.CreateRefreshToken(current.Metadata.Audience, current.Scope, current.Metadata.Subject)
+	next := domain.CreateRefreshToken(
+		current.Metadata.Audience, current.Scope, current.Metadata.Subject, current.ClientID)
 	next.Metadata.ID = current.Metadata.ID
 	next.SequenceNumber = current.SequenceNumber + 1
+	next.KeyThumbprint = current.KeyThumbprint
 	return next
 }
 
-func (domain *OAuth2FlowDomain) CreateIDToken(aud string, user *User) *OAuth2IDToken {
+func (domain *OAuth2FlowDomain) CreateRefreshTokenFamily(
+	refreshToken *OAuth2RefreshToken,
+	accessToken *OAuth2AccessToken,
+) *OAuth2RefreshTokenFamily {
+	now := time.Now()
+	return &OAuth2RefreshTokenFamily{
+		ID:             refreshToken.Metadata.ID,
+		AccessTokenID:  accessToken.Metadata.ID,
+		SequenceNumber: refreshToken.SequenceNumber,
+		UserID:         refreshToken.Metadata.Subject,
+		ClientID:       refreshToken.ClientID,
+		Scope:          refreshToken.Scope,
+		CreatedAt:      now,
+		LastUsedAt:     now,
+	}
+}
+
+// IsLatestRefreshToken checks if the refresh token with the sequence number is
+// the latest one of its family. Rotated tokens have a smaller sequence number,
+// presenting one of them again means the family was leaked.
+func (domain *OAuth2FlowDomain) IsLatestRefreshToken(family *OAuth2RefreshTokenFamily, sequenceNumber int) bool {
+	return family.SequenceNumber == sequenceNumber
+}
+
+// AccessTokenExpiresAt returns the time when the latest access token minted
+// from the family expires.
+func (domain *OAuth2FlowDomain) AccessTokenExpiresAt(family *OAuth2RefreshTokenFamily) time.Time {
+	return time.UnixMilli(family.AccessTokenID.Time()).Add(domain.AccessTokenExpiration)
+}
+
+// CreateIDToken creates an OpenID Connect ID token. The accessToken and code
+// are the serialized access token and the authorization code issued along
+// with the ID token, they are used to calculate the at_hash and c_hash claims.
+// Leave them empty if they are not issued.
+func (domain *OAuth2FlowDomain) CreateIDToken(
+	aud string,
+	user *User,
+	authTime time.Time,
+	nonce string,
+	accessToken string,
+	code string,
+) *OAuth2IDToken {
 	return &OAuth2IDToken{
-		Metadata: domain.createMedata(aud, user.ID, domain.IDTokenExpiration),
-		User:     user,
+		Metadata:        domain.createMedata(aud, user.ID, domain.IDTokenExpiration),
+		User:            user,
+		AuthTime:        authTime,
+		Nonce:           nonce,
+		AccessTokenHash: tokenHash(accessToken),
+		CodeHash:        tokenHash(code),
+	}
+}
+
+// ValidateAssertionAudience checks if the audience of an assertion issued by
+// a client identifies this server, which is either the issuer or one of the
+// endpoints under the issuer (RFC 7523).
+func (domain *OAuth2FlowDomain) ValidateAssertionAudience(audience []string) error {
+	issuer := strings.TrimSuffix(domain.Issuer, "/")
+	for _, aud := range audience {
+		if aud == issuer || aud == issuer+"/" || strings.HasPrefix(aud, issuer+"/") {
+			return nil
+		}
+	}
+
+	return Wrap(ErrAssertionInvalid, "the audience of the assertion must identify %s", domain.Issuer)
+}
+
+// ValidateRequestObject checks the claims of the request object, which must be
+// issued by the client to this server and expire soon (RFC 9101, section
+// 6.3).
+func (domain *OAuth2FlowDomain) ValidateRequestObject(
+	client *OAuth2Client,
+	issuer string,
+	clientID string,
+	audience string,
+	expiresAt time.Time,
+) error {
+	if issuer != client.ID.String() {
+		return fmt.Errorf("%w%s", ErrKnown, "the request object must be issued by the client")
+	}
+
+	if clientID != client.ID.String() {
+		return fmt.Errorf("%w%s", ErrKnown, "mismatched client id")
+	}
+
+	if strings.TrimSuffix(audience, "/") != strings.TrimSuffix(domain.Issuer, "/") {
+		return fmt.Errorf("%wthe audience of the request object must be %s", ErrKnown, domain.Issuer)
+	}
+
+	if expiresAt.IsZero() {
+		return fmt.Errorf("%w%s", ErrKnown, "the request object must have an expiration time")
+	}
+
+	if time.Until(expiresAt) > RequestObjectMaximumLifetime {
+		return fmt.Errorf("%wthe lifetime of the request object must not exceed %s", ErrKnown, RequestObjectMaximumLifetime)
+	}
+
+	return nil
+}
+
+// BindCertificate binds the access token to the client certificate, only the
+// holder of the certificate can use the token at resource servers checking
+// the confirmation claim (RFC 8705).
+func (domain *OAuth2FlowDomain) BindCertificate(token *OAuth2AccessToken, certificate *x509.Certificate) {
+	token.CertificateThumbprint = CertificateThumbprint(certificate)
+}
+
+// CertificateThumbprint returns the base64url-encoded SHA-256 hash of the DER
+// encoding of the certificate, which is the x5t#S256 confirmation method.
+func CertificateThumbprint(certificate *x509.Certificate) string {
+	hash := sha256.Sum256(certificate.Raw)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// BindKey binds the access token to the DPoP key, only the holder of the key
+// can use the token (RFC 9449).
+func (domain *OAuth2FlowDomain) BindKey(token *OAuth2AccessToken, keyThumbprint string) {
+	token.KeyThumbprint = keyThumbprint
+}
+
+// BindRefreshTokenKey binds the refresh token to the DPoP key. Refresh tokens
+// of confidential clients are not bound since they are already bound to the
+// client by its authentication (RFC 9449 Section 5).
+func (domain *OAuth2FlowDomain) BindRefreshTokenKey(client *OAuth2Client, token *OAuth2RefreshToken, keyThumbprint string) {
+	if !client.IsConfidential {
+		token.KeyThumbprint = keyThumbprint
+	}
+}
+
+func (domain *OAuth2FlowDomain) CreateDPoPNonce() *OAuth2DPoPNonce {
+	return &OAuth2DPoPNonce{
+		Value:     xcrypto.RandString(32),
+		ExpiresAt: time.Now().Add(domain.DPoPNonceExpiration),
+	}
+}
+
+// ValidateDPoPProof checks if the proof is created for the HTTP request. At
+// resource servers, the proof must also be bound to the access token.
+func (domain *OAuth2FlowDomain) ValidateDPoPProof(proof *OAuth2DPoPProof, method, uri, accessToken string) error {
+	if proof.Method != method {
+		return Wrap(ErrDPoPProofInvalid, "mismatched http method %s", proof.Method)
+	}
+
+	if !matchDPoPURI(proof.URI, uri) {
+		return Wrap(ErrDPoPProofInvalid, "mismatched http uri %s", proof.URI)
+	}
+
+	now := time.Now()
+	if proof.IssuedAt.Before(now.Add(-domain.DPoPProofLifetime)) || proof.IssuedAt.After(now.Add(domain.DPoPProofLifetime)) {
+		return Wrap(ErrDPoPProofInvalid, "the proof is expired or issued in the future")
+	}
+
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		if proof.AccessTokenHash != base64.RawURLEncoding.EncodeToString(hash[:]) {
+			return Wrap(ErrDPoPProofInvalid, "mismatched access token hash")
+		}
+	}
+
+	return nil
+}
+
+// CodeChallengeMethods returns the code challenge methods allowed by the
+// server.
+func (domain *OAuth2FlowDomain) CodeChallengeMethods() []string {
+	if domain.AllowPlainCodeChallenge {
+		return []string{CodeChallengeMethodS256, CodeChallengeMethodPlain}
+	}
+
+	return []string{CodeChallengeMethodS256}
+}
+
+// ValidatePKCE checks the code challenge of an authorization request which
+// issues an authorization code. Public clients cannot authenticate at the token
+// endpoint, so they must use PKCE. The method defaults to plain (RFC 7636),
+// the normalized method is returned.
+func (domain *OAuth2FlowDomain) ValidatePKCE(client *OAuth2Client, challenge, method string) (string, error) {
+	if challenge == "" {
+		if method != "" {
+			return "", Wrap(ErrCodeChallengeInvalid, "require code_challenge along with code_challenge_method")
+		}
+
+		if !client.IsConfidential {
+			return "", Wrap(ErrCodeChallengeInvalid, "require code_challenge for public clients")
+		}
+
+		return "", nil
+	}
+
+	if method == "" {
+		method = CodeChallengeMethodPlain
+	}
+
+	switch method {
+	case CodeChallengeMethodS256:
+	case CodeChallengeMethodPlain:
+		if !domain.AllowPlainCodeChallenge || client.ForbidPlainCodeChallenge {
+			return "", Wrap(ErrCodeChallengeInvalid, "the code challenge method %s is not allowed", method)
+		}
+	default:
+		return "", Wrap(ErrCodeChallengeInvalid, "not support code challenge method %s", method)
 	}
+
+	if len(challenge) < MinimumCodeChallengeLength || len(challenge) > MaximumCodeChallengeLength {
+		return "", Wrap(ErrCodeChallengeInvalid, "require %d to %d characters",
+			MinimumCodeChallengeLength, MaximumCodeChallengeLength)
+	}
+
+	for _, c := range challenge {
+		if !isCodeVerifierCharacter(c) {
+			return "", Wrap(ErrCodeChallengeInvalid, "got an invalid character %c", c)
+		}
+	}
+
+	return method, nil
 }
 
 func (domain *OAuth2FlowDomain) ValidateCodeChallenge(verifier, challenge, method string) bool {
 	switch method {
 	case CodeChallengeMethodPlain:
 		return verifier == challenge
-	default: // CodeChallengeMethodS256
+	case CodeChallengeMethodS256:
 		hash := sha256.Sum256([]byte(verifier))
 		encoded := base64.RawURLEncoding.EncodeToString(hash[:])
 		return encoded == challenge
+	default:
+		return false
 	}
 }
 
+// ValidateRequestedScope checks the scope requested by the client on behalf of
+// a user. The OIDC scopes are always allowed in such flows.
 func (domain *OAuth2FlowDomain) ValidateRequestedScope(requestedScope scope.Scopes, client *OAuth2Client) error {
+	allowedScope := append(append(scope.Scopes{}, client.AllowedScope...), OIDCScopes...)
+	if !requestedScope.LessThanOrEqual(allowedScope) {
+		return fmt.Errorf("%w%s", ErrKnown, "the requested scope is exceed the client allowed scope")
+	}
+
+	return nil
+}
+
+// ValidateRequestedClientScope checks the scope requested by the client on
+// behalf of itself. There is no user, so the OIDC scopes are not allowed.
+func (domain *OAuth2FlowDomain) ValidateRequestedClientScope(requestedScope scope.Scopes, client *OAuth2Client) error {
 	if !requestedScope.LessThanOrEqual(client.AllowedScope) {
 		return fmt.Errorf("%w%s", ErrKnown, "the requested scope is exceed the client allowed scope")
 	}
@@ -238,11 +979,77 @@ func (domain *OAuth2FlowDomain) ValidateRequestedScope(requestedScope scope.Scop
 	return nil
 }
 
+// ValidateRefreshScope checks if the scope requested when refreshing is
+// covered by the scope originally granted to the refresh token (RFC 6749,
+// section 6).
+func (domain *OAuth2FlowDomain) ValidateRefreshScope(requestedScope scope.Scopes, refreshToken *OAuth2RefreshToken) error {
+	if !requestedScope.LessThanOrEqual(refreshToken.Scope) {
+		return fmt.Errorf("%w%s", ErrKnown, "the requested scope is exceed the originally granted scope")
+	}
+
+	return nil
+}
+
+// ValidateTrustedIssuer checks if the client is allowed to present the
+// assertion issued by the issuer, and if the assertion is short-lived.
+func (domain *OAuth2FlowDomain) ValidateTrustedIssuer(
+	issuer *OAuth2TrustedIssuer,
+	client *OAuth2Client,
+	issuedAt time.Time,
+	expiresAt time.Time,
+) error {
+	if !slices.Contains(issuer.ClientIDs, client.ID) {
+		return Wrap(ErrClientInvalid, "the client is not allowed to present assertions of %s", issuer.Issuer)
+	}
+
+	if expiresAt.Sub(issuedAt) > JWTBearerMaximumLifetime {
+		return Wrap(ErrAssertionInvalid, "the lifetime of the assertion must not exceed %s", JWTBearerMaximumLifetime)
+	}
+
+	return nil
+}
+
+// ValidateExchangeScope returns the scope of the token issued by the token
+// exchange. The requested scope must be covered by the subject token, it is
+// the whole scope of the subject token if omitted. The result is then narrowed
+// to the scope allowed for the client.
+func (domain *OAuth2FlowDomain) ValidateExchangeScope(
+	requestedScope scope.Scopes,
+	subjectToken *OAuth2AccessToken,
+	client *OAuth2Client,
+) (scope.Scopes, error) {
+	if len(requestedScope) == 0 {
+		requestedScope = subjectToken.Scope
+	}
+
+	if !requestedScope.LessThanOrEqual(subjectToken.Scope) {
+		return nil, fmt.Errorf("%w%s", ErrKnown, "the requested scope is exceed the scope of the subject token")
+	}
+
+	exchangedScope := requestedScope.Intersect(client.AllowedScope)
+	if len(exchangedScope) == 0 {
+		return nil, fmt.Errorf("%w%s", ErrKnown, "the client is not allowed any scope of the subject token")
+	}
+
+	return exchangedScope, nil
+}
+
+// ValidateActorChain checks if the subject token can be exchanged once more
+// without exceeding the maximum length of the delegation chain.
+func (domain *OAuth2FlowDomain) ValidateActorChain(subjectToken *OAuth2AccessToken) error {
+	if subjectToken.Actor.Length() >= MaximumActorChainLength {
+		return fmt.Errorf("%wthe delegation chain is longer than %d actors", ErrKnown, MaximumActorChainLength)
+	}
+
+	return nil
+}
+
 func (domain *OAuth2FlowDomain) NewSession(userID snowflake.ID) *Session {
 	return &Session{
-		State:     SessionStateAuthenticated,
-		UserID:    userID,
-		ExpiresAt: time.Now().Add(domain.SessionExpiration),
+		State:           SessionStateAuthenticated,
+		UserID:          userID,
+		AuthenticatedAt: time.Now(),
+		ExpiresAt:       time.Now().Add(domain.SessionExpiration),
 	}
 }
 
@@ -266,3 +1073,65 @@ func (domain *OAuth2FlowDomain) createMedata(aud string, sub snowflake.ID, expir
 		NotBefore: int(time.UnixMilli(id.Time()).Unix()),
 	}
 }
+
+// NormalizeUserCode converts the user code typed by the user to the canonical
+// form XXXX-XXXX. The user code is case-insensitive and all characters other
+// than letters are ignored.
+func NormalizeUserCode(userCode string) string {
+	letters := []rune{}
+	for _, c := range strings.ToUpper(userCode) {
+		if c >= 'A' && c <= 'Z' {
+			letters = append(letters, c)
+		}
+	}
+
+	if len(letters) != userCodeLength {
+		return string(letters)
+	}
+
+	return string(letters[:userCodeLength/2]) + "-" + string(letters[userCodeLength/2:])
+}
+
+func randUserCode() string {
+	b := make([]byte, userCodeLength)
+	for i := range b {
+		b[i] = userCodeCharset[xcrypto.RandInt(len(userCodeCharset))]
+	}
+
+	return NormalizeUserCode(string(b))
+}
+
+// tokenHash calculates the at_hash and c_hash claims of the ID token, which
+// are the base64url encoding of the left-most half of the hash of the value.
+// All algorithms supported by the token engine use SHA-256.
+func tokenHash(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(hash[:len(hash)/2])
+}
+
+// isCodeVerifierCharacter checks if the character is an unreserved character
+// of RFC 3986, which are the only characters allowed in code verifiers.
+func isCodeVerifierCharacter(c rune) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// matchDPoPURI compares the htu claim with the uri of the request, ignoring
+// the query and fragment components (RFC 9449 Section 4.3).
+func matchDPoPURI(htu, uri string) bool {
+	h, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(h.Scheme, u.Scheme) && strings.EqualFold(h.Host, u.Host) && h.Path == u.Path
+}
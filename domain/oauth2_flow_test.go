@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xybor-x/snowflake"
+)
+
+func newTestOAuth2FlowDomain(t *testing.T) *OAuth2FlowDomain {
+	node, err := snowflake.NewNode(1)
+	if err != nil {
+		t.Fatalf("failed to create snowflake node: %v", err)
+	}
+
+	return &OAuth2FlowDomain{
+		Snowflake:              node,
+		Issuer:                 "https://todennus.example",
+		AccessTokenExpiration:  time.Hour,
+		RefreshTokenExpiration: 24 * time.Hour,
+	}
+}
+
+func TestValidatePKCE(t *testing.T) {
+	validChallenge := strings.Repeat("a", MinimumCodeChallengeLength)
+
+	testcases := []struct {
+		name         string
+		allowPlain   bool
+		client       *OAuth2Client
+		challenge    string
+		method       string
+		expectMethod string
+		expectErr    bool
+	}{
+		{
+			name:   "confidential client without pkce",
+			client: &OAuth2Client{IsConfidential: true},
+		},
+		{
+			name:      "public client without pkce",
+			client:    &OAuth2Client{},
+			expectErr: true,
+		},
+		{
+			name:      "method without challenge",
+			client:    &OAuth2Client{IsConfidential: true},
+			method:    CodeChallengeMethodS256,
+			expectErr: true,
+		},
+		{
+			name:         "s256",
+			client:       &OAuth2Client{},
+			challenge:    validChallenge,
+			method:       CodeChallengeMethodS256,
+			expectMethod: CodeChallengeMethodS256,
+		},
+		{
+			name:         "default to plain",
+			allowPlain:   true,
+			client:       &OAuth2Client{},
+			challenge:    validChallenge,
+			expectMethod: CodeChallengeMethodPlain,
+		},
+		{
+			name:      "plain is not allowed by the server",
+			client:    &OAuth2Client{},
+			challenge: validChallenge,
+			method:    CodeChallengeMethodPlain,
+			expectErr: true,
+		},
+		{
+			name:       "plain is forbidden by the client",
+			allowPlain: true,
+			client:     &OAuth2Client{ForbidPlainCodeChallenge: true},
+			challenge:  validChallenge,
+			expectErr:  true,
+		},
+		{
+			name:      "unsupported method",
+			client:    &OAuth2Client{},
+			challenge: validChallenge,
+			method:    "S512",
+			expectErr: true,
+		},
+		{
+			name:      "too short challenge",
+			client:    &OAuth2Client{},
+			challenge: validChallenge[1:],
+			method:    CodeChallengeMethodS256,
+			expectErr: true,
+		},
+		{
+			name:      "too long challenge",
+			client:    &OAuth2Client{},
+			challenge: strings.Repeat("a", MaximumCodeChallengeLength+1),
+			method:    CodeChallengeMethodS256,
+			expectErr: true,
+		},
+		{
+			name:      "invalid character",
+			client:    &OAuth2Client{},
+			challenge: validChallenge[1:] + "+",
+			method:    CodeChallengeMethodS256,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			domain := newTestOAuth2FlowDomain(t)
+			domain.AllowPlainCodeChallenge = tc.allowPlain
+
+			method, err := domain.ValidatePKCE(tc.client, tc.challenge, tc.method)
+			if tc.expectErr {
+				if !errors.Is(err, ErrCodeChallengeInvalid) {
+					t.Fatalf("expect ErrCodeChallengeInvalid, but got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if method != tc.expectMethod {
+				t.Fatalf("expect method %q, but got %q", tc.expectMethod, method)
+			}
+		})
+	}
+}
+
+func TestValidateCodeChallenge(t *testing.T) {
+	// The example of RFC 7636, appendix B.
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	testcases := []struct {
+		name      string
+		verifier  string
+		challenge string
+		method    string
+		expect    bool
+	}{
+		{"s256", verifier, challenge, CodeChallengeMethodS256, true},
+		{"s256 mismatched", verifier + "x", challenge, CodeChallengeMethodS256, false},
+		{"plain", verifier, verifier, CodeChallengeMethodPlain, true},
+		{"plain mismatched", verifier, challenge, CodeChallengeMethodPlain, false},
+		{"unknown method", verifier, verifier, "S512", false},
+	}
+
+	domain := newTestOAuth2FlowDomain(t)
+	domain.AllowPlainCodeChallenge = true
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.ValidateCodeChallenge(tc.verifier, tc.challenge, tc.method); got != tc.expect {
+				t.Fatalf("expect %v, but got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	domain := newTestOAuth2FlowDomain(t)
+	userID := domain.Snowflake.Generate()
+	clientID := domain.Snowflake.Generate()
+
+	first := domain.CreateRefreshToken("aud", ScopeEngine.ParseScopes("read:user"), userID, clientID)
+	first.KeyThumbprint = "thumbprint"
+	accessToken := domain.CreateAccessToken("aud", first.Scope, &User{ID: userID}, clientID)
+	family := domain.CreateRefreshTokenFamily(first, accessToken)
+
+	second := domain.NextRefreshToken(first)
+	third := domain.NextRefreshToken(second)
+
+	for i, token := range []*OAuth2RefreshToken{first, second, third} {
+		if token.SequenceNumber != i {
+			t.Fatalf("expect sequence number %d, but got %d", i, token.SequenceNumber)
+		}
+
+		if token.Metadata.ID != family.ID {
+			t.Fatalf("expect the token %d to keep the family id %d, but got %d", i, family.ID, token.Metadata.ID)
+		}
+
+		if token.Metadata.Subject != userID || token.ClientID != clientID || token.KeyThumbprint != "thumbprint" {
+			t.Fatalf("expect the token %d to keep the grant of the family", i)
+		}
+	}
+
+	// The family records the sequence number of the latest token after each
+	// rotation, only that token is accepted.
+	family.SequenceNumber = third.SequenceNumber
+
+	testcases := []struct {
+		name   string
+		token  *OAuth2RefreshToken
+		expect bool
+	}{
+		{"latest token", third, true},
+		{"reused rotated token", second, false},
+		{"reused first token", first, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.IsLatestRefreshToken(family, tc.token.SequenceNumber); got != tc.expect {
+				t.Fatalf("expect %v, but got %v", tc.expect, got)
+			}
+		})
+	}
+}
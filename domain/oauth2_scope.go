@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"sort"
+
 	"github.com/xybor/todennus-backend/domain/definition"
 	"github.com/xybor/x/scope"
 )
@@ -8,3 +10,34 @@ import (
 var Actions, actionMap = scope.DefineAction[definition.Actions]()
 var Resources, resourceMap = scope.DefineResource[definition.Resource]()
 var ScopeEngine = scope.NewEngine("todennus", actionMap, resourceMap)
+
+// ScopeOpenID is not managed by the scope engine. It is requested by the
+// client to indicate an OpenID Connect request.
+var ScopeOpenID = scope.NewUndefinedScope("openid")
+
+// ScopeProfile allows the client to access the default profile claims of the
+// user from the UserInfo endpoint.
+var ScopeProfile = scope.NewUndefinedScope("profile")
+
+// OIDCScopes are scopes defined by OpenID Connect, any client is allowed to
+// request them.
+var OIDCScopes = scope.NewScopes(ScopeOpenID, ScopeProfile)
+
+// DefinedScopes returns all scopes known by the server, including the scopes
+// defined by OpenID Connect.
+func DefinedScopes() []string {
+	result := []string{}
+	for _, action := range actionMap {
+		for _, resource := range resourceMap {
+			result = append(result, ScopeEngine.New(action, resource).String())
+		}
+	}
+
+	sort.Strings(result)
+
+	for _, s := range OIDCScopes {
+		result = append(result, s.String())
+	}
+
+	return result
+}
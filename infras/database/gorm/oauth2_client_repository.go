@@ -31,8 +31,72 @@ func (repo *OAuth2ClientRepository) GetByID(ctx context.Context, clientID int64)
 	return model.To(), nil
 }
 
+func (repo *OAuth2ClientRepository) Update(ctx context.Context, client *domain.OAuth2Client) error {
+	model := model.NewOAuth2Client(client)
+	return database.ConvertError(repo.db.WithContext(ctx).Save(model).Error)
+}
+
+func (repo *OAuth2ClientRepository) List(ctx context.Context, offset, limit int) ([]*domain.OAuth2Client, error) {
+	models := []model.OAuth2ClientModel{}
+	err := repo.db.WithContext(ctx).Order("id").Offset(offset).Limit(limit).Find(&models).Error
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	return toOAuth2Clients(models), nil
+}
+
+func (repo *OAuth2ClientRepository) ListByOwner(
+	ctx context.Context,
+	ownerID int64,
+	offset, limit int,
+) ([]*domain.OAuth2Client, error) {
+	models := []model.OAuth2ClientModel{}
+	err := repo.db.WithContext(ctx).Where("user_id=?", ownerID).
+		Order("id").Offset(offset).Limit(limit).Find(&models).Error
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	return toOAuth2Clients(models), nil
+}
+
+// Delete removes the client along with the consents given to it and the
+// refresh tokens issued to it.
+func (repo *OAuth2ClientRepository) Delete(ctx context.Context, clientID int64) error {
+	return database.ConvertError(repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.OAuth2ConsentModel{}, "client_id=?", clientID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.RefreshTokenModel{}, "client_id=?", clientID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.OAuth2ClientModel{}, "id=?", clientID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return database.ErrRecordNotFound
+		}
+
+		return nil
+	}))
+}
+
 func (repo *OAuth2ClientRepository) Count(ctx context.Context) (int64, error) {
 	var n int64
 	err := repo.db.WithContext(ctx).Model(&model.OAuth2ClientModel{}).Count(&n).Error
 	return n, database.ConvertError(err)
 }
+
+func toOAuth2Clients(models []model.OAuth2ClientModel) []*domain.OAuth2Client {
+	clients := make([]*domain.OAuth2Client, 0, len(models))
+	for _, model := range models {
+		clients = append(clients, model.To())
+	}
+
+	return clients
+}
@@ -2,7 +2,9 @@ package gorm
 
 import (
 	"context"
+	"time"
 
+	"github.com/xybor/todennus-backend/domain"
 	"github.com/xybor/todennus-backend/infras/database"
 	"github.com/xybor/todennus-backend/infras/database/model"
 	"gorm.io/gorm"
@@ -18,15 +20,20 @@ func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
 
 func (repo *RefreshTokenRepository) Create(
 	ctx context.Context,
-	refreshTokenId,
-	accessTokenID int64,
-	seq int,
+	family *domain.OAuth2RefreshTokenFamily,
 ) error {
-	return database.ConvertError(repo.db.WithContext(ctx).Create(&model.RefreshTokenModel{
-		RefreshTokenID: refreshTokenId,
-		AccessTokenID:  accessTokenID,
-		Seq:            seq,
-	}).Error)
+	return database.ConvertError(repo.db.WithContext(ctx).Create(model.NewRefreshToken(family)).Error)
+}
+
+func (repo *RefreshTokenRepository) GetByRefreshTokenID(
+	ctx context.Context, refreshTokenID int64,
+) (*domain.OAuth2RefreshTokenFamily, error) {
+	model := model.RefreshTokenModel{}
+	if err := repo.db.WithContext(ctx).Take(&model, "refresh_token_id=?", refreshTokenID).Error; err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	return model.To(), nil
 }
 
 func (repo *RefreshTokenRepository) UpdateByRefreshTokenID(
@@ -39,13 +46,20 @@ func (repo *RefreshTokenRepository) UpdateByRefreshTokenID(
 		Updates(map[string]any{
 			"seq":             expectedCurSeq + 1,
 			"access_token_id": accessTokenID,
+			"last_used_at":    time.Now(),
 		})
 
+	if result.Error != nil {
+		return database.ConvertError(result.Error)
+	}
+
+	// No row matches the expected sequence number, the refresh token was
+	// already rotated or revoked.
 	if result.RowsAffected == 0 {
 		return database.ErrRecordNotFound
 	}
 
-	return database.ConvertError(result.Error)
+	return nil
 }
 
 func (repo *RefreshTokenRepository) DeleteByRefreshTokenID(
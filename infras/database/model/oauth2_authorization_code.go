@@ -12,8 +12,11 @@ type OAuth2AuthorizationCodeModel struct {
 	UserID              int64  `json:"uid"`
 	ClientID            int64  `json:"cid"`
 	Scope               string `json:"scp"`
+	RedirectURI         string `json:"rdr"`
 	CodeChallenge       string `json:"chl"`
 	CodeChallengeMethod string `json:"cmt"`
+	Nonce               string `json:"non,omitempty"`
+	AuthTime            int64  `json:"ath"`
 	ExpiresAt           int64  `json:"exp"`
 }
 
@@ -23,8 +26,11 @@ func NewOAuth2AuthorizationCode(code *domain.OAuth2AuthorizationCode) *OAuth2Aut
 		UserID:              code.UserID.Int64(),
 		ClientID:            code.ClientID.Int64(),
 		Scope:               code.Scope.String(),
+		RedirectURI:         code.RedirectURI,
 		CodeChallenge:       code.CodeChallenge,
 		CodeChallengeMethod: code.CodeChallengeMethod,
+		Nonce:               code.Nonce,
+		AuthTime:            code.AuthTime.UnixMilli(),
 		ExpiresAt:           code.ExpiresAt.UnixMilli(),
 	}
 }
@@ -35,8 +41,11 @@ func (code OAuth2AuthorizationCodeModel) To() *domain.OAuth2AuthorizationCode {
 		UserID:              snowflake.ID(code.UserID),
 		ClientID:            snowflake.ID(code.ClientID),
 		Scope:               domain.ScopeEngine.ParseScopes(code.Scope),
+		RedirectURI:         code.RedirectURI,
 		CodeChallenge:       code.CodeChallenge,
 		CodeChallengeMethod: code.CodeChallengeMethod,
+		Nonce:               code.Nonce,
+		AuthTime:            time.UnixMilli(code.AuthTime),
 		ExpiresAt:           time.UnixMilli(code.ExpiresAt),
 	}
 }
@@ -45,13 +54,19 @@ type OAuth2AuthorizationStoreModel struct {
 	ID                  string `json:"-"`
 	IsOpen              bool   `json:"iop"`
 	ResponseType        string `json:"res"`
+	ResponseMode        string `json:"rmo,omitempty"`
 	ClientID            int64  `json:"cid"`
 	RedirectURI         string `json:"rdr"`
 	Scope               string `json:"scp"`
 	State               string `json:"sta"`
 	CodeChallenge       string `json:"chl"`
 	CodeChallengeMethod string `json:"cmt"`
+	Nonce               string `json:"non,omitempty"`
+	UserCode            string `json:"usc,omitempty"`
 	ExpiresAt           int64  `json:"exp"`
+	Pushed              bool   `json:"psh,omitempty"`
+	AuthReqID           string `json:"arq,omitempty"`
+	UserID              int64  `json:"uid,omitempty"`
 }
 
 func NewOAuth2AuthorizationStore(store *domain.OAuth2AuthorizationStore) *OAuth2AuthorizationStoreModel {
@@ -59,13 +74,19 @@ func NewOAuth2AuthorizationStore(store *domain.OAuth2AuthorizationStore) *OAuth2
 		ID:                  store.ID,
 		IsOpen:              store.IsOpen,
 		ResponseType:        store.ResponseType,
+		ResponseMode:        store.ResponseMode,
 		ClientID:            store.ClientID.Int64(),
 		RedirectURI:         store.RedirectURI,
 		Scope:               store.Scope.String(),
 		State:               store.State,
 		CodeChallenge:       store.CodeChallenge,
 		CodeChallengeMethod: store.CodeChallengeMethod,
+		Nonce:               store.Nonce,
+		UserCode:            store.UserCode,
 		ExpiresAt:           store.ExpiresAt.UnixMilli(),
+		Pushed:              store.Pushed,
+		AuthReqID:           store.AuthReqID,
+		UserID:              store.UserID.Int64(),
 	}
 }
 
@@ -74,13 +95,19 @@ func (store OAuth2AuthorizationStoreModel) To() *domain.OAuth2AuthorizationStore
 		ID:                  store.ID,
 		IsOpen:              store.IsOpen,
 		ResponseType:        store.ResponseType,
+		ResponseMode:        store.ResponseMode,
 		ClientID:            snowflake.ID(store.ClientID),
 		RedirectURI:         store.RedirectURI,
 		Scope:               domain.ScopeEngine.ParseScopes(store.Scope),
 		State:               store.State,
 		CodeChallenge:       store.CodeChallenge,
 		CodeChallengeMethod: store.CodeChallengeMethod,
+		Nonce:               store.Nonce,
+		UserCode:            store.UserCode,
 		ExpiresAt:           time.UnixMilli(store.ExpiresAt),
+		Pushed:              store.Pushed,
+		AuthReqID:           store.AuthReqID,
+		UserID:              snowflake.ID(store.UserID),
 	}
 }
 
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+)
+
+type OAuth2BackchannelAuthenticationModel struct {
+	AuthReqID               string `json:"-"`
+	AuthorizationID         string `json:"aid"`
+	ClientID                int64  `json:"cid"`
+	UserID                  int64  `json:"uid"`
+	Scope                   string `json:"scp"`
+	BindingMessage          string `json:"bdm,omitempty"`
+	ClientNotificationToken string `json:"cnt,omitempty"`
+	Status                  int    `json:"sta"`
+	AuthTime                int64  `json:"ath,omitempty"`
+	Interval                int64  `json:"itv"`
+	LastPolledAt            int64  `json:"lpa"`
+	ExpiresAt               int64  `json:"exp"`
+}
+
+func NewOAuth2BackchannelAuthentication(req *domain.OAuth2BackchannelAuthentication) *OAuth2BackchannelAuthenticationModel {
+	return &OAuth2BackchannelAuthenticationModel{
+		AuthReqID:               req.AuthReqID,
+		AuthorizationID:         req.AuthorizationID,
+		ClientID:                req.ClientID.Int64(),
+		UserID:                  req.UserID.Int64(),
+		Scope:                   req.Scope.String(),
+		BindingMessage:          req.BindingMessage,
+		ClientNotificationToken: req.ClientNotificationToken,
+		Status:                  int(req.Status),
+		AuthTime:                req.AuthTime.UnixMilli(),
+		Interval:                req.Interval.Milliseconds(),
+		LastPolledAt:            req.LastPolledAt.UnixMilli(),
+		ExpiresAt:               req.ExpiresAt.UnixMilli(),
+	}
+}
+
+func (req OAuth2BackchannelAuthenticationModel) To() *domain.OAuth2BackchannelAuthentication {
+	return &domain.OAuth2BackchannelAuthentication{
+		AuthReqID:               req.AuthReqID,
+		AuthorizationID:         req.AuthorizationID,
+		ClientID:                snowflake.ID(req.ClientID),
+		UserID:                  snowflake.ID(req.UserID),
+		Scope:                   domain.ScopeEngine.ParseScopes(req.Scope),
+		BindingMessage:          req.BindingMessage,
+		ClientNotificationToken: req.ClientNotificationToken,
+		Status:                  domain.BackchannelAuthenticationStatus(req.Status),
+		AuthTime:                time.UnixMilli(req.AuthTime),
+		Interval:                time.Duration(req.Interval) * time.Millisecond,
+		LastPolledAt:            time.UnixMilli(req.LastPolledAt),
+		ExpiresAt:               time.UnixMilli(req.ExpiresAt),
+	}
+}
+
+// OAuth2BackchannelPollModel is stored apart from
+// OAuth2BackchannelAuthenticationModel, so that the polling of the client never
+// overwrites the status of the backchannel authentication.
+type OAuth2BackchannelPollModel struct {
+	Interval     int64 `json:"itv"`
+	LastPolledAt int64 `json:"lpa"`
+}
+
+func NewOAuth2BackchannelPoll(req *domain.OAuth2BackchannelAuthentication) *OAuth2BackchannelPollModel {
+	return &OAuth2BackchannelPollModel{
+		Interval:     req.Interval.Milliseconds(),
+		LastPolledAt: req.LastPolledAt.UnixMilli(),
+	}
+}
+
+func (poll OAuth2BackchannelPollModel) Apply(req *domain.OAuth2BackchannelAuthentication) {
+	req.Interval = time.Duration(poll.Interval) * time.Millisecond
+	req.LastPolledAt = time.UnixMilli(poll.LastPolledAt)
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/xybor-x/snowflake"
@@ -8,13 +9,28 @@ import (
 )
 
 type OAuth2ClientModel struct {
-	ID             int64     `gorm:"id;primaryKey"`
-	UserID         int64     `gorm:"user_id"`
-	Name           string    `gorm:"name"`
-	HashedSecret   string    `gorm:"hashed_secret"`
-	IsConfidential bool      `gorm:"is_confidential"`
-	AllowedScope   string    `gorm:"allowed_scope"`
-	UpdatedAt      time.Time `gorm:"updated_at"`
+	ID                int64     `gorm:"id;primaryKey"`
+	UserID            int64     `gorm:"user_id"`
+	Name              string    `gorm:"name"`
+	HashedSecret      string    `gorm:"hashed_secret"`
+	IsConfidential    bool      `gorm:"is_confidential"`
+	AllowedScope      string    `gorm:"allowed_scope"`
+	RedirectURIs      string    `gorm:"redirect_uris"`
+	AllowedResources  string    `gorm:"allowed_resources"`
+	AllowImplicitFlow bool      `gorm:"allow_implicit_flow"`
+	RequirePAR        bool      `gorm:"require_pushed_authorization_requests"`
+	JWKS              string    `gorm:"jwks"`
+	ForbidPlainPKCE   bool      `gorm:"forbid_plain_code_challenge"`
+	AuthMethod        string    `gorm:"token_endpoint_auth_method"`
+	TLSSubjectDN      string    `gorm:"tls_client_auth_subject_dn"`
+	BackchannelMode   string    `gorm:"backchannel_token_delivery_mode"`
+	BackchannelNotify string    `gorm:"backchannel_client_notification_endpoint"`
+	GrantTypes        string    `gorm:"grant_types"`
+	ResponseTypes     string    `gorm:"response_types"`
+	LogoURI           string    `gorm:"logo_uri"`
+	HashedRegToken    string    `gorm:"hashed_registration_access_token"`
+	IsDisabled        bool      `gorm:"is_disabled"`
+	UpdatedAt         time.Time `gorm:"updated_at"`
 }
 
 func (OAuth2ClientModel) TableName() string {
@@ -23,24 +39,66 @@ func (OAuth2ClientModel) TableName() string {
 
 func NewOAuth2Client(domain *domain.OAuth2Client) *OAuth2ClientModel {
 	return &OAuth2ClientModel{
-		ID:             domain.ID.Int64(),
-		UserID:         domain.OwnerUserID.Int64(),
-		Name:           domain.Name,
-		HashedSecret:   domain.HashedSecret,
-		IsConfidential: domain.IsConfidential,
-		UpdatedAt:      domain.UpdatedAt,
-		AllowedScope:   domain.AllowedScope.String(),
+		ID:               domain.ID.Int64(),
+		UserID:           domain.OwnerUserID.Int64(),
+		Name:             domain.Name,
+		HashedSecret:     domain.HashedSecret,
+		IsConfidential:   domain.IsConfidential,
+		UpdatedAt:        domain.UpdatedAt,
+		AllowedScope:     domain.AllowedScope.String(),
+		RedirectURIs:     strings.Join(domain.RedirectURIs, " "),
+		AllowedResources: strings.Join(domain.AllowedResources, " "),
+
+		AllowImplicitFlow: domain.AllowImplicitFlow,
+		RequirePAR:        domain.RequirePushedAuthorizationRequests,
+		JWKS:              domain.JWKS,
+		ForbidPlainPKCE:   domain.ForbidPlainCodeChallenge,
+		AuthMethod:        domain.TokenEndpointAuthMethod,
+		TLSSubjectDN:      domain.TLSClientAuthSubjectDN,
+		BackchannelMode:   domain.BackchannelTokenDeliveryMode,
+		BackchannelNotify: domain.BackchannelClientNotificationEndpoint,
+		GrantTypes:        strings.Join(domain.GrantTypes, " "),
+		ResponseTypes:     strings.Join(domain.ResponseTypes, ","),
+		LogoURI:           domain.LogoURI,
+		HashedRegToken:    domain.HashedRegistrationAccessToken,
+		IsDisabled:        domain.IsDisabled,
 	}
 }
 
 func (client OAuth2ClientModel) To() *domain.OAuth2Client {
 	return &domain.OAuth2Client{
-		ID:             snowflake.ID(client.ID),
-		OwnerUserID:    snowflake.ID(client.UserID),
-		Name:           client.Name,
-		HashedSecret:   client.HashedSecret,
-		IsConfidential: client.IsConfidential,
-		AllowedScope:   domain.ScopeEngine.ParseScopes(client.AllowedScope),
-		UpdatedAt:      client.UpdatedAt,
+		ID:               snowflake.ID(client.ID),
+		OwnerUserID:      snowflake.ID(client.UserID),
+		Name:             client.Name,
+		HashedSecret:     client.HashedSecret,
+		IsConfidential:   client.IsConfidential,
+		AllowedScope:     domain.ScopeEngine.ParseScopes(client.AllowedScope),
+		RedirectURIs:     strings.Fields(client.RedirectURIs),
+		AllowedResources: strings.Fields(client.AllowedResources),
+		UpdatedAt:        client.UpdatedAt,
+
+		AllowImplicitFlow:                     client.AllowImplicitFlow,
+		RequirePushedAuthorizationRequests:    client.RequirePAR,
+		JWKS:                                  client.JWKS,
+		ForbidPlainCodeChallenge:              client.ForbidPlainPKCE,
+		TokenEndpointAuthMethod:               client.AuthMethod,
+		TLSClientAuthSubjectDN:                client.TLSSubjectDN,
+		BackchannelTokenDeliveryMode:          client.BackchannelMode,
+		BackchannelClientNotificationEndpoint: client.BackchannelNotify,
+		GrantTypes:                            strings.Fields(client.GrantTypes),
+		ResponseTypes:                         splitResponseTypes(client.ResponseTypes),
+		LogoURI:                               client.LogoURI,
+		HashedRegistrationAccessToken:         client.HashedRegToken,
+		IsDisabled:                            client.IsDisabled,
+	}
+}
+
+// splitResponseTypes splits the comma-separated response types, a response
+// type itself can contain spaces, such as "code id_token".
+func splitResponseTypes(responseTypes string) []string {
+	if responseTypes == "" {
+		return nil
 	}
+
+	return strings.Split(responseTypes, ",")
 }
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+)
+
+type OAuth2DeviceCodeModel struct {
+	DeviceCode   string `json:"-"`
+	UserCode     string `json:"usc"`
+	ClientID     int64  `json:"cid"`
+	Scope        string `json:"scp"`
+	Status       int    `json:"sta"`
+	UserID       int64  `json:"uid,omitempty"`
+	AuthTime     int64  `json:"ath,omitempty"`
+	Interval     int64  `json:"itv"`
+	LastPolledAt int64  `json:"lpa"`
+	ExpiresAt    int64  `json:"exp"`
+}
+
+func NewOAuth2DeviceCode(code *domain.OAuth2DeviceCode) *OAuth2DeviceCodeModel {
+	return &OAuth2DeviceCodeModel{
+		DeviceCode:   code.DeviceCode,
+		UserCode:     code.UserCode,
+		ClientID:     code.ClientID.Int64(),
+		Scope:        code.Scope.String(),
+		Status:       int(code.Status),
+		UserID:       code.UserID.Int64(),
+		AuthTime:     code.AuthTime.UnixMilli(),
+		Interval:     code.Interval.Milliseconds(),
+		LastPolledAt: code.LastPolledAt.UnixMilli(),
+		ExpiresAt:    code.ExpiresAt.UnixMilli(),
+	}
+}
+
+func (code OAuth2DeviceCodeModel) To() *domain.OAuth2DeviceCode {
+	return &domain.OAuth2DeviceCode{
+		DeviceCode:   code.DeviceCode,
+		UserCode:     code.UserCode,
+		ClientID:     snowflake.ID(code.ClientID),
+		Scope:        domain.ScopeEngine.ParseScopes(code.Scope),
+		Status:       domain.DeviceCodeStatus(code.Status),
+		UserID:       snowflake.ID(code.UserID),
+		AuthTime:     time.UnixMilli(code.AuthTime),
+		Interval:     time.Duration(code.Interval) * time.Millisecond,
+		LastPolledAt: time.UnixMilli(code.LastPolledAt),
+		ExpiresAt:    time.UnixMilli(code.ExpiresAt),
+	}
+}
+
+// OAuth2DevicePollModel is stored apart from OAuth2DeviceCodeModel, so that
+// the polling of the client never overwrites the status of the device code.
+type OAuth2DevicePollModel struct {
+	Interval     int64 `json:"itv"`
+	LastPolledAt int64 `json:"lpa"`
+}
+
+func NewOAuth2DevicePoll(code *domain.OAuth2DeviceCode) *OAuth2DevicePollModel {
+	return &OAuth2DevicePollModel{
+		Interval:     code.Interval.Milliseconds(),
+		LastPolledAt: code.LastPolledAt.UnixMilli(),
+	}
+}
+
+func (poll OAuth2DevicePollModel) Apply(code *domain.OAuth2DeviceCode) {
+	code.Interval = time.Duration(poll.Interval) * time.Millisecond
+	code.LastPolledAt = time.UnixMilli(poll.LastPolledAt)
+}
@@ -1,14 +1,50 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+)
 
 type RefreshTokenModel struct {
 	RefreshTokenID int64     `gorm:"refresh_token_id;primaryKey"`
 	AccessTokenID  int64     `gorm:"access_token_id"`
 	Seq            int       `gorm:"seq"`
+	UserID         int64     `gorm:"user_id"`
+	ClientID       int64     `gorm:"client_id"`
+	Scope          string    `gorm:"scope"`
+	CreatedAt      time.Time `gorm:"created_at"`
+	LastUsedAt     time.Time `gorm:"last_used_at"`
 	UpdatedAt      time.Time `gorm:"updated_at"`
 }
 
 func (RefreshTokenModel) TableName() string {
 	return "refresh_tokens"
 }
+
+func NewRefreshToken(family *domain.OAuth2RefreshTokenFamily) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		RefreshTokenID: family.ID.Int64(),
+		AccessTokenID:  family.AccessTokenID.Int64(),
+		Seq:            family.SequenceNumber,
+		UserID:         family.UserID.Int64(),
+		ClientID:       family.ClientID.Int64(),
+		Scope:          family.Scope.String(),
+		CreatedAt:      family.CreatedAt,
+		LastUsedAt:     family.LastUsedAt,
+	}
+}
+
+func (token RefreshTokenModel) To() *domain.OAuth2RefreshTokenFamily {
+	return &domain.OAuth2RefreshTokenFamily{
+		ID:             snowflake.ID(token.RefreshTokenID),
+		AccessTokenID:  snowflake.ID(token.AccessTokenID),
+		SequenceNumber: token.Seq,
+		UserID:         snowflake.ID(token.UserID),
+		ClientID:       snowflake.ID(token.ClientID),
+		Scope:          domain.ScopeEngine.ParseScopes(token.Scope),
+		CreatedAt:      token.CreatedAt,
+		LastUsedAt:     token.LastUsedAt,
+	}
+}
@@ -20,6 +20,10 @@ func oauth2AuthorizationStoreKey(code string) string {
 	return fmt.Sprintf("oauth2_store:%s", code)
 }
 
+func oauth2PushedAuthorizationStoreKey(id string) string {
+	return fmt.Sprintf("oauth2_par:%s", id)
+}
+
 func oauth2AuthenticationResultKey(code string) string {
 	return fmt.Sprintf("oauth2_auth:%s", code)
 }
@@ -112,6 +116,45 @@ func (repo *OAuth2AuthorizationCodeRepository) DeleteAuthorizationStore(
 	return database.ConvertError(repo.client.Del(ctx, oauth2AuthorizationStoreKey(id)).Err())
 }
 
+func (repo *OAuth2AuthorizationCodeRepository) SavePushedAuthorizationStore(
+	ctx context.Context,
+	store *domain.OAuth2AuthorizationStore,
+) error {
+	model := model.NewOAuth2AuthorizationStore(store)
+
+	modelJSON, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx,
+		oauth2PushedAuthorizationStoreKey(model.ID), modelJSON, time.Until(store.ExpiresAt)).Err())
+}
+
+func (repo *OAuth2AuthorizationCodeRepository) LoadPushedAuthorizationStore(
+	ctx context.Context,
+	id string,
+) (*domain.OAuth2AuthorizationStore, error) {
+	result, err := repo.client.Get(ctx, oauth2PushedAuthorizationStoreKey(id)).Result()
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	model := model.OAuth2AuthorizationStoreModel{ID: id}
+	if err := json.Unmarshal([]byte(result), &model); err != nil {
+		return nil, err
+	}
+
+	return model.To(), nil
+}
+
+func (repo *OAuth2AuthorizationCodeRepository) DeletePushedAuthorizationStore(
+	ctx context.Context,
+	id string,
+) error {
+	return database.ConvertError(repo.client.Del(ctx, oauth2PushedAuthorizationStoreKey(id)).Err())
+}
+
 func (repo *OAuth2AuthorizationCodeRepository) SaveAuthenticationResult(
 	ctx context.Context,
 	result *domain.OAuth2AuthenticationResult,
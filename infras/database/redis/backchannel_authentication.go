@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/todennus-backend/infras/database"
+	"github.com/xybor/todennus-backend/infras/database/model"
+)
+
+func oauth2BackchannelAuthenticationKey(authReqID string) string {
+	return fmt.Sprintf("oauth2_ciba:%s", authReqID)
+}
+
+func oauth2BackchannelPollKey(authReqID string) string {
+	return fmt.Sprintf("oauth2_ciba_poll:%s", authReqID)
+}
+
+type OAuth2BackchannelAuthenticationRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2BackchannelAuthenticationRepository(client *redis.Client) *OAuth2BackchannelAuthenticationRepository {
+	return &OAuth2BackchannelAuthenticationRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2BackchannelAuthenticationRepository) Save(
+	ctx context.Context,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	model := model.NewOAuth2BackchannelAuthentication(req)
+
+	modelJSON, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx,
+		oauth2BackchannelAuthenticationKey(model.AuthReqID), modelJSON, time.Until(req.ExpiresAt)).Err())
+}
+
+// UpdateStatus saves the status of the backchannel authentication only if the
+// stored one is still pending, it returns database.ErrRecordNotFound
+// otherwise.
+func (repo *OAuth2BackchannelAuthenticationRepository) UpdateStatus(
+	ctx context.Context,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	modelJSON, err := json.Marshal(model.NewOAuth2BackchannelAuthentication(req))
+	if err != nil {
+		return err
+	}
+
+	key := oauth2BackchannelAuthenticationKey(req.AuthReqID)
+	err = repo.client.Watch(ctx, func(tx *redis.Tx) error {
+		result, err := tx.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		var stored model.OAuth2BackchannelAuthenticationModel
+		if err := json.Unmarshal([]byte(result), &stored); err != nil {
+			return err
+		}
+
+		if domain.BackchannelAuthenticationStatus(stored.Status) != domain.BackchannelAuthenticationStatusPending {
+			return database.ErrRecordNotFound
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, key, modelJSON, redis.SetArgs{KeepTTL: true})
+			return nil
+		})
+
+		return err
+	}, key)
+
+	// The backchannel authentication is changed by another request after it
+	// was watched.
+	if errors.Is(err, redis.TxFailedErr) {
+		return database.ErrRecordNotFound
+	}
+
+	return database.ConvertError(err)
+}
+
+// SavePoll saves the polling state of the client.
+func (repo *OAuth2BackchannelAuthenticationRepository) SavePoll(
+	ctx context.Context,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	pollJSON, err := json.Marshal(model.NewOAuth2BackchannelPoll(req))
+	if err != nil {
+		return err
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx,
+		oauth2BackchannelPollKey(req.AuthReqID), pollJSON, time.Until(req.ExpiresAt)).Err())
+}
+
+func (repo *OAuth2BackchannelAuthenticationRepository) Load(
+	ctx context.Context,
+	authReqID string,
+) (*domain.OAuth2BackchannelAuthentication, error) {
+	results, err := repo.client.MGet(ctx,
+		oauth2BackchannelAuthenticationKey(authReqID), oauth2BackchannelPollKey(authReqID)).Result()
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	result, ok := results[0].(string)
+	if !ok {
+		return nil, database.ErrRecordNotFound
+	}
+
+	reqModel := model.OAuth2BackchannelAuthenticationModel{AuthReqID: authReqID}
+	if err := json.Unmarshal([]byte(result), &reqModel); err != nil {
+		return nil, err
+	}
+
+	req := reqModel.To()
+	if pollResult, ok := results[1].(string); ok {
+		var poll model.OAuth2BackchannelPollModel
+		if err := json.Unmarshal([]byte(pollResult), &poll); err != nil {
+			return nil, err
+		}
+
+		poll.Apply(req)
+	}
+
+	return req, nil
+}
+
+// Delete returns database.ErrRecordNotFound if the backchannel authentication
+// has already been deleted, so that it can not be redeemed twice.
+func (repo *OAuth2BackchannelAuthenticationRepository) Delete(ctx context.Context, authReqID string) error {
+	var del *redis.IntCmd
+	_, err := repo.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(ctx, oauth2BackchannelAuthenticationKey(authReqID))
+		pipe.Del(ctx, oauth2BackchannelPollKey(authReqID))
+		return nil
+	})
+	if err != nil {
+		return database.ConvertError(err)
+	}
+
+	if del.Val() == 0 {
+		return database.ErrRecordNotFound
+	}
+
+	return nil
+}
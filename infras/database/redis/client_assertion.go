@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xybor/todennus-backend/infras/database"
+)
+
+func oauth2ClientAssertionKey(clientID int64, assertionID string) string {
+	return fmt.Sprintf("oauth2_client_assertion:%d:%s", clientID, assertionID)
+}
+
+type OAuth2ClientAssertionRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2ClientAssertionRepository(client *redis.Client) *OAuth2ClientAssertionRepository {
+	return &OAuth2ClientAssertionRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2ClientAssertionRepository) Add(
+	ctx context.Context,
+	clientID int64,
+	assertionID string,
+	expiresAt time.Time,
+) (bool, error) {
+	expiration := time.Until(expiresAt)
+	if expiration <= 0 {
+		return false, nil
+	}
+
+	ok, err := repo.client.SetNX(ctx, oauth2ClientAssertionKey(clientID, assertionID), 1, expiration).Result()
+	return ok, database.ConvertError(err)
+}
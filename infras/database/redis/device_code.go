@@ -0,0 +1,206 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/todennus-backend/infras/database"
+	"github.com/xybor/todennus-backend/infras/database/model"
+)
+
+func oauth2DeviceCodeKey(deviceCode string) string {
+	return fmt.Sprintf("oauth2_device:%s", deviceCode)
+}
+
+func oauth2UserCodeKey(userCode string) string {
+	return fmt.Sprintf("oauth2_user_code:%s", userCode)
+}
+
+func oauth2DevicePollKey(deviceCode string) string {
+	return fmt.Sprintf("oauth2_device_poll:%s", deviceCode)
+}
+
+func oauth2UserCodeAttemptKey(address string) string {
+	return fmt.Sprintf("oauth2_user_code_attempt:%s", address)
+}
+
+type OAuth2DeviceCodeRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2DeviceCodeRepository(client *redis.Client) *OAuth2DeviceCodeRepository {
+	return &OAuth2DeviceCodeRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2DeviceCodeRepository) SaveDeviceCode(
+	ctx context.Context,
+	code *domain.OAuth2DeviceCode,
+) error {
+	model := model.NewOAuth2DeviceCode(code)
+
+	modelJSON, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	expiration := time.Until(code.ExpiresAt)
+	_, err = repo.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetEx(ctx, oauth2DeviceCodeKey(model.DeviceCode), modelJSON, expiration)
+		pipe.SetEx(ctx, oauth2UserCodeKey(model.UserCode), model.DeviceCode, expiration)
+		return nil
+	})
+
+	return database.ConvertError(err)
+}
+
+// UpdateDeviceCodeStatus saves the status of the device code only if the
+// stored one is still pending, it returns database.ErrRecordNotFound
+// otherwise.
+func (repo *OAuth2DeviceCodeRepository) UpdateDeviceCodeStatus(
+	ctx context.Context,
+	code *domain.OAuth2DeviceCode,
+) error {
+	modelJSON, err := json.Marshal(model.NewOAuth2DeviceCode(code))
+	if err != nil {
+		return err
+	}
+
+	key := oauth2DeviceCodeKey(code.DeviceCode)
+	err = repo.client.Watch(ctx, func(tx *redis.Tx) error {
+		result, err := tx.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		var stored model.OAuth2DeviceCodeModel
+		if err := json.Unmarshal([]byte(result), &stored); err != nil {
+			return err
+		}
+
+		if domain.DeviceCodeStatus(stored.Status) != domain.DeviceCodeStatusPending {
+			return database.ErrRecordNotFound
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, key, modelJSON, redis.SetArgs{KeepTTL: true})
+			return nil
+		})
+
+		return err
+	}, key)
+
+	// The device code is changed by another request after it was watched.
+	if errors.Is(err, redis.TxFailedErr) {
+		return database.ErrRecordNotFound
+	}
+
+	return database.ConvertError(err)
+}
+
+// SaveDeviceCodePoll saves the polling state of the client.
+func (repo *OAuth2DeviceCodeRepository) SaveDeviceCodePoll(
+	ctx context.Context,
+	code *domain.OAuth2DeviceCode,
+) error {
+	pollJSON, err := json.Marshal(model.NewOAuth2DevicePoll(code))
+	if err != nil {
+		return err
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx,
+		oauth2DevicePollKey(code.DeviceCode), pollJSON, time.Until(code.ExpiresAt)).Err())
+}
+
+func (repo *OAuth2DeviceCodeRepository) LoadDeviceCode(
+	ctx context.Context,
+	deviceCode string,
+) (*domain.OAuth2DeviceCode, error) {
+	results, err := repo.client.MGet(ctx, oauth2DeviceCodeKey(deviceCode), oauth2DevicePollKey(deviceCode)).Result()
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	result, ok := results[0].(string)
+	if !ok {
+		return nil, database.ErrRecordNotFound
+	}
+
+	codeModel := model.OAuth2DeviceCodeModel{DeviceCode: deviceCode}
+	if err := json.Unmarshal([]byte(result), &codeModel); err != nil {
+		return nil, err
+	}
+
+	code := codeModel.To()
+	if pollResult, ok := results[1].(string); ok {
+		var poll model.OAuth2DevicePollModel
+		if err := json.Unmarshal([]byte(pollResult), &poll); err != nil {
+			return nil, err
+		}
+
+		poll.Apply(code)
+	}
+
+	return code, nil
+}
+
+func (repo *OAuth2DeviceCodeRepository) LoadDeviceCodeByUserCode(
+	ctx context.Context,
+	userCode string,
+) (*domain.OAuth2DeviceCode, error) {
+	deviceCode, err := repo.client.Get(ctx, oauth2UserCodeKey(userCode)).Result()
+	if err != nil {
+		return nil, database.ConvertError(err)
+	}
+
+	return repo.LoadDeviceCode(ctx, deviceCode)
+}
+
+// DeleteDeviceCode returns database.ErrRecordNotFound if the device code has
+// already been deleted, so that it can not be redeemed twice.
+func (repo *OAuth2DeviceCodeRepository) DeleteDeviceCode(
+	ctx context.Context,
+	code *domain.OAuth2DeviceCode,
+) error {
+	var del *redis.IntCmd
+	_, err := repo.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(ctx, oauth2DeviceCodeKey(code.DeviceCode))
+		pipe.Del(ctx, oauth2UserCodeKey(code.UserCode), oauth2DevicePollKey(code.DeviceCode))
+		return nil
+	})
+	if err != nil {
+		return database.ConvertError(err)
+	}
+
+	if del.Val() == 0 {
+		return database.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (repo *OAuth2DeviceCodeRepository) AddUserCodeAttempt(
+	ctx context.Context,
+	address string,
+	window time.Duration,
+) (int64, error) {
+	key := oauth2UserCodeAttemptKey(address)
+
+	var incr *redis.IntCmd
+	_, err := repo.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, database.ConvertError(err)
+	}
+
+	return incr.Val(), nil
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/todennus-backend/infras/database"
+)
+
+func oauth2DPoPProofKey(keyThumbprint, proofID string) string {
+	return fmt.Sprintf("oauth2_dpop_proof:%s:%s", keyThumbprint, proofID)
+}
+
+func oauth2DPoPNonceKey(nonce string) string {
+	return fmt.Sprintf("oauth2_dpop_nonce:%s", nonce)
+}
+
+type OAuth2DPoPProofRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2DPoPProofRepository(client *redis.Client) *OAuth2DPoPProofRepository {
+	return &OAuth2DPoPProofRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2DPoPProofRepository) Add(
+	ctx context.Context,
+	keyThumbprint string,
+	proofID string,
+	expiresAt time.Time,
+) (bool, error) {
+	expiration := time.Until(expiresAt)
+	if expiration <= 0 {
+		return false, nil
+	}
+
+	ok, err := repo.client.SetNX(ctx, oauth2DPoPProofKey(keyThumbprint, proofID), 1, expiration).Result()
+	return ok, database.ConvertError(err)
+}
+
+type OAuth2DPoPNonceRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2DPoPNonceRepository(client *redis.Client) *OAuth2DPoPNonceRepository {
+	return &OAuth2DPoPNonceRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2DPoPNonceRepository) Add(ctx context.Context, nonce *domain.OAuth2DPoPNonce) error {
+	expiration := time.Until(nonce.ExpiresAt)
+	if expiration <= 0 {
+		return nil
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx, oauth2DPoPNonceKey(nonce.Value), 1, expiration).Err())
+}
+
+func (repo *OAuth2DPoPNonceRepository) Contains(ctx context.Context, nonce string) (bool, error) {
+	n, err := repo.client.Exists(ctx, oauth2DPoPNonceKey(nonce)).Result()
+	if err != nil {
+		return false, database.ConvertError(err)
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xybor/todennus-backend/infras/database"
+)
+
+func oauth2RevokedTokenKey(tokenID int64) string {
+	return fmt.Sprintf("oauth2_revoked_token:%d", tokenID)
+}
+
+type OAuth2TokenDenylistRepository struct {
+	client *redis.Client
+}
+
+func NewOAuth2TokenDenylistRepository(client *redis.Client) *OAuth2TokenDenylistRepository {
+	return &OAuth2TokenDenylistRepository{
+		client: client,
+	}
+}
+
+func (repo *OAuth2TokenDenylistRepository) Add(ctx context.Context, tokenID int64, expiresAt time.Time) error {
+	expiration := time.Until(expiresAt)
+	if expiration <= 0 {
+		return nil
+	}
+
+	return database.ConvertError(repo.client.SetEx(ctx, oauth2RevokedTokenKey(tokenID), 1, expiration).Err())
+}
+
+func (repo *OAuth2TokenDenylistRepository) Contains(ctx context.Context, tokenID int64) (bool, error) {
+	n, err := repo.client.Exists(ctx, oauth2RevokedTokenKey(tokenID)).Result()
+	if err != nil {
+		return false, database.ConvertError(err)
+	}
+
+	return n > 0, nil
+}
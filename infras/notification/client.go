@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/xybor/todennus-backend/domain"
+)
+
+const clientNotificationTimeout = 10 * time.Second
+
+// HTTPClientNotifier posts the auth_req_id to the client notification
+// endpoint, authenticated by the client notification token (CIBA ping mode).
+// The endpoint is chosen by the client, so only public addresses are dialed
+// and redirects are not followed.
+type HTTPClientNotifier struct {
+	client *http.Client
+}
+
+func NewHTTPClientNotifier() *HTTPClientNotifier {
+	dialer := &net.Dialer{Timeout: clientNotificationTimeout, Control: dialPublicAddress}
+
+	return &HTTPClientNotifier{
+		client: &http.Client{
+			Timeout:   clientNotificationTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// dialPublicAddress is called after the host is resolved, so a host resolving
+// to an internal address is also rejected.
+func dialPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !domain.IsPublicIP(ip) {
+		return fmt.Errorf("refuse to connect to the non-public address %s", address)
+	}
+
+	return nil
+}
+
+func (notifier *HTTPClientNotifier) PingClient(
+	ctx context.Context,
+	client *domain.OAuth2Client,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	body, err := json.Marshal(map[string]string{"auth_req_id": req.AuthReqID})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		client.BackchannelClientNotificationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+req.ClientNotificationToken)
+
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/x/xcontext"
+)
+
+// LogUserNotifier writes the backchannel authentication requests to the log
+// instead of delivering them to the user. It is useful for development, where
+// the user finds the authorization id in the log.
+type LogUserNotifier struct{}
+
+func NewLogUserNotifier() *LogUserNotifier {
+	return &LogUserNotifier{}
+}
+
+func (notifier *LogUserNotifier) NotifyUser(
+	ctx context.Context,
+	user *domain.User,
+	client *domain.OAuth2Client,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	xcontext.Logger(ctx).Info("backchannel-authentication-requested",
+		"uid", user.ID, "cid", client.ID, "client_name", client.Name,
+		"aid", req.AuthorizationID, "scope", req.Scope.String(), "binding_message", req.BindingMessage)
+	return nil
+}
+
+// UserNotification is a backchannel authentication request delivered to the
+// user by the MemoryUserNotifier.
+type UserNotification struct {
+	AuthorizationID string
+	ClientID        snowflake.ID
+	ClientName      string
+	Scope           string
+	BindingMessage  string
+}
+
+// MemoryUserNotifier keeps the backchannel authentication requests in memory,
+// so that tests can approve them on behalf of the user.
+type MemoryUserNotifier struct {
+	mutex         sync.Mutex
+	notifications map[snowflake.ID][]UserNotification
+}
+
+func NewMemoryUserNotifier() *MemoryUserNotifier {
+	return &MemoryUserNotifier{
+		notifications: map[snowflake.ID][]UserNotification{},
+	}
+}
+
+func (notifier *MemoryUserNotifier) NotifyUser(
+	ctx context.Context,
+	user *domain.User,
+	client *domain.OAuth2Client,
+	req *domain.OAuth2BackchannelAuthentication,
+) error {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	notifier.notifications[user.ID] = append(notifier.notifications[user.ID], UserNotification{
+		AuthorizationID: req.AuthorizationID,
+		ClientID:        client.ID,
+		ClientName:      client.Name,
+		Scope:           req.Scope.String(),
+		BindingMessage:  req.BindingMessage,
+	})
+
+	return nil
+}
+
+// Pop returns and removes the notifications delivered to the user.
+func (notifier *MemoryUserNotifier) Pop(userID snowflake.ID) []UserNotification {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	notifications := notifier.notifications[userID]
+	delete(notifier.notifications, userID)
+	return notifications
+}
@@ -0,0 +1,98 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/xybor/todennus-backend/domain"
+	xtoken "github.com/xybor/x/token"
+)
+
+// DPoPProofType is the typ header of DPoP proofs (RFC 9449).
+const DPoPProofType = "dpop+jwt"
+
+// DPoPProofEngine validates DPoP proofs. Different from other tokens, a proof
+// is signed by the private key of the client and carries the public key in
+// its jwk header.
+type DPoPProofEngine struct{}
+
+func NewDPoPProofEngine() *DPoPProofEngine {
+	return &DPoPProofEngine{}
+}
+
+// Validate verifies the signature of the proof by the key in its header, then
+// returns the JWK thumbprint (RFC 7638) of the key.
+func (*DPoPProofEngine) Validate(ctx context.Context, proof string, claims xtoken.Claims) (string, error) {
+	keyThumbprint := ""
+	parsedToken, err := jwt.ParseWithClaims(proof, claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != DPoPProofType {
+			return nil, fmt.Errorf("%w: require typ %s", xtoken.ErrTokenInvalidFormat, DPoPProofType)
+		}
+
+		jwk, ok := t.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: require the jwk header", xtoken.ErrTokenInvalidFormat)
+		}
+
+		if _, ok := jwk["d"]; ok {
+			return nil, fmt.Errorf("%w: the jwk header must not contain a private key", xtoken.ErrTokenInvalidFormat)
+		}
+
+		header, err := parseJWKHeader(jwk)
+		if err != nil {
+			return nil, err
+		}
+
+		var key interface{}
+		switch t.Method {
+		case jwt.SigningMethodRS256:
+			rsaKey, err := header.RSAPublicKey()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", xtoken.ErrTokenInvalidFormat, err.Error())
+			}
+
+			key, keyThumbprint = rsaKey, domain.RSAKeyThumbprint(rsaKey)
+
+		case jwt.SigningMethodES256:
+			ecKey, err := header.ECPublicKey()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", xtoken.ErrTokenInvalidFormat, err.Error())
+			}
+
+			key, keyThumbprint = ecKey, domain.ECKeyThumbprint(ecKey)
+
+		default:
+			return nil, xtoken.ErrTokenSigningMethodNotSupport
+		}
+
+		return key, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !parsedToken.Valid {
+		return "", xtoken.ErrTokenInvalidFormat
+	}
+
+	return keyThumbprint, nil
+}
+
+// parseJWKHeader converts the jwk header, which is decoded as a generic map,
+// to the shared JWK representation.
+func parseJWKHeader(jwk map[string]interface{}) (domain.JSONWebKey, error) {
+	key := domain.JSONWebKey{}
+
+	raw, err := json.Marshal(jwk)
+	if err != nil {
+		return key, fmt.Errorf("%w: invalid jwk header: %s", xtoken.ErrTokenInvalidFormat, err.Error())
+	}
+
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return key, fmt.Errorf("%w: invalid jwk header: %s", xtoken.ErrTokenInvalidFormat, err.Error())
+	}
+
+	return key, nil
+}
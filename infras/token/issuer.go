@@ -0,0 +1,236 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+	xtoken "github.com/xybor/x/token"
+	"github.com/xybor/x/xcontext"
+)
+
+const (
+	// JWKSRefreshInterval is how long the keys fetched from the jwks uri of a
+	// trusted issuer are cached, so that rotated keys are picked up.
+	JWKSRefreshInterval = 10 * time.Minute
+
+	jwksFetchTimeout = 10 * time.Second
+	jwksMaximumSize  = 1 << 20
+)
+
+// TrustedIssuerConfig is an entry of the trusted issuers file. The keys are
+// loaded either from the jwks file once, or from the jwks uri periodically.
+type TrustedIssuerConfig struct {
+	Issuer      string   `json:"issuer"`
+	JWKSURI     string   `json:"jwks_uri"`
+	JWKSFile    string   `json:"jwks_file"`
+	UserMapping string   `json:"user_mapping"`
+	Clients     []string `json:"clients"`
+}
+
+type trustedIssuer struct {
+	issuer  *domain.OAuth2TrustedIssuer
+	jwksURI string
+
+	mutex     sync.Mutex
+	engine    *JWTEngine
+	fetchedAt time.Time
+}
+
+// TrustedIssuerEngine validates assertions signed by the trusted external
+// issuers, such as the workload identities of another cluster.
+type TrustedIssuerEngine struct {
+	issuers map[string]*trustedIssuer
+	client  *http.Client
+}
+
+func NewTrustedIssuerEngine() *TrustedIssuerEngine {
+	return &TrustedIssuerEngine{
+		issuers: map[string]*trustedIssuer{},
+		client:  &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// LoadFile adds the issuers listed in the json file. An empty path means no
+// trusted issuer.
+func (engine *TrustedIssuerEngine) LoadFile(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	configs := []TrustedIssuerConfig{}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("invalid trusted issuers file: %w", err)
+	}
+
+	for i := range configs {
+		if err := engine.Add(&configs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (engine *TrustedIssuerEngine) Add(config *TrustedIssuerConfig) error {
+	if config.Issuer == "" {
+		return errors.New("require the issuer of trusted issuer")
+	}
+
+	if _, ok := engine.issuers[config.Issuer]; ok {
+		return fmt.Errorf("duplicated trusted issuer %s", config.Issuer)
+	}
+
+	if (config.JWKSURI == "") == (config.JWKSFile == "") {
+		return fmt.Errorf("require exactly one of jwks_uri and jwks_file of trusted issuer %s", config.Issuer)
+	}
+
+	userMapping := config.UserMapping
+	if userMapping == "" {
+		userMapping = domain.UserMappingSubject
+	}
+
+	if userMapping != domain.UserMappingSubject && userMapping != domain.UserMappingUsername {
+		return fmt.Errorf("invalid user_mapping %s of trusted issuer %s", config.UserMapping, config.Issuer)
+	}
+
+	if len(config.Clients) == 0 {
+		return fmt.Errorf("require at least one client of trusted issuer %s", config.Issuer)
+	}
+
+	clientIDs := []snowflake.ID{}
+	for _, client := range config.Clients {
+		clientID, err := snowflake.ParseString(client)
+		if err != nil {
+			return fmt.Errorf("invalid client %s of trusted issuer %s", client, config.Issuer)
+		}
+
+		clientIDs = append(clientIDs, clientID)
+	}
+
+	issuer := &trustedIssuer{
+		issuer: &domain.OAuth2TrustedIssuer{
+			Issuer:      config.Issuer,
+			UserMapping: userMapping,
+			ClientIDs:   clientIDs,
+		},
+		jwksURI: config.JWKSURI,
+	}
+
+	if config.JWKSFile != "" {
+		jwks, err := os.ReadFile(config.JWKSFile)
+		if err != nil {
+			return err
+		}
+
+		issuer.engine, err = NewJWKSEngine(string(jwks))
+		if err != nil {
+			return fmt.Errorf("invalid jwks of trusted issuer %s: %w", config.Issuer, err)
+		}
+	}
+
+	engine.issuers[config.Issuer] = issuer
+	return nil
+}
+
+// Validate finds the issuer by the iss claim, then verifies the assertion by
+// the keys of the issuer. It returns the issuer of the valid assertion.
+func (engine *TrustedIssuerEngine) Validate(
+	ctx context.Context,
+	assertion string,
+	claims xtoken.Claims,
+) (*domain.OAuth2TrustedIssuer, error) {
+	unverified := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(assertion, unverified); err != nil {
+		return nil, err
+	}
+
+	iss, _ := unverified["iss"].(string)
+	issuer, ok := engine.issuers[iss]
+	if !ok {
+		return nil, fmt.Errorf("%w: untrusted issuer %s", xtoken.ErrTokenInvalidFormat, iss)
+	}
+
+	verifier, err := engine.verifier(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err = verifier.Validate(ctx, assertion, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, xtoken.ErrTokenInvalidFormat
+	}
+
+	return issuer.issuer, nil
+}
+
+// verifier returns the engine with the keys of the issuer. The keys of the
+// jwks uri are fetched on the first use and refreshed when they are stale,
+// the stale keys are still used if the issuer is unreachable.
+func (engine *TrustedIssuerEngine) verifier(ctx context.Context, issuer *trustedIssuer) (*JWTEngine, error) {
+	issuer.mutex.Lock()
+	defer issuer.mutex.Unlock()
+
+	if issuer.jwksURI == "" || time.Since(issuer.fetchedAt) < JWKSRefreshInterval {
+		return issuer.engine, nil
+	}
+
+	verifier, err := engine.fetchJWKS(ctx, issuer.jwksURI)
+	if err != nil {
+		if issuer.engine == nil {
+			return nil, fmt.Errorf("failed to fetch jwks of %s: %w", issuer.issuer.Issuer, err)
+		}
+
+		// Back off until the next refresh instead of blocking every request
+		// on the unreachable issuer.
+		xcontext.Logger(ctx).Warn("failed-to-refresh-jwks", "iss", issuer.issuer.Issuer, "err", err)
+		issuer.fetchedAt = time.Now()
+		return issuer.engine, nil
+	}
+
+	issuer.engine = verifier
+	issuer.fetchedAt = time.Now()
+	return issuer.engine, nil
+}
+
+func (engine *TrustedIssuerEngine) fetchJWKS(ctx context.Context, uri string) (*JWTEngine, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := engine.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	jwks, err := io.ReadAll(io.LimitReader(response.Body, jwksMaximumSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJWKSEngine(string(jwks))
+}
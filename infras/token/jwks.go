@@ -0,0 +1,72 @@
+package token
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/xybor/todennus-backend/domain"
+	xtoken "github.com/xybor/x/token"
+)
+
+// ParseJWKS parses the RSA signing keys of a JSON Web Key Set (RFC 7517). The
+// keys are indexed by their key ids, or by their thumbprints if the key ids
+// are absent. Keys of other types are ignored.
+func ParseJWKS(jwks string) (map[string]*rsa.PublicKey, error) {
+	set, err := domain.ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", xtoken.ErrSigningKeyInvalid, err.Error())
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.RSAPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid key %s: %s", xtoken.ErrSigningKeyInvalid, jwk.KeyID, err.Error())
+		}
+
+		kid := jwk.KeyID
+		if kid == "" {
+			kid = domain.RSAKeyThumbprint(key)
+		}
+
+		keys[kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: not found any rsa signing key", xtoken.ErrSigningKeyInvalid)
+	}
+
+	return keys, nil
+}
+
+// NewJWKSEngine creates an engine which only validates tokens signed by one
+// of the keys in the JSON Web Key Set, e.g. the keys registered by a client.
+// It cannot generate tokens.
+func NewJWKSEngine(jwks string) (*JWTEngine, error) {
+	keys, err := ParseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTEngine{verificationKeys: keys}, nil
+}
+
+// JWKSEngineFactory creates the engines validating tokens signed by clients.
+type JWKSEngineFactory struct{}
+
+func NewJWKSEngineFactory() *JWKSEngineFactory {
+	return &JWKSEngineFactory{}
+}
+
+func (*JWKSEngineFactory) New(jwks string) (xtoken.Engine, error) {
+	if jwks == "" {
+		return nil, errors.New("require non-empty jwks")
+	}
+
+	return NewJWKSEngine(jwks)
+}
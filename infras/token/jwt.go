@@ -0,0 +1,158 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/xybor/todennus-backend/domain"
+	xtoken "github.com/xybor/x/token"
+)
+
+var _ xtoken.Engine = (*JWTEngine)(nil)
+
+// JWTEngine signs tokens with RS256 (preferred) or HS256. Different from the
+// JWTEngine of xybor/x, it includes the key id in the header of RS256 tokens,
+// so that relying parties can select the verification key from the JWKS.
+type JWTEngine struct {
+	// RSA Signing method
+	rsaKeyID      string
+	rsaPrivateKey *rsa.PrivateKey
+	rsaPublicKey  *rsa.PublicKey
+
+	// HMAC Signing method
+	hmacSecret []byte
+
+	// Keys only used to validate tokens signed by others, indexed by the key
+	// ids.
+	verificationKeys map[string]*rsa.PublicKey
+}
+
+func NewJWTEngine() *JWTEngine {
+	return &JWTEngine{}
+}
+
+func (*JWTEngine) Type() string {
+	return "Bearer"
+}
+
+func (engine *JWTEngine) WithHMAC(secret string) error {
+	if secret == "" {
+		return fmt.Errorf("%w: require non-empty hmac secret", xtoken.ErrSigningKeyInvalid)
+	}
+
+	engine.hmacSecret = []byte(secret)
+	return nil
+}
+
+func (engine *JWTEngine) WithRSA(priv, pub string) error {
+	if priv == "" || pub == "" {
+		return fmt.Errorf("%w: require non-empty rsa private key and public key", xtoken.ErrSigningKeyInvalid)
+	}
+
+	var err error
+	engine.rsaPrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(priv))
+	if err != nil {
+		return err
+	}
+
+	engine.rsaPublicKey, err = jwt.ParseRSAPublicKeyFromPEM([]byte(pub))
+	if err != nil {
+		return err
+	}
+
+	engine.rsaKeyID = domain.RSAKeyThumbprint(engine.rsaPublicKey)
+	return nil
+}
+
+// Algorithm returns the algorithm used to sign tokens.
+func (engine *JWTEngine) Algorithm() string {
+	if engine.rsaPrivateKey != nil {
+		return jwt.SigningMethodRS256.Alg()
+	}
+
+	return jwt.SigningMethodHS256.Alg()
+}
+
+// PublicKeys returns the verification keys indexed by their key ids. The HMAC
+// secret is never included.
+func (engine *JWTEngine) PublicKeys() map[string]crypto.PublicKey {
+	keys := map[string]crypto.PublicKey{}
+	if engine.rsaPublicKey != nil {
+		keys[engine.rsaKeyID] = engine.rsaPublicKey
+	}
+
+	return keys
+}
+
+func (engine *JWTEngine) Generate(ctx context.Context, claims xtoken.Claims) (string, error) {
+	switch {
+	case engine.rsaPrivateKey != nil:
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = engine.rsaKeyID
+		return token.SignedString(engine.rsaPrivateKey)
+	case engine.hmacSecret != nil:
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(engine.hmacSecret)
+	default:
+		return "", errors.New("not found any signing method provided for jwt engine")
+	}
+}
+
+func (engine *JWTEngine) Validate(ctx context.Context, token string, claims xtoken.Claims) (bool, error) {
+	parsedToken, err := jwt.ParseWithClaims(token, claims, engine.publicKeyFunc)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := parsedToken.Claims.(xtoken.Claims); !ok {
+		return false, xtoken.ErrTokenInvalidFormat
+	}
+
+	return parsedToken.Valid, nil
+}
+
+func (engine *JWTEngine) publicKeyFunc(t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if engine.verificationKeys != nil {
+			return engine.verificationKey(t)
+		}
+
+		if engine.rsaPublicKey == nil {
+			return nil, xtoken.ErrTokenSigningMethodNotSupport
+		}
+
+		return engine.rsaPublicKey, nil
+	case *jwt.SigningMethodHMAC:
+		if engine.hmacSecret == nil {
+			return nil, xtoken.ErrTokenSigningMethodNotSupport
+		}
+
+		return engine.hmacSecret, nil
+	default:
+		return nil, xtoken.ErrTokenSigningMethodNotSupport
+	}
+}
+
+// verificationKey selects the key by the kid header. The kid header can be
+// omitted only if there is exactly one key.
+func (engine *JWTEngine) verificationKey(t *jwt.Token) (interface{}, error) {
+	if kid, ok := t.Header["kid"].(string); ok {
+		if key, ok := engine.verificationKeys[kid]; ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("%w: unknown key id %s", xtoken.ErrTokenInvalidFormat, kid)
+	}
+
+	if len(engine.verificationKeys) == 1 {
+		for _, key := range engine.verificationKeys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: require the kid header", xtoken.ErrTokenInvalidFormat)
+}
@@ -1,6 +1,9 @@
 package abstraction
 
 import (
+	"crypto/x509"
+	"time"
+
 	"github.com/xybor-x/snowflake"
 	"github.com/xybor/todennus-backend/domain"
 	"github.com/xybor/x/scope"
@@ -15,37 +18,125 @@ type OAuth2FlowDomain interface {
 	CreateAuthorizationCode(
 		userID, clientID snowflake.ID,
 		scope scope.Scopes,
-		codeChallenge, codeChallengeMethod string,
+		redirectURI, codeChallenge, codeChallengeMethod, nonce string,
+		authTime time.Time,
 	) *domain.OAuth2AuthorizationCode
 	CreateAuthorizationStore(
-		respType string,
+		respType, respMode string,
 		clientID snowflake.ID,
 		scope scope.Scopes,
-		redirectURI, state, codeChallenge, codeChallengeMethod string,
+		redirectURI, state, codeChallenge, codeChallengeMethod, nonce, userCode string,
+		pushed bool,
+	) *domain.OAuth2AuthorizationStore
+	PushAuthorizationStore(store *domain.OAuth2AuthorizationStore) *domain.OAuth2AuthorizationStore
+	ParseRequestURI(requestURI string) (string, error)
+	CreateDeviceCode(clientID snowflake.ID, scope scope.Scopes) *domain.OAuth2DeviceCode
+	PollDeviceCode(code *domain.OAuth2DeviceCode) bool
+	ApproveDeviceCode(code *domain.OAuth2DeviceCode, userID snowflake.ID, scope scope.Scopes, authTime time.Time)
+	DenyDeviceCode(code *domain.OAuth2DeviceCode)
+	CreateDeviceConfirmationStore(
+		code *domain.OAuth2DeviceCode,
+		userID snowflake.ID,
+		scope scope.Scopes,
 	) *domain.OAuth2AuthorizationStore
+	ValidateDeviceConfirmationStore(store *domain.OAuth2AuthorizationStore, userID snowflake.ID, userCode string) error
+	ValidateBackchannelAuthenticationRequest(
+		client *domain.OAuth2Client,
+		scope scope.Scopes,
+		bindingMessage, clientNotificationToken string,
+	) error
+	CreateBackchannelAuthentication(
+		client *domain.OAuth2Client,
+		userID snowflake.ID,
+		scope scope.Scopes,
+		bindingMessage, clientNotificationToken string,
+		requestedExpiry time.Duration,
+	) *domain.OAuth2BackchannelAuthentication
+	CreateBackchannelAuthorizationStore(req *domain.OAuth2BackchannelAuthentication) *domain.OAuth2AuthorizationStore
+	PollBackchannelAuthentication(req *domain.OAuth2BackchannelAuthentication) bool
+	ApproveBackchannelAuthentication(req *domain.OAuth2BackchannelAuthentication, scope scope.Scopes, authTime time.Time)
+	DenyBackchannelAuthentication(req *domain.OAuth2BackchannelAuthentication)
 	CreateAuthenticationResultSuccess(authID string, userID snowflake.ID, username string) *domain.OAuth2AuthenticationResult
 	CreateAuthenticationResultFailure(authID string, err string) *domain.OAuth2AuthenticationResult
 
-	CreateAccessToken(aud string, scope scope.Scopes, user *domain.User) *domain.OAuth2AccessToken
-	CreateRefreshToken(aud string, scope scope.Scopes, userID snowflake.ID) *domain.OAuth2RefreshToken
+	CreateAccessToken(aud string, scope scope.Scopes, user *domain.User, clientID snowflake.ID) *domain.OAuth2AccessToken
+	CreateClientAccessToken(aud string, scope scope.Scopes, client *domain.OAuth2Client) *domain.OAuth2AccessToken
+	CreateExchangedAccessToken(aud string, scope scope.Scopes, subjectToken, actorToken *domain.OAuth2AccessToken, client *domain.OAuth2Client) *domain.OAuth2AccessToken
+	CreateRefreshToken(aud string, scope scope.Scopes, userID, clientID snowflake.ID) *domain.OAuth2RefreshToken
 	NextRefreshToken(current *domain.OAuth2RefreshToken) *domain.OAuth2RefreshToken
-	CreateIDToken(aud string, user *domain.User) *domain.OAuth2IDToken
+	CreateRefreshTokenFamily(refreshToken *domain.OAuth2RefreshToken, accessToken *domain.OAuth2AccessToken) *domain.OAuth2RefreshTokenFamily
+	IsLatestRefreshToken(family *domain.OAuth2RefreshTokenFamily, sequenceNumber int) bool
+	AccessTokenExpiresAt(family *domain.OAuth2RefreshTokenFamily) time.Time
+	CreateIDToken(aud string, user *domain.User, authTime time.Time, nonce, accessToken, code string) *domain.OAuth2IDToken
 
+	CodeChallengeMethods() []string
+	ValidateAssertionAudience(audience []string) error
+	ValidateRequestObject(
+		client *domain.OAuth2Client,
+		issuer string,
+		clientID string,
+		audience string,
+		expiresAt time.Time,
+	) error
+	ValidateTrustedIssuer(issuer *domain.OAuth2TrustedIssuer, client *domain.OAuth2Client, issuedAt, expiresAt time.Time) error
+	BindCertificate(token *domain.OAuth2AccessToken, certificate *x509.Certificate)
+	BindKey(token *domain.OAuth2AccessToken, keyThumbprint string)
+	BindRefreshTokenKey(client *domain.OAuth2Client, token *domain.OAuth2RefreshToken, keyThumbprint string)
+	CreateDPoPNonce() *domain.OAuth2DPoPNonce
+	ValidateDPoPProof(proof *domain.OAuth2DPoPProof, method, uri, accessToken string) error
+	ValidatePKCE(client *domain.OAuth2Client, challenge, method string) (string, error)
 	ValidateCodeChallenge(verifier, challenge, method string) bool
 	ValidateRequestedScope(requestedScope scope.Scopes, client *domain.OAuth2Client) error
+	ValidateRequestedClientScope(requestedScope scope.Scopes, client *domain.OAuth2Client) error
+	ValidateRefreshScope(requestedScope scope.Scopes, refreshToken *domain.OAuth2RefreshToken) error
+	ValidateExchangeScope(requestedScope scope.Scopes, subjectToken *domain.OAuth2AccessToken, client *domain.OAuth2Client) (scope.Scopes, error)
+	ValidateActorChain(subjectToken *domain.OAuth2AccessToken) error
 
 	NewSession(userID snowflake.ID) *domain.Session
 	InvalidateSession(state domain.SessionState) *domain.Session
 }
 
 type OAuth2ClientDomain interface {
-	CreateClient(ownerID snowflake.ID, name string, isConfidential bool) (*domain.OAuth2Client, string, error)
+	CreateClient(
+		ownerID snowflake.ID,
+		name string,
+		isConfidential bool,
+		redirectURIs []string,
+	) (*domain.OAuth2Client, string, error)
+	SetName(client *domain.OAuth2Client, name string) error
+	SetOwner(client *domain.OAuth2Client, ownerID snowflake.ID)
+	SetDisabled(client *domain.OAuth2Client, disabled bool)
+	SetAllowedScope(client *domain.OAuth2Client, allowedScope scope.Scopes) error
+	SetGrantTypes(client *domain.OAuth2Client, grantTypes, responseTypes []string)
+	SetLogoURI(client *domain.OAuth2Client, logoURI string) error
+	CreateRegistrationAccessToken(client *domain.OAuth2Client) (string, error)
+	SetRedirectURIs(client *domain.OAuth2Client, redirectURIs []string) error
+	ValidateRedirectURI(client *domain.OAuth2Client, redirectURI string) error
+	SetAllowedResources(client *domain.OAuth2Client, resources []string) error
+	ValidateResource(client *domain.OAuth2Client, resource string) error
+	SetAllowImplicitFlow(client *domain.OAuth2Client, allow bool)
+	SetRequirePushedAuthorizationRequests(client *domain.OAuth2Client, require bool)
+	SetJWKS(client *domain.OAuth2Client, jwks string) error
+	SetForbidPlainCodeChallenge(client *domain.OAuth2Client, forbid bool)
+	SetTokenEndpointAuthMethod(client *domain.OAuth2Client, method string) error
+	SetTLSClientAuthSubjectDN(client *domain.OAuth2Client, subjectDN string)
+	SetBackchannelTokenDelivery(client *domain.OAuth2Client, mode, endpoint string) error
+	ValidateTokenEndpointAuthMethod(client *domain.OAuth2Client, method string) error
+	ValidateGrantType(client *domain.OAuth2Client, grantType string) error
+	ValidateResponseType(client *domain.OAuth2Client, responseType string) error
+	ValidateRegistrationAccessToken(client *domain.OAuth2Client, token string) error
 	ValidateClient(
 		client *domain.OAuth2Client,
 		clientID snowflake.ID,
 		clientSecret string,
 		confidentialRequirement domain.ConfidentialRequirementType,
 	) error
+	ValidateClientCertificate(
+		client *domain.OAuth2Client,
+		clientID snowflake.ID,
+		certificate *x509.Certificate,
+		trusted bool,
+	) error
 }
 
 type OAuth2ConsentDomain interface {
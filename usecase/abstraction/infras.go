@@ -0,0 +1,52 @@
+package abstraction
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/x/token"
+)
+
+type TokenEngine interface {
+	token.Engine
+
+	Algorithm() string
+	PublicKeys() map[string]crypto.PublicKey
+}
+
+// JWKSEngineFactory creates engines which validate tokens signed by the keys
+// in a JSON Web Key Set.
+type JWKSEngineFactory interface {
+	New(jwks string) (token.Engine, error)
+}
+
+// DPoPProofEngine validates DPoP proofs (RFC 9449), which are signed by the
+// key in their header. It returns the thumbprint of the key.
+type DPoPProofEngine interface {
+	Validate(ctx context.Context, proof string, claims token.Claims) (string, error)
+}
+
+// TrustedIssuerEngine validates assertions signed by the trusted external
+// issuers (RFC 7523). It returns the issuer of the assertion.
+type TrustedIssuerEngine interface {
+	Validate(ctx context.Context, assertion string, claims token.Claims) (*domain.OAuth2TrustedIssuer, error)
+}
+
+// BackchannelUserNotifier asks the user to approve the backchannel
+// authentication request on their authentication device, such as by a push
+// notification or an email.
+type BackchannelUserNotifier interface {
+	NotifyUser(
+		ctx context.Context,
+		user *domain.User,
+		client *domain.OAuth2Client,
+		req *domain.OAuth2BackchannelAuthentication,
+	) error
+}
+
+// BackchannelClientNotifier notifies the client in ping mode that the
+// backchannel authentication request completes.
+type BackchannelClientNotifier interface {
+	PingClient(ctx context.Context, client *domain.OAuth2Client, req *domain.OAuth2BackchannelAuthentication) error
+}
@@ -2,6 +2,7 @@ package abstraction
 
 import (
 	"context"
+	"time"
 
 	"github.com/xybor/todennus-backend/domain"
 	"github.com/xybor/x/enum"
@@ -15,14 +16,45 @@ type UserRepository interface {
 }
 
 type RefreshTokenRepository interface {
-	Create(ctx context.Context, refreshTokenID, accessTokenID int64, seq int) error
+	Create(ctx context.Context, family *domain.OAuth2RefreshTokenFamily) error
+	GetByRefreshTokenID(ctx context.Context, refreshTokenID int64) (*domain.OAuth2RefreshTokenFamily, error)
+
+	// UpdateByRefreshTokenID returns database.ErrRecordNotFound only if no
+	// family has the expected sequence number.
 	UpdateByRefreshTokenID(ctx context.Context, refreshTokenID, accessTokenId int64, expectedCurSeq int) error
+
 	DeleteByRefreshTokenID(ctx context.Context, refreshTokenID int64) error
 }
 
+type OAuth2TokenDenylistRepository interface {
+	Add(ctx context.Context, tokenID int64, expiresAt time.Time) error
+	Contains(ctx context.Context, tokenID int64) (bool, error)
+}
+
+type OAuth2ClientAssertionRepository interface {
+	// Add returns false if the assertion was already added, which means it is
+	// replayed.
+	Add(ctx context.Context, clientID int64, assertionID string, expiresAt time.Time) (bool, error)
+}
+
+type OAuth2DPoPProofRepository interface {
+	// Add returns false if the proof was already added, which means it is
+	// replayed.
+	Add(ctx context.Context, keyThumbprint string, proofID string, expiresAt time.Time) (bool, error)
+}
+
+type OAuth2DPoPNonceRepository interface {
+	Add(ctx context.Context, nonce *domain.OAuth2DPoPNonce) error
+	Contains(ctx context.Context, nonce string) (bool, error)
+}
+
 type OAuth2ClientRepository interface {
 	Create(ctx context.Context, client *domain.OAuth2Client) error
 	GetByID(ctx context.Context, clientID int64) (*domain.OAuth2Client, error)
+	Update(ctx context.Context, client *domain.OAuth2Client) error
+	List(ctx context.Context, offset, limit int) ([]*domain.OAuth2Client, error)
+	ListByOwner(ctx context.Context, ownerID int64, offset, limit int) ([]*domain.OAuth2Client, error)
+	Delete(ctx context.Context, clientID int64) error
 	Count(ctx context.Context) (int64, error)
 }
 
@@ -40,11 +72,50 @@ type OAuth2AuthorizationCodeRepository interface {
 	LoadAuthorizationStore(ctx context.Context, id string) (*domain.OAuth2AuthorizationStore, error)
 	DeleteAuthorizationStore(ctx context.Context, id string) error
 
+	SavePushedAuthorizationStore(ctx context.Context, store *domain.OAuth2AuthorizationStore) error
+	LoadPushedAuthorizationStore(ctx context.Context, id string) (*domain.OAuth2AuthorizationStore, error)
+	DeletePushedAuthorizationStore(ctx context.Context, id string) error
+
 	SaveAuthenticationResult(ctx context.Context, result *domain.OAuth2AuthenticationResult) error
 	LoadAuthenticationResult(ctx context.Context, id string) (*domain.OAuth2AuthenticationResult, error)
 	DeleteAuthenticationResult(ctx context.Context, id string) error
 }
 
+type OAuth2DeviceCodeRepository interface {
+	SaveDeviceCode(ctx context.Context, code *domain.OAuth2DeviceCode) error
+
+	// UpdateDeviceCodeStatus returns database.ErrRecordNotFound if the device
+	// code is not pending anymore.
+	UpdateDeviceCodeStatus(ctx context.Context, code *domain.OAuth2DeviceCode) error
+
+	SaveDeviceCodePoll(ctx context.Context, code *domain.OAuth2DeviceCode) error
+	LoadDeviceCode(ctx context.Context, deviceCode string) (*domain.OAuth2DeviceCode, error)
+	LoadDeviceCodeByUserCode(ctx context.Context, userCode string) (*domain.OAuth2DeviceCode, error)
+
+	// DeleteDeviceCode returns database.ErrRecordNotFound if the device code
+	// has already been deleted.
+	DeleteDeviceCode(ctx context.Context, code *domain.OAuth2DeviceCode) error
+
+	// AddUserCodeAttempt counts a lookup of user codes from the address and
+	// returns the number of lookups in the window.
+	AddUserCodeAttempt(ctx context.Context, address string, window time.Duration) (int64, error)
+}
+
+type OAuth2BackchannelAuthenticationRepository interface {
+	Save(ctx context.Context, req *domain.OAuth2BackchannelAuthentication) error
+
+	// UpdateStatus returns database.ErrRecordNotFound if the backchannel
+	// authentication is not pending anymore.
+	UpdateStatus(ctx context.Context, req *domain.OAuth2BackchannelAuthentication) error
+
+	SavePoll(ctx context.Context, req *domain.OAuth2BackchannelAuthentication) error
+	Load(ctx context.Context, authReqID string) (*domain.OAuth2BackchannelAuthentication, error)
+
+	// Delete returns database.ErrRecordNotFound if the backchannel
+	// authentication has already been deleted.
+	Delete(ctx context.Context, authReqID string) error
+}
+
 type OAuth2ConsentRepository interface {
 	SaveResult(ctx context.Context, result *domain.OAuth2ConsentResult) error
 	LoadResult(ctx context.Context, userID, clientID int64) (*domain.OAuth2ConsentResult, error)
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"context"
+	"time"
 
 	"github.com/xybor-x/snowflake"
 	"github.com/xybor/todennus-backend/domain"
@@ -9,8 +10,17 @@ import (
 )
 
 type OAuth2ClientCreateRequest struct {
-	Name           string
-	IsConfidential bool
+	Name              string
+	IsConfidential    bool
+	RedirectURIs      []string
+	AllowImplicitFlow bool
+	RequirePAR        bool
+	JWKS              string
+	ForbidPlainPKCE   bool
+	AuthMethod        string
+	TLSSubjectDN      string
+	BackchannelMode   string
+	BackchannelNotify string
 }
 
 type OAuth2ClientCreateResponse struct {
@@ -29,7 +39,8 @@ type OAuth2ClientCreateFirstRequest struct {
 	Username string
 	Password string
 
-	Name string
+	Name         string
+	RedirectURIs []string
 }
 
 type OAuth2ClientCreateByAdminResponse struct {
@@ -57,3 +68,154 @@ func NewOAuth2ClientGetResponse(ctx context.Context, client *domain.OAuth2Client
 		Client: resource.NewOAuth2Client(ctx, client),
 	}
 }
+
+type OAuth2ClientUpdateRequest struct {
+	ClientID         snowflake.ID
+	Name             string
+	AllowedScope     string
+	RedirectURIs     []string
+	AllowedResources []string
+	JWKS             string
+	TLSSubjectDN     string
+
+	BackchannelMode   string
+	BackchannelNotify string
+}
+
+type OAuth2ClientUpdateResponse struct {
+	Client *resource.OAuth2Client
+}
+
+func NewOAuth2ClientUpdateResponse(ctx context.Context, client *domain.OAuth2Client) *OAuth2ClientUpdateResponse {
+	return &OAuth2ClientUpdateResponse{
+		Client: resource.NewOAuth2Client(ctx, client),
+	}
+}
+
+type OAuth2ClientListRequest struct {
+	// All lists the clients of all users, it is only allowed for admins.
+	All     bool
+	Page    int
+	PerPage int
+}
+
+type OAuth2ClientListResponse struct {
+	Clients []*resource.OAuth2Client
+}
+
+func NewOAuth2ClientListResponse(ctx context.Context, clients []*domain.OAuth2Client) *OAuth2ClientListResponse {
+	resp := &OAuth2ClientListResponse{Clients: make([]*resource.OAuth2Client, 0, len(clients))}
+	for _, client := range clients {
+		resp.Clients = append(resp.Clients, resource.NewOAuth2Client(ctx, client))
+	}
+
+	return resp
+}
+
+type OAuth2ClientDisableRequest struct {
+	ClientID   snowflake.ID
+	IsDisabled bool
+}
+
+type OAuth2ClientDisableResponse struct {
+	Client *resource.OAuth2Client
+}
+
+func NewOAuth2ClientDisableResponse(ctx context.Context, client *domain.OAuth2Client) *OAuth2ClientDisableResponse {
+	return &OAuth2ClientDisableResponse{
+		Client: resource.NewOAuth2Client(ctx, client),
+	}
+}
+
+type OAuth2ClientDeleteRequest struct {
+	ClientID snowflake.ID
+}
+
+type OAuth2ClientDeleteResponse struct{}
+
+type OAuth2ClientTransferRequest struct {
+	ClientID snowflake.ID
+	OwnerID  snowflake.ID
+}
+
+type OAuth2ClientTransferResponse struct {
+	Client *resource.OAuth2Client
+}
+
+func NewOAuth2ClientTransferResponse(ctx context.Context, client *domain.OAuth2Client) *OAuth2ClientTransferResponse {
+	return &OAuth2ClientTransferResponse{
+		Client: resource.NewOAuth2Client(ctx, client),
+	}
+}
+
+// OAuth2ClientMetadata is the client metadata of the dynamic client
+// registration (RFC 7591).
+type OAuth2ClientMetadata struct {
+	Name          string
+	RedirectURIs  []string
+	GrantTypes    []string
+	ResponseTypes []string
+	AuthMethod    string
+	JWKS          string
+	LogoURI       string
+	Scope         string
+}
+
+func NewOAuth2ClientMetadata(client *domain.OAuth2Client) *OAuth2ClientMetadata {
+	return &OAuth2ClientMetadata{
+		Name:          client.Name,
+		RedirectURIs:  client.RedirectURIs,
+		GrantTypes:    client.GrantTypes,
+		ResponseTypes: client.ResponseTypes,
+		AuthMethod:    client.TokenEndpointAuthMethod,
+		JWKS:          client.JWKS,
+		LogoURI:       client.LogoURI,
+		Scope:         client.AllowedScope.String(),
+	}
+}
+
+type OAuth2ClientRegisterRequest struct {
+	Metadata *OAuth2ClientMetadata
+}
+
+// OAuth2ClientRegistrationResponse is the client information response. The
+// client secret is only returned when the client is registered.
+type OAuth2ClientRegistrationResponse struct {
+	ClientID                snowflake.ID
+	ClientSecret            string
+	IssuedAt                time.Time
+	RegistrationAccessToken string
+	Metadata                *OAuth2ClientMetadata
+}
+
+func NewOAuth2ClientRegistrationResponse(
+	client *domain.OAuth2Client,
+	secret string,
+	registrationAccessToken string,
+) *OAuth2ClientRegistrationResponse {
+	return &OAuth2ClientRegistrationResponse{
+		ClientID:                client.ID,
+		ClientSecret:            secret,
+		IssuedAt:                time.UnixMilli(client.ID.Time()),
+		RegistrationAccessToken: registrationAccessToken,
+		Metadata:                NewOAuth2ClientMetadata(client),
+	}
+}
+
+type OAuth2ClientReadRegistrationRequest struct {
+	ClientID                snowflake.ID
+	RegistrationAccessToken string
+}
+
+type OAuth2ClientUpdateRegistrationRequest struct {
+	ClientID                snowflake.ID
+	RegistrationAccessToken string
+	Metadata                *OAuth2ClientMetadata
+}
+
+type OAuth2ClientDeleteRegistrationRequest struct {
+	ClientID                snowflake.ID
+	RegistrationAccessToken string
+}
+
+type OAuth2ClientDeleteRegistrationResponse struct{}
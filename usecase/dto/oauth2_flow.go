@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"crypto/x509"
+	"encoding/json"
 	"time"
 
 	"github.com/xybor-x/snowflake"
@@ -11,6 +13,10 @@ import (
 )
 
 var _ (token.Claims) = (*OAuth2StandardClaims)(nil)
+var _ (token.Claims) = (*OAuth2RequestObject)(nil)
+var _ (token.Claims) = (*OAuth2ClientAssertion)(nil)
+var _ (token.Claims) = (*OAuth2DPoPProof)(nil)
+var _ (token.Claims) = (*OAuth2BearerAssertion)(nil)
 
 type OAuth2StandardClaims struct {
 	ID        string `json:"jti,omitempty"`
@@ -76,15 +82,79 @@ func (claims *OAuth2StandardClaims) Valid() error {
 	return nil
 }
 
+// OAuth2Confirmation is the cnf claim (RFC 7800), which binds the token to a
+// key held by the client.
+type OAuth2Confirmation struct {
+	CertificateThumbprint string `json:"x5t#S256,omitempty"`
+	KeyThumbprint         string `json:"jkt,omitempty"`
+}
+
+func NewOAuth2Confirmation(certificateThumbprint, keyThumbprint string) *OAuth2Confirmation {
+	if certificateThumbprint == "" && keyThumbprint == "" {
+		return nil
+	}
+
+	return &OAuth2Confirmation{CertificateThumbprint: certificateThumbprint, KeyThumbprint: keyThumbprint}
+}
+
+// OAuth2Actor is the act claim (RFC 8693), which identifies the party acting
+// on behalf of the subject.
+type OAuth2Actor struct {
+	Subject  string       `json:"sub"`
+	ClientID string       `json:"client_id,omitempty"`
+	Actor    *OAuth2Actor `json:"act,omitempty"`
+}
+
+func OAuth2ActorFromDomain(actor *domain.OAuth2Actor) *OAuth2Actor {
+	if actor == nil {
+		return nil
+	}
+
+	return &OAuth2Actor{
+		Subject:  actor.Subject.String(),
+		ClientID: actor.ClientID.String(),
+		Actor:    OAuth2ActorFromDomain(actor.Actor),
+	}
+}
+
+func (actor *OAuth2Actor) To() (*domain.OAuth2Actor, error) {
+	if actor == nil {
+		return nil, nil
+	}
+
+	sub, err := snowflake.ParseString(actor.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, err := snowflake.ParseString(actor.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	prior, err := actor.Actor.To()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OAuth2Actor{Subject: sub, ClientID: clientID, Actor: prior}, nil
+}
+
 type OAuth2AccessToken struct {
 	*OAuth2StandardClaims
-	Scope string `json:"scope"`
+	ClientID     string              `json:"client_id,omitempty"`
+	Scope        string              `json:"scope"`
+	Confirmation *OAuth2Confirmation `json:"cnf,omitempty"`
+	Actor        *OAuth2Actor        `json:"act,omitempty"`
 }
 
 func OAuth2AccessTokenFromDomain(token *domain.OAuth2AccessToken) *OAuth2AccessToken {
 	return &OAuth2AccessToken{
 		OAuth2StandardClaims: OAuth2StandardClaimsFromDomain(token.Metadata),
+		ClientID:             token.ClientID.String(),
 		Scope:                token.Scope.String(),
+		Confirmation:         NewOAuth2Confirmation(token.CertificateThumbprint, token.KeyThumbprint),
+		Actor:                OAuth2ActorFromDomain(token.Actor),
 	}
 }
 
@@ -94,23 +164,46 @@ func (token *OAuth2AccessToken) To() (*domain.OAuth2AccessToken, error) {
 		return nil, err
 	}
 
-	return &domain.OAuth2AccessToken{
+	clientID, err := snowflake.ParseString(token.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := token.Actor.To()
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken := &domain.OAuth2AccessToken{
 		Metadata: metadata,
+		ClientID: clientID,
 		Scope:    domain.ScopeEngine.ParseScopes(token.Scope),
-	}, nil
+		Actor:    actor,
+	}
+
+	if token.Confirmation != nil {
+		accessToken.CertificateThumbprint = token.Confirmation.CertificateThumbprint
+		accessToken.KeyThumbprint = token.Confirmation.KeyThumbprint
+	}
+
+	return accessToken, nil
 }
 
 type OAuth2RefreshToken struct {
 	*OAuth2StandardClaims
-	SequenceNumber int    `json:"seq"`
-	Scope          string `json:"scope"`
+	SequenceNumber int                 `json:"seq"`
+	ClientID       string              `json:"client_id,omitempty"`
+	Scope          string              `json:"scope"`
+	Confirmation   *OAuth2Confirmation `json:"cnf,omitempty"`
 }
 
 func OAuth2RefreshTokenFromDomain(token *domain.OAuth2RefreshToken) *OAuth2RefreshToken {
 	return &OAuth2RefreshToken{
 		OAuth2StandardClaims: OAuth2StandardClaimsFromDomain(token.Metadata),
 		SequenceNumber:       token.SequenceNumber,
+		ClientID:             token.ClientID.String(),
 		Scope:                token.Scope.String(),
+		Confirmation:         NewOAuth2Confirmation("", token.KeyThumbprint),
 	}
 }
 
@@ -120,16 +213,63 @@ func (token *OAuth2RefreshToken) To() (*domain.OAuth2RefreshToken, error) {
 		return nil, err
 	}
 
-	return &domain.OAuth2RefreshToken{
+	clientID, err := snowflake.ParseString(token.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := &domain.OAuth2RefreshToken{
 		Metadata:       metadata,
 		SequenceNumber: token.SequenceNumber,
+		ClientID:       clientID,
 		Scope:          domain.ScopeEngine.ParseScopes(token.Scope),
-	}, nil
+	}
+
+	if token.Confirmation != nil {
+		refreshToken.KeyThumbprint = token.Confirmation.KeyThumbprint
+	}
+
+	return refreshToken, nil
+}
+
+// OAuth2GenericToken can be parsed from both access tokens and refresh
+// tokens. Only refresh tokens have the sequence number.
+type OAuth2GenericToken struct {
+	*OAuth2StandardClaims
+	SequenceNumber *int                `json:"seq"`
+	ClientID       string              `json:"client_id,omitempty"`
+	Scope          string              `json:"scope"`
+	Confirmation   *OAuth2Confirmation `json:"cnf,omitempty"`
+	Actor          *OAuth2Actor        `json:"act,omitempty"`
+}
+
+func (token *OAuth2GenericToken) IsRefreshToken() bool {
+	return token.SequenceNumber != nil
+}
+
+// AccessToken converts the token to the domain access token, it must not be
+// called on refresh tokens.
+func (token *OAuth2GenericToken) AccessToken() (*domain.OAuth2AccessToken, error) {
+	accessToken := OAuth2AccessToken{
+		OAuth2StandardClaims: token.OAuth2StandardClaims,
+		ClientID:             token.ClientID,
+		Scope:                token.Scope,
+		Confirmation:         token.Confirmation,
+		Actor:                token.Actor,
+	}
+
+	return accessToken.To()
 }
 
 type OAuth2IDToken struct {
 	*OAuth2StandardClaims
 
+	IssuedAt        int    `json:"iat,omitempty"`
+	AuthTime        int    `json:"auth_time,omitempty"`
+	Nonce           string `json:"nonce,omitempty"`
+	AccessTokenHash string `json:"at_hash,omitempty"`
+	CodeHash        string `json:"c_hash,omitempty"`
+
 	Username    string `json:"username"`
 	Displayname string `json:"display_name"`
 }
@@ -137,6 +277,11 @@ type OAuth2IDToken struct {
 func OAuth2IDTokenFromDomain(token *domain.OAuth2IDToken) *OAuth2IDToken {
 	return &OAuth2IDToken{
 		OAuth2StandardClaims: OAuth2StandardClaimsFromDomain(token.Metadata),
+		IssuedAt:             token.Metadata.NotBefore,
+		AuthTime:             int(token.AuthTime.Unix()),
+		Nonce:                token.Nonce,
+		AccessTokenHash:      token.AccessTokenHash,
+		CodeHash:             token.CodeHash,
 		Username:             token.User.Username,
 		Displayname:          token.User.DisplayName,
 	}
@@ -155,14 +300,181 @@ func (token *OAuth2IDToken) To() (*domain.OAuth2IDToken, error) {
 			Username:    token.Username,
 			DisplayName: token.Displayname,
 		},
+		AuthTime:        time.Unix(int64(token.AuthTime), 0),
+		Nonce:           token.Nonce,
+		AccessTokenHash: token.AccessTokenHash,
+		CodeHash:        token.CodeHash,
 	}, nil
 }
 
+// OAuth2ClientAuthentication is the credentials of the client at the
+// endpoints requiring client authentication.
+type OAuth2ClientAuthentication struct {
+	ClientID     snowflake.ID
+	ClientSecret string
+
+	// AuthMethod is the token endpoint auth method which the adapter detects
+	// from the request. It is empty if the adapter carries only the secret
+	// without telling how it is sent (e.g. gRPC).
+	AuthMethod string
+
+	// Only for private_key_jwt (RFC 7523).
+	ClientAssertion string
+
+	// ClientCertificate is the certificate of the mutual-TLS connection, it
+	// is nil if the client presents no certificate.
+	ClientCertificate *OAuth2ClientCertificate
+}
+
+// OAuth2ClientCertificate is the certificate presented by the client in the
+// mutual-TLS connection (RFC 8705).
+type OAuth2ClientCertificate struct {
+	Certificate *x509.Certificate
+
+	// Trusted is true if the certificate chains to a trusted CA. It is
+	// required by tls_client_auth, but not by self_signed_tls_client_auth.
+	Trusted bool
+}
+
+func (certificate *OAuth2ClientCertificate) Thumbprint() string {
+	return domain.CertificateThumbprint(certificate.Certificate)
+}
+
+// OAuth2DPoP is the DPoP proof along with the HTTP request which it is sent
+// with (RFC 9449).
+type OAuth2DPoP struct {
+	Proof  string
+	Method string
+	URI    string
+}
+
+// OAuth2DPoPProof is the claims of a DPoP proof.
+type OAuth2DPoPProof struct {
+	ID              string `json:"jti"`
+	Method          string `json:"htm"`
+	URI             string `json:"htu"`
+	IssuedAt        int    `json:"iat"`
+	AccessTokenHash string `json:"ath,omitempty"`
+	Nonce           string `json:"nonce,omitempty"`
+}
+
+func (claims *OAuth2DPoPProof) Valid() error {
+	if claims.ID == "" || claims.Method == "" || claims.URI == "" || claims.IssuedAt == 0 {
+		return token.ErrTokenInvalidFormat
+	}
+
+	return nil
+}
+
+func (claims *OAuth2DPoPProof) To(keyThumbprint string) *domain.OAuth2DPoPProof {
+	return &domain.OAuth2DPoPProof{
+		ID:              claims.ID,
+		Method:          claims.Method,
+		URI:             claims.URI,
+		IssuedAt:        time.Unix(int64(claims.IssuedAt), 0),
+		AccessTokenHash: claims.AccessTokenHash,
+		Nonce:           claims.Nonce,
+		KeyThumbprint:   keyThumbprint,
+	}
+}
+
+// OAuth2Audience is the aud claim, which is either a string or an array of
+// strings.
+type OAuth2Audience []string
+
+func (aud *OAuth2Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = OAuth2Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*aud = multiple
+	return nil
+}
+
+// OAuth2ClientAssertion is the claims of the JWT which the client uses to
+// authenticate itself (RFC 7523).
+type OAuth2ClientAssertion struct {
+	ID        string         `json:"jti"`
+	Issuer    string         `json:"iss"`
+	Subject   string         `json:"sub"`
+	Audience  OAuth2Audience `json:"aud"`
+	ExpiresAt int            `json:"exp"`
+	NotBefore int            `json:"nbf,omitempty"`
+}
+
+func (claims *OAuth2ClientAssertion) Valid() error {
+	if claims.ExpiresAt == 0 || claims.ID == "" {
+		return token.ErrTokenInvalidFormat
+	}
+
+	now := time.Now()
+	if time.Unix(int64(claims.ExpiresAt), 0).Before(now) {
+		return token.ErrTokenExpired
+	}
+
+	if claims.NotBefore != 0 && time.Unix(int64(claims.NotBefore), 0).After(now) {
+		return token.ErrTokenNotYetValid
+	}
+
+	return nil
+}
+
+// OAuth2BearerAssertion is the claims of the JWT which a trusted issuer signs
+// for the JWT bearer grant (RFC 7523).
+type OAuth2BearerAssertion struct {
+	ID        string         `json:"jti,omitempty"`
+	Issuer    string         `json:"iss"`
+	Subject   string         `json:"sub"`
+	Audience  OAuth2Audience `json:"aud"`
+	ExpiresAt int            `json:"exp"`
+	NotBefore int            `json:"nbf,omitempty"`
+	IssuedAt  int            `json:"iat,omitempty"`
+}
+
+func (claims *OAuth2BearerAssertion) Valid() error {
+	if claims.ExpiresAt == 0 || claims.Subject == "" {
+		return token.ErrTokenInvalidFormat
+	}
+
+	now := time.Now()
+	if time.Unix(int64(claims.ExpiresAt), 0).Before(now) {
+		return token.ErrTokenExpired
+	}
+
+	if claims.NotBefore != 0 && time.Unix(int64(claims.NotBefore), 0).After(now) {
+		return token.ErrTokenNotYetValid
+	}
+
+	return nil
+}
+
+// IssuedTime returns when the assertion was issued. The assertion is treated
+// as issued now if it has neither iat nor nbf.
+func (claims *OAuth2BearerAssertion) IssuedTime() time.Time {
+	switch {
+	case claims.IssuedAt != 0:
+		return time.Unix(int64(claims.IssuedAt), 0)
+	case claims.NotBefore != 0:
+		return time.Unix(int64(claims.NotBefore), 0)
+	default:
+		return time.Now()
+	}
+}
+
 type OAuth2TokenRequest struct {
 	GrantType string
 
-	ClientID     snowflake.ID
-	ClientSecret string
+	OAuth2ClientAuthentication
+
+	// DPoP is optional, the issued tokens are bound to the key of the proof.
+	DPoP OAuth2DPoP
 
 	// Authorization Code Flow
 	Code         string
@@ -174,8 +486,28 @@ type OAuth2TokenRequest struct {
 	Password string
 	Scope    string
 
+	// RFC 8707 Resource Indicator
+	Resource string
+
 	// Refresh Token Flow
 	RefreshToken string
+
+	// Device Flow
+	DeviceCode string
+
+	// Client Initiated Backchannel Authentication
+	AuthReqID string
+
+	// JWT Bearer Grant (RFC 7523)
+	Assertion string
+
+	// Token Exchange (RFC 8693)
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Audience           string
 }
 
 type OAuth2TokenResponse struct {
@@ -184,10 +516,72 @@ type OAuth2TokenResponse struct {
 	ExpiresIn    int
 	RefreshToken string
 	Scope        string
+	IDToken      string
+
+	// IssuedTokenType is only set by the token exchange.
+	IssuedTokenType string
+}
+
+type OAuth2RevokeRequest struct {
+	OAuth2ClientAuthentication
+	Token string
+}
+
+type OAuth2RevokeResponse struct{}
+
+type OAuth2IntrospectRequest struct {
+	OAuth2ClientAuthentication
+	Token string
+}
+
+type OAuth2IntrospectResponse struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Subject   string
+	ExpiresAt int
+	IssuedAt  int
+	TokenID   string
+	TokenType string
+
+	// CertificateThumbprint is only set if the access token is bound to a
+	// client certificate.
+	CertificateThumbprint string
+
+	// KeyThumbprint is only set if the token is bound to a DPoP key.
+	KeyThumbprint string
+
+	// Actor is only set if the access token was issued by the token exchange.
+	Actor *OAuth2Actor
+}
+
+type OAuth2CreateDPoPNonceRequest struct{}
+
+type OAuth2CreateDPoPNonceResponse struct {
+	Nonce string
+}
+
+// OAuth2ValidateDPoPProofRequest is sent by resource servers to check if the
+// DPoP proof is bound to the access token.
+type OAuth2ValidateDPoPProofRequest struct {
+	DPoP          OAuth2DPoP
+	AccessToken   string
+	KeyThumbprint string
+}
+
+type OAuth2ValidateDPoPProofResponse struct{}
+
+type OAuth2IsTokenRevokedRequest struct {
+	TokenID snowflake.ID
+}
+
+type OAuth2IsTokenRevokedResponse struct {
+	Revoked bool
 }
 
 type OAuth2AuthorizeRequest struct {
 	ResponseType string
+	ResponseMode string
 	ClientID     snowflake.ID
 	RedirectURI  string
 	Scope        string
@@ -196,6 +590,85 @@ type OAuth2AuthorizeRequest struct {
 	// Only for PKCE
 	CodeChallenge       string
 	CodeChallengeMethod string
+
+	// Only for OpenID Connect
+	Nonce string
+
+	// Only for Device Flow, the authorization is requested by the device
+	// verification page rather than the client.
+	UserCode string
+
+	// Only for Pushed Authorization Requests, the other parameters are
+	// loaded from the pushed request.
+	RequestURI string
+
+	// Only for JWT-Secured Authorization Requests, the request object signed
+	// by the client. Only its claims are used, the other parameters are ignored.
+	Request string
+}
+
+func NewOAuth2AuthorizeRequestFromPushed(store *domain.OAuth2AuthorizationStore, requestURI string) *OAuth2AuthorizeRequest {
+	return &OAuth2AuthorizeRequest{
+		ResponseType:        store.ResponseType,
+		ResponseMode:        store.ResponseMode,
+		ClientID:            store.ClientID,
+		RedirectURI:         store.RedirectURI,
+		Scope:               store.Scope.String(),
+		State:               store.State,
+		CodeChallenge:       store.CodeChallenge,
+		CodeChallengeMethod: store.CodeChallengeMethod,
+		Nonce:               store.Nonce,
+		RequestURI:          requestURI,
+	}
+}
+
+// OAuth2RequestObject is the claims of the request object (RFC 9101).
+type OAuth2RequestObject struct {
+	Issuer    string `json:"iss,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	ExpiresAt int    `json:"exp,omitempty"`
+	NotBefore int    `json:"nbf,omitempty"`
+
+	ClientID            string `json:"client_id,omitempty"`
+	ResponseType        string `json:"response_type,omitempty"`
+	ResponseMode        string `json:"response_mode,omitempty"`
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+}
+
+func (obj *OAuth2RequestObject) Valid() error {
+	now := time.Now()
+	if obj.ExpiresAt != 0 && time.Unix(int64(obj.ExpiresAt), 0).Before(now) {
+		return token.ErrTokenExpired
+	}
+
+	if obj.NotBefore != 0 && time.Unix(int64(obj.NotBefore), 0).After(now) {
+		return token.ErrTokenNotYetValid
+	}
+
+	return nil
+}
+
+// To returns the authorization request built only from the claims of the
+// request object. The other parameters of the request are not signed, so they
+// are ignored (RFC 9101, section 6.3).
+func (obj *OAuth2RequestObject) To(req *OAuth2AuthorizeRequest) *OAuth2AuthorizeRequest {
+	return &OAuth2AuthorizeRequest{
+		ResponseType:        obj.ResponseType,
+		ResponseMode:        obj.ResponseMode,
+		ClientID:            req.ClientID,
+		RedirectURI:         obj.RedirectURI,
+		Scope:               obj.Scope,
+		State:               obj.State,
+		CodeChallenge:       obj.CodeChallenge,
+		CodeChallengeMethod: obj.CodeChallengeMethod,
+		Nonce:               obj.Nonce,
+		Request:             req.Request,
+	}
 }
 
 type OAuth2AuthorizeResponse struct {
@@ -213,6 +686,10 @@ type OAuth2AuthorizeResponse struct {
 	AccessToken string
 	TokenType   string
 	ExpiresIn   int
+	Scope       string
+
+	// OpenID Connect Implicit and Hybrid Flow
+	IDToken string
 }
 
 func NewOAuth2AuthorizeResponseWithCode(code string) *OAuth2AuthorizeResponse {
@@ -241,6 +718,32 @@ func NewOAuth2AuthorizeResponseWithToken(token, tokenType string, expiration tim
 	}
 }
 
+type OAuth2PushAuthorizationRequest struct {
+	OAuth2AuthorizeRequest
+	Authentication OAuth2ClientAuthentication
+}
+
+type OAuth2PushAuthorizationResponse struct {
+	RequestURI string
+	ExpiresIn  int
+}
+
+func NewOAuth2PushAuthorizationResponse(store *domain.OAuth2AuthorizationStore) *OAuth2PushAuthorizationResponse {
+	return &OAuth2PushAuthorizationResponse{
+		RequestURI: domain.RequestURIPrefix + store.ID,
+		ExpiresIn:  int(time.Until(store.ExpiresAt).Round(time.Second) / time.Second),
+	}
+}
+
+// OAuth2ResolveAuthorizationResponse is the effective authorization request
+// after loading the pushed authorization request and merging the request
+// object.
+type OAuth2ResolveAuthorizationResponse OAuth2AuthorizeRequest
+
+func NewOAuth2ResolveAuthorizationResponse(req *OAuth2AuthorizeRequest) *OAuth2ResolveAuthorizationResponse {
+	return (*OAuth2ResolveAuthorizationResponse)(req)
+}
+
 type OAuth2AuthenticationCallbackRequest struct {
 	Secret          string
 	AuthorizationID string
@@ -266,12 +769,15 @@ type OAuth2SessionUpdateResponse OAuth2AuthorizeRequest
 func NewOAuth2SessionUpdateResponse(store *domain.OAuth2AuthorizationStore) *OAuth2SessionUpdateResponse {
 	return &OAuth2SessionUpdateResponse{
 		ResponseType:        store.ResponseType,
+		ResponseMode:        store.ResponseMode,
 		ClientID:            store.ClientID,
 		RedirectURI:         store.RedirectURI,
 		Scope:               store.Scope.String(),
 		State:               store.State,
 		CodeChallenge:       store.CodeChallenge,
 		CodeChallengeMethod: store.CodeChallengeMethod,
+		Nonce:               store.Nonce,
+		UserCode:            store.UserCode,
 	}
 }
 
@@ -305,11 +811,129 @@ type OAUth2UpdateConsentResponse OAuth2AuthorizeRequest
 func NewOAUth2UpdateConsentResponse(store *domain.OAuth2AuthorizationStore) *OAUth2UpdateConsentResponse {
 	return &OAUth2UpdateConsentResponse{
 		ResponseType:        store.ResponseType,
+		ResponseMode:        store.ResponseMode,
 		ClientID:            store.ClientID,
 		RedirectURI:         store.RedirectURI,
 		Scope:               store.Scope.String(),
 		State:               store.State,
 		CodeChallenge:       store.CodeChallenge,
 		CodeChallengeMethod: store.CodeChallengeMethod,
+		Nonce:               store.Nonce,
+		UserCode:            store.UserCode,
 	}
 }
+
+type OAuth2DeviceAuthorizationRequest struct {
+	OAuth2ClientAuthentication
+	Scope string
+}
+
+type OAuth2DeviceAuthorizationResponse struct {
+	DeviceCode string
+	UserCode   string
+	ExpiresIn  int
+	Interval   int
+}
+
+func NewOAuth2DeviceAuthorizationResponse(code *domain.OAuth2DeviceCode) *OAuth2DeviceAuthorizationResponse {
+	return &OAuth2DeviceAuthorizationResponse{
+		DeviceCode: code.DeviceCode,
+		UserCode:   code.UserCode,
+		ExpiresIn:  int(time.Until(code.ExpiresAt) / time.Second),
+		Interval:   int(code.Interval / time.Second),
+	}
+}
+
+type OAuth2DeviceVerifyRequest struct {
+	UserCode string
+	ClientIP string
+}
+
+type OAuth2DeviceVerifyResponse struct {
+	// Idp
+	IdpURL          string
+	AuthorizationID string
+
+	// Consent
+	NeedConsent bool
+
+	// Confirmation, the user must confirm the user code of the client before
+	// the device is approved.
+	Confirm    bool
+	UserCode   string
+	ClientName string
+	Scope      string
+}
+
+func NewOAuth2DeviceVerifyResponseRedirect(resp *OAuth2AuthorizeResponse) *OAuth2DeviceVerifyResponse {
+	return &OAuth2DeviceVerifyResponse{
+		IdpURL:          resp.IdpURL,
+		AuthorizationID: resp.AuthorizationID,
+		NeedConsent:     resp.NeedConsent,
+	}
+}
+
+func NewOAuth2DeviceVerifyResponseConfirm(
+	store *domain.OAuth2AuthorizationStore,
+	client *domain.OAuth2Client,
+) *OAuth2DeviceVerifyResponse {
+	return &OAuth2DeviceVerifyResponse{
+		AuthorizationID: store.ID,
+		Confirm:         true,
+		UserCode:        store.UserCode,
+		ClientName:      client.Name,
+		Scope:           store.Scope.String(),
+	}
+}
+
+type OAuth2DeviceConfirmRequest struct {
+	AuthorizationID string
+	UserCode        string
+	Accept          bool
+}
+
+type OAuth2DeviceConfirmResponse struct {
+	Approved bool
+	Denied   bool
+}
+
+func NewOAuth2DeviceConfirmResponse(accept bool) *OAuth2DeviceConfirmResponse {
+	return &OAuth2DeviceConfirmResponse{Approved: accept, Denied: !accept}
+}
+
+type OAuth2BackchannelAuthorizeRequest struct {
+	OAuth2ClientAuthentication
+	Scope                   string
+	LoginHint               string
+	BindingMessage          string
+	ClientNotificationToken string
+
+	// RequestedExpiry is in seconds, zero means the default expiry.
+	RequestedExpiry int
+}
+
+type OAuth2BackchannelAuthorizeResponse struct {
+	AuthReqID string
+	ExpiresIn int
+	Interval  int
+}
+
+func NewOAuth2BackchannelAuthorizeResponse(
+	req *domain.OAuth2BackchannelAuthentication,
+) *OAuth2BackchannelAuthorizeResponse {
+	return &OAuth2BackchannelAuthorizeResponse{
+		AuthReqID: req.AuthReqID,
+		ExpiresIn: int(time.Until(req.ExpiresAt) / time.Second),
+		Interval:  int(req.Interval / time.Second),
+	}
+}
+
+type OAuth2BackchannelConsentRequest struct {
+	AuthorizationID string
+	UserScope       string
+	Accept          bool
+}
+
+type OAuth2BackchannelConsentResponse struct {
+	Approved bool
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/todennus-backend/usecase/dto/resource"
+)
+
+type OIDCGetDiscoveryRequest struct{}
+
+type OIDCGetDiscoveryResponse struct {
+	Issuer                   string
+	Scopes                   []string
+	ResponseTypes            []string
+	ResponseModes            []string
+	GrantTypes               []string
+	CodeChallengeMethods     []string
+	TokenEndpointAuthMethods []string
+	SigningAlgorithms        []string
+
+	RequestObjectSigningAlgorithms []string
+	DPoPSigningAlgorithms          []string
+	BackchannelTokenDeliveryModes  []string
+}
+
+type OIDCGetJWKSRequest struct{}
+
+type JSONWebKey struct {
+	KeyType   string
+	Use       string
+	Algorithm string
+	KeyID     string
+	N         string
+	E         string
+}
+
+type OIDCGetJWKSResponse struct {
+	Keys []JSONWebKey
+}
+
+func NewOIDCGetJWKSResponse(alg string, keys map[string]crypto.PublicKey) *OIDCGetJWKSResponse {
+	resp := &OIDCGetJWKSResponse{Keys: []JSONWebKey{}}
+	for kid, key := range keys {
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			resp.Keys = append(resp.Keys, JSONWebKey{
+				KeyType:   "RSA",
+				Use:       "sig",
+				Algorithm: alg,
+				KeyID:     kid,
+				N:         base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			})
+		}
+	}
+
+	sort.Slice(resp.Keys, func(i, j int) bool { return resp.Keys[i].KeyID < resp.Keys[j].KeyID })
+	return resp
+}
+
+type OIDCGetUserInfoRequest struct{}
+
+type OIDCGetUserInfoResponse struct {
+	Subject           snowflake.ID
+	PreferredUsername string
+	Name              string
+}
+
+func NewOIDCGetUserInfoResponse(ctx context.Context, user *domain.User) *OIDCGetUserInfoResponse {
+	resp := &OIDCGetUserInfoResponse{
+		Subject:           user.ID,
+		PreferredUsername: user.Username,
+		Name:              user.DisplayName,
+	}
+
+	resource.Filter(ctx, &resp.PreferredUsername).WhenNotContainsScope(domain.ScopeProfile)
+	resource.Filter(ctx, &resp.Name).WhenNotContainsScope(domain.ScopeProfile)
+
+	return resp
+}
@@ -36,7 +36,7 @@ func (f *Filterer[T]) WhenNot(cond bool) *Filterer[T] {
 	return f.When(!cond)
 }
 
-func (f *Filterer[T]) WhenNotContainsScope(target scope.Scope) *Filterer[T] {
+func (f *Filterer[T]) WhenNotContainsScope(target scope.Scoper) *Filterer[T] {
 	return f.WhenNot(xcontext.Scope(f.ctx).Contains(target))
 }
 
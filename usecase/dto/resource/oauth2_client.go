@@ -12,6 +12,18 @@ type OAuth2Client struct {
 	ClientID     snowflake.ID
 	Name         string
 	AllowedScope string
+	RedirectURIs []string
+
+	AllowedResources  []string
+	AllowImplicitFlow bool
+	RequirePAR        bool
+	JWKS              string
+	ForbidPlainPKCE   bool
+	AuthMethod        string
+	TLSSubjectDN      string
+	BackchannelMode   string
+	BackchannelNotify string
+	IsDisabled        bool
 }
 
 func NewOAuth2Client(ctx context.Context, client *domain.OAuth2Client) *OAuth2Client {
@@ -20,12 +32,34 @@ func NewOAuth2Client(ctx context.Context, client *domain.OAuth2Client) *OAuth2Cl
 		OwnerID:      client.OwnerUserID,
 		Name:         client.Name,
 		AllowedScope: client.AllowedScope.String(),
+		RedirectURIs: client.RedirectURIs,
+
+		AllowedResources:  client.AllowedResources,
+		AllowImplicitFlow: client.AllowImplicitFlow,
+		RequirePAR:        client.RequirePushedAuthorizationRequests,
+		JWKS:              client.JWKS,
+		ForbidPlainPKCE:   client.ForbidPlainCodeChallenge,
+		AuthMethod:        client.TokenEndpointAuthMethod,
+		TLSSubjectDN:      client.TLSClientAuthSubjectDN,
+		BackchannelMode:   client.BackchannelTokenDeliveryMode,
+		BackchannelNotify: client.BackchannelClientNotificationEndpoint,
+		IsDisabled:        client.IsDisabled,
 	}
 
 	Filter(ctx, &usecaseClient.OwnerID).WhenRequestUserNot(client.OwnerUserID)
 	Filter(ctx, &usecaseClient.AllowedScope).
 		WhenRequestUserNot(client.OwnerUserID).
 		WhenNotContainsScope(domain.ScopeEngine.New(domain.Actions.Read, domain.Resources.Client.AllowedScope))
+	Filter(ctx, &usecaseClient.RedirectURIs).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.AllowedResources).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.AllowImplicitFlow).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.RequirePAR).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.JWKS).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.ForbidPlainPKCE).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.AuthMethod).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.TLSSubjectDN).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.BackchannelMode).WhenRequestUserNot(client.OwnerUserID)
+	Filter(ctx, &usecaseClient.BackchannelNotify).WhenRequestUserNot(client.OwnerUserID)
 
 	return usecaseClient
 }
@@ -36,6 +70,18 @@ func NewOAuth2ClientWithoutFilter(client *domain.OAuth2Client) *OAuth2Client {
 		OwnerID:      client.OwnerUserID,
 		Name:         client.Name,
 		AllowedScope: client.AllowedScope.String(),
+		RedirectURIs: client.RedirectURIs,
+
+		AllowedResources:  client.AllowedResources,
+		AllowImplicitFlow: client.AllowImplicitFlow,
+		RequirePAR:        client.RequirePushedAuthorizationRequests,
+		JWKS:              client.JWKS,
+		ForbidPlainPKCE:   client.ForbidPlainCodeChallenge,
+		AuthMethod:        client.TokenEndpointAuthMethod,
+		TLSSubjectDN:      client.TLSClientAuthSubjectDN,
+		BackchannelMode:   client.BackchannelTokenDeliveryMode,
+		BackchannelNotify: client.BackchannelClientNotificationEndpoint,
+		IsDisabled:        client.IsDisabled,
 	}
 
 	return usecaseClient
@@ -11,21 +11,38 @@ var (
 	ErrServer        = xerror.Enrich(errors.New("server_error"), "an unexpected error occurred")
 	ErrServerTimeout = xerror.Enrich(errors.New("server_timeout"), "server timeout")
 
-	ErrRequestInvalid = errors.New("invalid_request")
-	ErrDuplicated     = errors.New("duplicated")
-	ErrNotFound       = errors.New("not_found")
+	ErrRequestInvalid       = errors.New("invalid_request")
+	ErrRequestURIInvalid    = errors.New("invalid_request_uri")
+	ErrRequestObjectInvalid = errors.New("invalid_request_object")
+	ErrDuplicated           = errors.New("duplicated")
+	ErrNotFound             = errors.New("not_found")
+	ErrTooManyRequests      = errors.New("too_many_requests")
 
 	ErrCredentialsInvalid = errors.New("invalid_credentials")
 
 	ErrUnauthenticated = errors.New("unauthenticated")
 	ErrForbidden       = errors.New("forbidden")
 
-	ErrClientInvalid = errors.New("invalid_client")
+	ErrClientInvalid      = errors.New("invalid_client")
+	ErrRedirectURIInvalid = errors.New("invalid_redirect_uri")
+	ErrUnauthorizedClient = errors.New("unauthorized_client")
 
-	ErrScopeInvalid = errors.New("invalid_scope")
+	ErrClientMetadataInvalid = errors.New("invalid_client_metadata")
 
-	ErrAuthorizationAccessDenied = errors.New("access_denined")
+	ErrScopeInvalid  = errors.New("invalid_scope")
+	ErrTargetInvalid = errors.New("invalid_target")
+
+	ErrAuthorizationAccessDenied = errors.New("access_denied")
 	ErrTokenInvalidGrant         = errors.New("invalid_grant")
+	ErrTokenAuthorizationPending = errors.New("authorization_pending")
+	ErrTokenSlowDown             = errors.New("slow_down")
+	ErrTokenExpired              = errors.New("expired_token")
+
+	ErrUnknownUserID         = errors.New("unknown_user_id")
+	ErrBindingMessageInvalid = errors.New("invalid_binding_message")
+
+	ErrDPoPProofInvalid = errors.New("invalid_dpop_proof")
+	ErrUseDPoPNonce     = errors.New("use_dpop_nonce")
 )
 
 var domainerr = xerror.NewWrapperConfigs(ErrServer, domain.ErrKnown)
@@ -3,16 +3,26 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 
+	"github.com/xybor-x/snowflake"
 	"github.com/xybor/todennus-backend/domain"
 	"github.com/xybor/todennus-backend/infras/database"
 	"github.com/xybor/todennus-backend/usecase/abstraction"
 	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/todennus-backend/usecase/reqctx"
 	"github.com/xybor/x/lock"
+	"github.com/xybor/x/scope"
 	"github.com/xybor/x/xcontext"
 	"github.com/xybor/x/xerror"
 )
 
+const (
+	DefaultClientPageSize = 20
+	MaximumClientPageSize = 100
+)
+
 type OAuth2ClientUsecase struct {
 	isNoClient         bool
 	firstClientLock    lock.Locker
@@ -44,21 +54,57 @@ func (usecase *OAuth2ClientUsecase) Create(
 	ctx context.Context,
 	req *dto.OAuth2ClientCreateRequest,
 ) (*dto.OAuth2ClientCreateResponse, error) {
-	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Create, domain.Resources.Client)
-	if !xcontext.Scope(ctx).Contains(requiredScope) {
-		return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+	userID, err := usecase.requireClientCreator(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID := xcontext.RequestUserID(ctx)
-	client, secret, err := usecase.oauth2ClientDomain.CreateClient(userID, req.Name, req.IsConfidential)
+	client, secret, err := usecase.oauth2ClientDomain.CreateClient(userID, req.Name, req.IsConfidential, req.RedirectURIs)
 	if err != nil {
 		return nil, domainerr.Event(err, "failed-to-new-client").Enrich(ErrRequestInvalid).Error()
 	}
 
+	if req.AllowImplicitFlow {
+		usecase.oauth2ClientDomain.SetAllowImplicitFlow(client, true)
+	}
+
+	if req.RequirePAR {
+		usecase.oauth2ClientDomain.SetRequirePushedAuthorizationRequests(client, true)
+	}
+
+	if req.ForbidPlainPKCE {
+		usecase.oauth2ClientDomain.SetForbidPlainCodeChallenge(client, true)
+	}
+
+	if req.JWKS != "" {
+		if err := usecase.oauth2ClientDomain.SetJWKS(client, req.JWKS); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-jwks").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.TLSSubjectDN != "" {
+		usecase.oauth2ClientDomain.SetTLSClientAuthSubjectDN(client, req.TLSSubjectDN)
+	}
+
+	if req.BackchannelMode != "" {
+		err := usecase.oauth2ClientDomain.SetBackchannelTokenDelivery(client, req.BackchannelMode, req.BackchannelNotify)
+		if err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-backchannel-token-delivery").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.AuthMethod != "" {
+		if err := usecase.oauth2ClientDomain.SetTokenEndpointAuthMethod(client, req.AuthMethod); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-token-endpoint-auth-method").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
 	if err = usecase.oauth2ClientRepo.Create(ctx, client); err != nil {
 		return nil, ErrServer.Hide(err, "failed-to-create-client")
 	}
 
+	xcontext.Logger(ctx).Debug("created-client", "cid", client.ID, "uid", userID, "request_cid", reqctx.RequestClientID(ctx))
+
 	return dto.NewOAuth2ClientCreateResponse(client, secret), nil
 }
 
@@ -103,7 +149,7 @@ func (usecase *OAuth2ClientUsecase) CreateByAdmin(
 		return nil, xerror.Enrich(ErrForbidden, "require admin")
 	}
 
-	client, secret, err := usecase.oauth2ClientDomain.CreateClient(user.ID, req.Name, true)
+	client, secret, err := usecase.oauth2ClientDomain.CreateClient(user.ID, req.Name, true, req.RedirectURIs)
 	if err != nil {
 		return nil, domainerr.Event(err, "failed-to-new-client").Enrich(ErrRequestInvalid).Error()
 	}
@@ -131,3 +177,510 @@ func (usecase *OAuth2ClientUsecase) Get(
 
 	return dto.NewOAuth2ClientGetResponse(ctx, client), nil
 }
+
+func (usecase *OAuth2ClientUsecase) List(
+	ctx context.Context,
+	req *dto.OAuth2ClientListRequest,
+) (*dto.OAuth2ClientListResponse, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Read, domain.Resources.Client)
+	if !xcontext.Scope(ctx).Contains(requiredScope) {
+		return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+	}
+
+	page, perPage := req.Page, req.PerPage
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = DefaultClientPageSize
+	}
+
+	if perPage > MaximumClientPageSize {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require at most %d clients per page", MaximumClientPageSize)
+	}
+
+	offset := (page - 1) * perPage
+
+	var clients []*domain.OAuth2Client
+	if req.All {
+		isAdmin, err := usecase.isAdmin(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAdmin {
+			return nil, xerror.Enrich(ErrForbidden, "only admins can list clients of all users")
+		}
+
+		clients, err = usecase.oauth2ClientRepo.List(ctx, offset, perPage)
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-list-clients")
+		}
+	} else {
+		userID := xcontext.RequestUserID(ctx)
+
+		var err error
+		clients, err = usecase.oauth2ClientRepo.ListByOwner(ctx, userID.Int64(), offset, perPage)
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-list-clients", "uid", userID)
+		}
+	}
+
+	return dto.NewOAuth2ClientListResponse(ctx, clients), nil
+}
+
+func (usecase *OAuth2ClientUsecase) Update(
+	ctx context.Context,
+	req *dto.OAuth2ClientUpdateRequest,
+) (*dto.OAuth2ClientUpdateResponse, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Update, domain.Resources.Client)
+	client, err := usecase.getManagedClient(ctx, req.ClientID, requiredScope)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		if err := usecase.oauth2ClientDomain.SetName(client, req.Name); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-name").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.AllowedScope != "" {
+		requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Update, domain.Resources.Client.AllowedScope)
+		if !xcontext.Scope(ctx).Contains(requiredScope) {
+			return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+		}
+
+		allowedScope := domain.ScopeEngine.ParseScopes(req.AllowedScope)
+		if err := usecase.oauth2ClientDomain.SetAllowedScope(client, allowedScope); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-allowed-scope").Enrich(ErrScopeInvalid).Error()
+		}
+	}
+
+	if req.RedirectURIs != nil {
+		if err := usecase.oauth2ClientDomain.SetRedirectURIs(client, req.RedirectURIs); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-redirect-uris").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.AllowedResources != nil {
+		if err := usecase.oauth2ClientDomain.SetAllowedResources(client, req.AllowedResources); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-allowed-resources").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.JWKS != "" {
+		if err := usecase.oauth2ClientDomain.SetJWKS(client, req.JWKS); err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-jwks").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if req.TLSSubjectDN != "" {
+		usecase.oauth2ClientDomain.SetTLSClientAuthSubjectDN(client, req.TLSSubjectDN)
+	}
+
+	if req.BackchannelMode != "" {
+		err := usecase.oauth2ClientDomain.SetBackchannelTokenDelivery(client, req.BackchannelMode, req.BackchannelNotify)
+		if err != nil {
+			return nil, domainerr.Event(err, "failed-to-set-backchannel-token-delivery").Enrich(ErrRequestInvalid).Error()
+		}
+	}
+
+	if err := usecase.oauth2ClientRepo.Update(ctx, client); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-update-client", "cid", client.ID)
+	}
+
+	return dto.NewOAuth2ClientUpdateResponse(ctx, client), nil
+}
+
+// Disable disables or enables the client. A disabled client can not start any
+// flow or obtain new tokens, but the access tokens already issued to it stay
+// valid until they expire. Its consents and refresh tokens are kept for when
+// it is enabled again.
+func (usecase *OAuth2ClientUsecase) Disable(
+	ctx context.Context,
+	req *dto.OAuth2ClientDisableRequest,
+) (*dto.OAuth2ClientDisableResponse, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Update, domain.Resources.Client)
+	client, err := usecase.getManagedClient(ctx, req.ClientID, requiredScope)
+	if err != nil {
+		return nil, err
+	}
+
+	usecase.oauth2ClientDomain.SetDisabled(client, req.IsDisabled)
+	if err := usecase.oauth2ClientRepo.Update(ctx, client); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-update-client", "cid", client.ID)
+	}
+
+	xcontext.Logger(ctx).Debug("set-client-disabled", "cid", client.ID, "disabled", req.IsDisabled)
+
+	return dto.NewOAuth2ClientDisableResponse(ctx, client), nil
+}
+
+// Delete removes the client along with the consents given to it and the
+// refresh tokens issued to it.
+func (usecase *OAuth2ClientUsecase) Delete(
+	ctx context.Context,
+	req *dto.OAuth2ClientDeleteRequest,
+) (*dto.OAuth2ClientDeleteResponse, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Delete, domain.Resources.Client)
+	client, err := usecase.getManagedClient(ctx, req.ClientID, requiredScope)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := usecase.oauth2ClientRepo.Delete(ctx, client.ID.Int64()); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrClientInvalid, "not found client")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-delete-client", "cid", client.ID)
+	}
+
+	xcontext.Logger(ctx).Debug("deleted-client", "cid", client.ID, "uid", xcontext.RequestUserID(ctx))
+
+	return &dto.OAuth2ClientDeleteResponse{}, nil
+}
+
+// Transfer gives the ownership of the client to another user.
+func (usecase *OAuth2ClientUsecase) Transfer(
+	ctx context.Context,
+	req *dto.OAuth2ClientTransferRequest,
+) (*dto.OAuth2ClientTransferResponse, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Update, domain.Resources.Client.Owner)
+	client, err := usecase.getManagedClient(ctx, req.ClientID, requiredScope)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := usecase.userRepo.GetByID(ctx, req.OwnerID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found new owner")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", req.OwnerID)
+	}
+
+	previousOwnerID := client.OwnerUserID
+	usecase.oauth2ClientDomain.SetOwner(client, owner.ID)
+	if err := usecase.oauth2ClientRepo.Update(ctx, client); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-update-client", "cid", client.ID)
+	}
+
+	xcontext.Logger(ctx).Debug("transferred-client", "cid", client.ID, "from", previousOwnerID, "to", owner.ID)
+
+	return dto.NewOAuth2ClientTransferResponse(ctx, client), nil
+}
+
+// Register creates a client from the client metadata (RFC 7591). The request
+// is authorized by the initial access token, which is an access token of the
+// owner with the scope to create clients.
+func (usecase *OAuth2ClientUsecase) Register(
+	ctx context.Context,
+	req *dto.OAuth2ClientRegisterRequest,
+) (*dto.OAuth2ClientRegistrationResponse, error) {
+	userID, err := usecase.requireClientCreator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authMethod := req.Metadata.AuthMethod
+	if authMethod == "" {
+		authMethod = TokenEndpointAuthMethodClientSecretBasic
+	}
+
+	isConfidential := authMethod != TokenEndpointAuthMethodNone
+	client, secret, err := usecase.oauth2ClientDomain.CreateClient(
+		userID, req.Metadata.Name, isConfidential, req.Metadata.RedirectURIs)
+	if err != nil {
+		return nil, clientMetadataError(err, "failed-to-new-client")
+	}
+
+	if err := usecase.setClientMetadata(client, req.Metadata, authMethod); err != nil {
+		return nil, err
+	}
+
+	registrationAccessToken, err := usecase.oauth2ClientDomain.CreateRegistrationAccessToken(client)
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-create-registration-access-token")
+	}
+
+	if err = usecase.oauth2ClientRepo.Create(ctx, client); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-create-client")
+	}
+
+	xcontext.Logger(ctx).Debug("registered-client", "cid", client.ID, "uid", userID)
+
+	return dto.NewOAuth2ClientRegistrationResponse(client, secret, registrationAccessToken), nil
+}
+
+func (usecase *OAuth2ClientUsecase) ReadRegistration(
+	ctx context.Context,
+	req *dto.OAuth2ClientReadRegistrationRequest,
+) (*dto.OAuth2ClientRegistrationResponse, error) {
+	client, err := usecase.authenticateRegistration(ctx, req.ClientID, req.RegistrationAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewOAuth2ClientRegistrationResponse(client, "", req.RegistrationAccessToken), nil
+}
+
+// UpdateRegistration replaces the metadata of the client (RFC 7592), the
+// omitted fields are reset to their default values. The client can not change
+// from confidential to public or vice versa.
+func (usecase *OAuth2ClientUsecase) UpdateRegistration(
+	ctx context.Context,
+	req *dto.OAuth2ClientUpdateRegistrationRequest,
+) (*dto.OAuth2ClientRegistrationResponse, error) {
+	client, err := usecase.authenticateRegistration(ctx, req.ClientID, req.RegistrationAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// The default method depends on the kind of the client, which can not be
+	// changed here.
+	authMethod := req.Metadata.AuthMethod
+	if authMethod == "" {
+		authMethod = TokenEndpointAuthMethodClientSecretBasic
+		if !client.IsConfidential {
+			authMethod = TokenEndpointAuthMethodNone
+		}
+	}
+
+	if err := usecase.oauth2ClientDomain.SetName(client, req.Metadata.Name); err != nil {
+		return nil, clientMetadataError(err, "failed-to-set-name")
+	}
+
+	if err := usecase.oauth2ClientDomain.SetRedirectURIs(client, req.Metadata.RedirectURIs); err != nil {
+		return nil, clientMetadataError(err, "failed-to-set-redirect-uris")
+	}
+
+	if err := usecase.setClientMetadata(client, req.Metadata, authMethod); err != nil {
+		return nil, err
+	}
+
+	if err := usecase.oauth2ClientRepo.Update(ctx, client); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-update-client", "cid", client.ID)
+	}
+
+	return dto.NewOAuth2ClientRegistrationResponse(client, "", req.RegistrationAccessToken), nil
+}
+
+func (usecase *OAuth2ClientUsecase) DeleteRegistration(
+	ctx context.Context,
+	req *dto.OAuth2ClientDeleteRegistrationRequest,
+) (*dto.OAuth2ClientDeleteRegistrationResponse, error) {
+	client, err := usecase.authenticateRegistration(ctx, req.ClientID, req.RegistrationAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := usecase.oauth2ClientRepo.Delete(ctx, client.ID.Int64()); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrUnauthenticated, "invalid registration access token")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-delete-client", "cid", client.ID)
+	}
+
+	xcontext.Logger(ctx).Debug("deleted-registered-client", "cid", client.ID)
+
+	return &dto.OAuth2ClientDeleteRegistrationResponse{}, nil
+}
+
+// getManagedClient loads the client which the request user manages with the
+// required scope. Only the owner and admins can manage a client.
+func (usecase *OAuth2ClientUsecase) getManagedClient(
+	ctx context.Context,
+	clientID snowflake.ID,
+	requiredScope scope.Scoper,
+) (*domain.OAuth2Client, error) {
+	if !xcontext.Scope(ctx).Contains(requiredScope) {
+		return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+	}
+
+	client, err := usecase.oauth2ClientRepo.GetByID(ctx, clientID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrClientInvalid, "not found client")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", clientID)
+	}
+
+	if client.OwnerUserID == xcontext.RequestUserID(ctx) {
+		return client, nil
+	}
+
+	isAdmin, err := usecase.isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		return nil, xerror.Enrich(ErrForbidden, "only the owner or admins can manage the client")
+	}
+
+	return client, nil
+}
+
+func (usecase *OAuth2ClientUsecase) isAdmin(ctx context.Context) (bool, error) {
+	userID := xcontext.RequestUserID(ctx)
+	user, err := usecase.userRepo.GetByID(ctx, userID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return false, nil
+		}
+
+		return false, ErrServer.Hide(err, "failed-to-get-user", "uid", userID)
+	}
+
+	return user.Role == domain.UserRoleAdmin, nil
+}
+
+// requireClientCreator returns the user who creates the client.
+func (usecase *OAuth2ClientUsecase) requireClientCreator(ctx context.Context) (snowflake.ID, error) {
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Create, domain.Resources.Client)
+	if !xcontext.Scope(ctx).Contains(requiredScope) {
+		return 0, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+	}
+
+	// Tokens of the client credentials flow represent the client itself, but
+	// a client must be owned by a user.
+	userID := xcontext.RequestUserID(ctx)
+	if userID == reqctx.RequestClientID(ctx) {
+		return 0, xerror.Enrich(ErrForbidden, "require a token issued to a user")
+	}
+
+	return userID, nil
+}
+
+// authenticateRegistration loads the client managed by the registration
+// access token. An unknown client is reported the same as an invalid token,
+// so that the existence of clients is not revealed. A disabled client can not
+// manage itself until it is enabled again.
+func (usecase *OAuth2ClientUsecase) authenticateRegistration(
+	ctx context.Context,
+	clientID snowflake.ID,
+	registrationAccessToken string,
+) (*domain.OAuth2Client, error) {
+	if registrationAccessToken == "" {
+		return nil, xerror.Enrich(ErrUnauthenticated, "require registration access token")
+	}
+
+	client, err := usecase.oauth2ClientRepo.GetByID(ctx, clientID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrUnauthenticated, "invalid registration access token")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", clientID)
+	}
+
+	err = usecase.oauth2ClientDomain.ValidateRegistrationAccessToken(client, registrationAccessToken)
+	if err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-registration-access-token").
+			EnrichWith(ErrUnauthenticated, "invalid registration access token").Error()
+	}
+
+	if client.IsDisabled {
+		return nil, xerror.Enrich(ErrClientInvalid, "client is disabled")
+	}
+
+	return client, nil
+}
+
+// setClientMetadata sets the metadata other than the name and the redirect
+// uris, which are set when the client is created.
+func (usecase *OAuth2ClientUsecase) setClientMetadata(
+	client *domain.OAuth2Client,
+	metadata *dto.OAuth2ClientMetadata,
+	authMethod string,
+) error {
+	grantTypes, responseTypes, err := resolveGrantTypes(metadata.GrantTypes, metadata.ResponseTypes)
+	if err != nil {
+		return err
+	}
+
+	if len(client.RedirectURIs) == 0 && len(responseTypes) > 0 {
+		return xerror.Enrich(ErrRedirectURIInvalid, "require redirect uris for response types %s",
+			strings.Join(responseTypes, ", "))
+	}
+
+	usecase.oauth2ClientDomain.SetGrantTypes(client, grantTypes, responseTypes)
+	usecase.oauth2ClientDomain.SetAllowImplicitFlow(client, slices.Contains(responseTypes, ResponseTypeToken))
+
+	allowedScope := domain.ScopeEngine.ParseScopes(metadata.Scope)
+	if err := usecase.oauth2ClientDomain.SetAllowedScope(client, allowedScope); err != nil {
+		return clientMetadataError(err, "failed-to-set-allowed-scope")
+	}
+
+	if err := usecase.oauth2ClientDomain.SetLogoURI(client, metadata.LogoURI); err != nil {
+		return clientMetadataError(err, "failed-to-set-logo-uri")
+	}
+
+	if err := usecase.oauth2ClientDomain.SetJWKS(client, metadata.JWKS); err != nil {
+		return clientMetadataError(err, "failed-to-set-jwks")
+	}
+
+	if err := usecase.oauth2ClientDomain.SetTokenEndpointAuthMethod(client, authMethod); err != nil {
+		return clientMetadataError(err, "failed-to-set-token-endpoint-auth-method")
+	}
+
+	return nil
+}
+
+// resolveGrantTypes fills the default grant types and response types of the
+// client metadata, then checks if they are consistent (RFC 7591, section
+// 2.1).
+func resolveGrantTypes(grantTypes, responseTypes []string) ([]string, []string, error) {
+	if len(grantTypes) == 0 {
+		grantTypes = []string{GrantTypeAuthorizationCode}
+	}
+
+	for _, grantType := range grantTypes {
+		if grantType != GrantTypeImplicit && !slices.Contains(SupportedGrantTypes, grantType) {
+			return nil, nil, xerror.Enrich(ErrClientMetadataInvalid, "not support grant type %s", grantType)
+		}
+	}
+
+	if len(responseTypes) == 0 && slices.Contains(grantTypes, GrantTypeAuthorizationCode) {
+		responseTypes = []string{ResponseTypeCode}
+	}
+
+	normalized := []string{}
+	for _, responseType := range responseTypes {
+		responseType = normalizeResponseType(responseType)
+		if !slices.Contains(SupportedResponseTypes, responseType) {
+			return nil, nil, xerror.Enrich(ErrClientMetadataInvalid, "not support response type %s", responseType)
+		}
+
+		if hasResponseType(responseType, ResponseTypeCode) && !slices.Contains(grantTypes, GrantTypeAuthorizationCode) {
+			return nil, nil, xerror.Enrich(ErrClientMetadataInvalid,
+				"response type %s requires grant type %s", responseType, GrantTypeAuthorizationCode)
+		}
+
+		if (hasResponseType(responseType, ResponseTypeToken) || hasResponseType(responseType, ResponseTypeIDToken)) &&
+			!slices.Contains(grantTypes, GrantTypeImplicit) {
+			return nil, nil, xerror.Enrich(ErrClientMetadataInvalid,
+				"response type %s requires grant type %s", responseType, GrantTypeImplicit)
+		}
+
+		normalized = append(normalized, responseType)
+	}
+
+	return grantTypes, normalized, nil
+}
+
+// clientMetadataError maps the domain error of the client metadata to the
+// errors of the dynamic client registration (RFC 7591, section 3.2.2).
+func clientMetadataError(err error, event string) error {
+	if errors.Is(err, domain.ErrRedirectURIInvalid) {
+		return domainerr.Event(err, event).Enrich(ErrRedirectURIInvalid).Error()
+	}
+
+	return domainerr.Event(err, event).Enrich(ErrClientMetadataInvalid).Error()
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveGrantTypes(t *testing.T) {
+	testcases := []struct {
+		name                string
+		grantTypes          []string
+		responseTypes       []string
+		expectGrantTypes    []string
+		expectResponseTypes []string
+		expectErr           bool
+	}{
+		{
+			name:                "default metadata",
+			expectGrantTypes:    []string{GrantTypeAuthorizationCode},
+			expectResponseTypes: []string{ResponseTypeCode},
+		},
+		{
+			name:                "default response type of authorization code",
+			grantTypes:          []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+			expectGrantTypes:    []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+			expectResponseTypes: []string{ResponseTypeCode},
+		},
+		{
+			name:                "no response type without authorization code",
+			grantTypes:          []string{GrantTypeClientCredentials},
+			expectGrantTypes:    []string{GrantTypeClientCredentials},
+			expectResponseTypes: []string{},
+		},
+		{
+			name:                "implicit",
+			grantTypes:          []string{GrantTypeImplicit},
+			responseTypes:       []string{ResponseTypeIDToken, ResponseTypeToken},
+			expectGrantTypes:    []string{GrantTypeImplicit},
+			expectResponseTypes: []string{ResponseTypeIDToken, ResponseTypeToken},
+		},
+		{
+			name:                "normalized hybrid response type",
+			grantTypes:          []string{GrantTypeAuthorizationCode, GrantTypeImplicit},
+			responseTypes:       []string{"id_token  code"},
+			expectGrantTypes:    []string{GrantTypeAuthorizationCode, GrantTypeImplicit},
+			expectResponseTypes: []string{ResponseTypeCodeIDToken},
+		},
+		{
+			name:       "unsupported grant type",
+			grantTypes: []string{"urn:example:unknown"},
+			expectErr:  true,
+		},
+		{
+			name:          "unsupported response type",
+			responseTypes: []string{"code token"},
+			grantTypes:    []string{GrantTypeAuthorizationCode, GrantTypeImplicit},
+			expectErr:     true,
+		},
+		{
+			name:          "code without authorization code grant",
+			grantTypes:    []string{GrantTypeImplicit},
+			responseTypes: []string{ResponseTypeCode},
+			expectErr:     true,
+		},
+		{
+			name:          "token without implicit grant",
+			grantTypes:    []string{GrantTypeAuthorizationCode},
+			responseTypes: []string{ResponseTypeToken},
+			expectErr:     true,
+		},
+		{
+			name:          "hybrid without implicit grant",
+			grantTypes:    []string{GrantTypeAuthorizationCode},
+			responseTypes: []string{ResponseTypeCodeIDToken},
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			grantTypes, responseTypes, err := resolveGrantTypes(tc.grantTypes, tc.responseTypes)
+			if tc.expectErr {
+				if !errors.Is(err, ErrClientMetadataInvalid) {
+					t.Fatalf("expect ErrClientMetadataInvalid, but got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(grantTypes, tc.expectGrantTypes) {
+				t.Fatalf("expect grant types %v, but got %v", tc.expectGrantTypes, grantTypes)
+			}
+
+			if !slices.Equal(responseTypes, tc.expectResponseTypes) {
+				t.Fatalf("expect response types %v, but got %v", tc.expectResponseTypes, responseTypes)
+			}
+		})
+	}
+}
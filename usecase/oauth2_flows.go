@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/xybor-x/snowflake"
@@ -14,7 +16,6 @@ import (
 	"github.com/xybor/x/token"
 	"github.com/xybor/x/xcontext"
 	"github.com/xybor/x/xerror"
-	"github.com/xybor/x/xhttp"
 )
 
 const (
@@ -22,19 +23,97 @@ const (
 	GrantTypePassword          = "password"
 	GrantTypeClientCredentials = "client_credentials"
 	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeDevice            = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	GrantTypeCIBA              = "urn:openid:params:grant-type:ciba"
+
+	// GrantTypeImplicit is only registered by clients using the implicit or
+	// hybrid flow (RFC 7591), it is never sent to the token endpoint.
+	GrantTypeImplicit = "implicit"
+)
+
+const (
+	ResponseTypeCode        = "code"
+	ResponseTypeToken       = "token"
+	ResponseTypeIDToken     = "id_token"
+	ResponseTypeCodeIDToken = "code id_token"
+)
 
-	// TODO: Support later
-	GrantTypeDevice = "urn:ietf:params:oauth:grant-type:device_code"
+const (
+	ResponseModeQuery    = "query"
+	ResponseModeFragment = "fragment"
+	ResponseModeFormPost = "form_post"
+)
+
+const (
+	TokenTypeAccessToken  = "access_token"
+	TokenTypeRefreshToken = "refresh_token"
 )
 
+// TokenTypeIdentifierAccessToken is the only token type identifier supported
+// by the token exchange (RFC 8693), for access tokens issued by this server.
+const TokenTypeIdentifierAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+// AccessTokenTypeDPoP is the type of access tokens bound to a DPoP key, other
+// access tokens have the type of the token engine.
+const AccessTokenTypeDPoP = "DPoP"
+
 const (
-	ResponseTypeCode    = "code"
-	ResponseTypeToken   = "token"
-	ResponseTypeIDToken = "id_token"
+	TokenEndpointAuthMethodNone              = domain.TokenEndpointAuthMethodNone
+	TokenEndpointAuthMethodClientSecretBasic = domain.TokenEndpointAuthMethodClientSecretBasic
+	TokenEndpointAuthMethodClientSecretPost  = domain.TokenEndpointAuthMethodClientSecretPost
+	TokenEndpointAuthMethodPrivateKeyJWT     = domain.TokenEndpointAuthMethodPrivateKeyJWT
+
+	TokenEndpointAuthMethodTLSClientAuth           = domain.TokenEndpointAuthMethodTLSClientAuth
+	TokenEndpointAuthMethodSelfSignedTLSClientAuth = domain.TokenEndpointAuthMethodSelfSignedTLSClientAuth
+)
+
+var (
+	SupportedResponseTypes = []string{
+		ResponseTypeCode,
+		ResponseTypeToken,
+		ResponseTypeIDToken,
+		ResponseTypeCodeIDToken,
+	}
+	SupportedResponseModes = []string{
+		ResponseModeQuery,
+		ResponseModeFragment,
+		ResponseModeFormPost,
+	}
+	SupportedGrantTypes = []string{
+		GrantTypeAuthorizationCode,
+		GrantTypePassword,
+		GrantTypeClientCredentials,
+		GrantTypeRefreshToken,
+		GrantTypeDevice,
+		GrantTypeTokenExchange,
+		GrantTypeJWTBearer,
+		GrantTypeCIBA,
+	}
+	SupportedTokenEndpointAuthMethods = []string{
+		TokenEndpointAuthMethodClientSecretBasic,
+		TokenEndpointAuthMethodClientSecretPost,
+		TokenEndpointAuthMethodPrivateKeyJWT,
+		TokenEndpointAuthMethodTLSClientAuth,
+		TokenEndpointAuthMethodSelfSignedTLSClientAuth,
+		TokenEndpointAuthMethodNone,
+	}
+	SupportedBackchannelTokenDeliveryModes = []string{
+		domain.BackchannelTokenDeliveryModePoll,
+		domain.BackchannelTokenDeliveryModePing,
+	}
+	SupportedRequestObjectSigningAlgorithms = []string{"RS256"}
+	SupportedDPoPSigningAlgorithms          = []string{"RS256", "ES256"}
 )
 
 type OAuth2FlowUsecase struct {
-	tokenEngine token.Engine
+	tokenEngine       token.Engine
+	jwksEngineFactory abstraction.JWKSEngineFactory
+	dpopEngine        abstraction.DPoPProofEngine
+	issuerEngine      abstraction.TrustedIssuerEngine
+	userNotifier      abstraction.BackchannelUserNotifier
+	clientNotifier    abstraction.BackchannelClientNotifier
 
 	idpLoginURL string
 	idpSecret   string
@@ -46,14 +125,25 @@ type OAuth2FlowUsecase struct {
 
 	userRepo          abstraction.UserRepository
 	refreshTokenRepo  abstraction.RefreshTokenRepository
+	denylistRepo      abstraction.OAuth2TokenDenylistRepository
 	sessionRepo       abstraction.SessionRepository
 	oauth2ClientRepo  abstraction.OAuth2ClientRepository
+	assertionRepo     abstraction.OAuth2ClientAssertionRepository
+	dpopProofRepo     abstraction.OAuth2DPoPProofRepository
+	dpopNonceRepo     abstraction.OAuth2DPoPNonceRepository
 	oauth2CodeRepo    abstraction.OAuth2AuthorizationCodeRepository
+	oauth2DeviceRepo  abstraction.OAuth2DeviceCodeRepository
+	backchannelRepo   abstraction.OAuth2BackchannelAuthenticationRepository
 	oauth2ConsentRepo abstraction.OAuth2ConsentRepository
 }
 
 func NewOAuth2Usecase(
 	tokenEngine token.Engine,
+	jwksEngineFactory abstraction.JWKSEngineFactory,
+	dpopEngine abstraction.DPoPProofEngine,
+	issuerEngine abstraction.TrustedIssuerEngine,
+	userNotifier abstraction.BackchannelUserNotifier,
+	clientNotifier abstraction.BackchannelClientNotifier,
 	idpLoginURL string,
 	idpSecret string,
 	userDomain abstraction.UserDomain,
@@ -62,13 +152,24 @@ func NewOAuth2Usecase(
 	oauth2ConsentDomain abstraction.OAuth2ConsentDomain,
 	userRepo abstraction.UserRepository,
 	refreshTokenRepo abstraction.RefreshTokenRepository,
+	denylistRepo abstraction.OAuth2TokenDenylistRepository,
 	oauth2ClientRepo abstraction.OAuth2ClientRepository,
+	assertionRepo abstraction.OAuth2ClientAssertionRepository,
+	dpopProofRepo abstraction.OAuth2DPoPProofRepository,
+	dpopNonceRepo abstraction.OAuth2DPoPNonceRepository,
 	sessionRepo abstraction.SessionRepository,
 	oauth2CodeRepo abstraction.OAuth2AuthorizationCodeRepository,
+	oauth2DeviceRepo abstraction.OAuth2DeviceCodeRepository,
+	backchannelRepo abstraction.OAuth2BackchannelAuthenticationRepository,
 	oauth2ConsentRepo abstraction.OAuth2ConsentRepository,
 ) *OAuth2FlowUsecase {
 	return &OAuth2FlowUsecase{
-		tokenEngine: tokenEngine,
+		tokenEngine:       tokenEngine,
+		jwksEngineFactory: jwksEngineFactory,
+		dpopEngine:        dpopEngine,
+		issuerEngine:      issuerEngine,
+		userNotifier:      userNotifier,
+		clientNotifier:    clientNotifier,
 
 		idpLoginURL: idpLoginURL,
 		idpSecret:   idpSecret,
@@ -80,9 +181,15 @@ func NewOAuth2Usecase(
 
 		userRepo:          userRepo,
 		refreshTokenRepo:  refreshTokenRepo,
+		denylistRepo:      denylistRepo,
 		sessionRepo:       sessionRepo,
 		oauth2ClientRepo:  oauth2ClientRepo,
+		assertionRepo:     assertionRepo,
+		dpopProofRepo:     dpopProofRepo,
+		dpopNonceRepo:     dpopNonceRepo,
 		oauth2CodeRepo:    oauth2CodeRepo,
+		oauth2DeviceRepo:  oauth2DeviceRepo,
+		backchannelRepo:   backchannelRepo,
 		oauth2ConsentRepo: oauth2ConsentRepo,
 	}
 }
@@ -91,450 +198,1856 @@ func (usecase *OAuth2FlowUsecase) Authorize(
 	ctx context.Context,
 	req *dto.OAuth2AuthorizeRequest,
 ) (*dto.OAuth2AuthorizeResponse, error) {
-	if _, err := xhttp.ParseURL(req.RedirectURI); err != nil {
-		return nil, xerror.Enrich(ErrRequestInvalid, "invalid redirect uri")
+	// The request uri is for one-time use.
+	req, client, err := usecase.resolveAuthorizeRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := usecase.oauth2ClientRepo.GetByID(ctx, req.ClientID.Int64())
+	requestedScope, err := usecase.validateAuthorizeRequest(req, client)
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrClientInvalid, "client is not found")
+		return nil, err
+	}
+
+	if client.RequirePushedAuthorizationRequests && req.RequestURI == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "the client requires pushed authorization requests")
+	}
+
+	return usecase.handleAuthorizeFlow(ctx, req, client, requestedScope)
+}
+
+func (usecase *OAuth2FlowUsecase) PushAuthorization(
+	ctx context.Context,
+	req *dto.OAuth2PushAuthorizationRequest,
+) (*dto.OAuth2PushAuthorizationResponse, error) {
+	client, err := usecase.getClient(ctx, req.Authentication.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = usecase.authenticateClient(ctx, client, &req.Authentication, domain.DependOnClientConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	authReq := &req.OAuth2AuthorizeRequest
+	authReq.ClientID = client.ID
+	if authReq.Request != "" {
+		authReq, err = usecase.parseRequestObject(ctx, authReq, client)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", req.ClientID)
+	requestedScope, err := usecase.validateAuthorizeRequest(authReq, client)
+	if err != nil {
+		return nil, err
 	}
 
-	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
-	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
-		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	store := usecase.oauth2FlowDomain.PushAuthorizationStore(usecase.oauth2FlowDomain.CreateAuthorizationStore(
+		authReq.ResponseType, authReq.ResponseMode, authReq.ClientID, requestedScope, authReq.RedirectURI,
+		authReq.State, authReq.CodeChallenge, authReq.CodeChallengeMethod, authReq.Nonce, "", true,
+	))
+
+	if err := usecase.oauth2CodeRepo.SavePushedAuthorizationStore(ctx, store); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-pushed-authorization-store")
 	}
 
-	switch req.ResponseType {
-	case ResponseTypeCode:
-		return usecase.handleAuthorizeCodeFlow(ctx, req, requestedScope)
-	default:
-		return nil, xerror.Enrich(ErrRequestInvalid, "not support response type %s", req.ResponseType)
+	return dto.NewOAuth2PushAuthorizationResponse(store), nil
+}
+
+// ResolveAuthorization returns the effective parameters of an authorization
+// request without consuming the pushed authorization request, so that the
+// adapter knows where to send the response of the authorization endpoint.
+func (usecase *OAuth2FlowUsecase) ResolveAuthorization(
+	ctx context.Context,
+	req *dto.OAuth2AuthorizeRequest,
+) (*dto.OAuth2ResolveAuthorizationResponse, error) {
+	resolved, _, err := usecase.resolveAuthorizeRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
 	}
+
+	return dto.NewOAuth2ResolveAuthorizationResponse(resolved), nil
 }
 
 func (usecase *OAuth2FlowUsecase) Token(
 	ctx context.Context,
 	req *dto.OAuth2TokenRequest,
 ) (*dto.OAuth2TokenResponse, error) {
-	client, err := usecase.oauth2ClientRepo.GetByID(ctx, req.ClientID.Int64())
+	client, err := usecase.getClient(ctx, req.ClientID)
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrClientInvalid, "client is not found")
-		}
+		return nil, err
+	}
+
+	// The proof is checked before the grant is consumed, so that the client
+	// can retry with the nonce it is challenged with.
+	proof, err := usecase.validateDPoPProof(ctx, &req.DPoP, "", true)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", req.ClientID)
+	if err := usecase.oauth2ClientDomain.ValidateGrantType(client, req.GrantType); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-grant-type").Enrich(ErrUnauthorizedClient).Error()
 	}
 
 	switch req.GrantType {
 	case GrantTypeAuthorizationCode:
-		return usecase.handleTokenCodeFlow(ctx, req, client)
+		return usecase.handleTokenCodeFlow(ctx, req, client, proof)
 	case GrantTypePassword:
-		return usecase.handleTokenPasswordFlow(ctx, req, client)
+		return usecase.handleTokenPasswordFlow(ctx, req, client, proof)
+	case GrantTypeClientCredentials:
+		return usecase.handleTokenClientCredentialsFlow(ctx, req, client, proof)
 	case GrantTypeRefreshToken:
-		return usecase.handleTokenRefreshTokenFlow(ctx, req, client)
+		return usecase.handleTokenRefreshTokenFlow(ctx, req, client, proof)
+	case GrantTypeDevice:
+		return usecase.handleTokenDeviceFlow(ctx, req, client, proof)
+	case GrantTypeTokenExchange:
+		return usecase.handleTokenExchangeFlow(ctx, req, client, proof)
+	case GrantTypeJWTBearer:
+		return usecase.handleTokenJWTBearerFlow(ctx, req, client, proof)
+	case GrantTypeCIBA:
+		return usecase.handleTokenBackchannelFlow(ctx, req, client, proof)
 	default:
 		return nil, xerror.Enrich(ErrRequestInvalid, "not support grant type %s", req.GrantType)
 	}
 }
 
-func (usecase *OAuth2FlowUsecase) AuthenticationCallback(
+func (usecase *OAuth2FlowUsecase) Revoke(
 	ctx context.Context,
-	req *dto.OAuth2AuthenticationCallbackRequest,
-) (*dto.OAuth2AuthenticationCallbackResponse, error) {
-	if req.Secret != usecase.idpSecret {
-		return nil, xerror.Enrich(ErrUnauthenticated, "incorrect idp secret")
+	req *dto.OAuth2RevokeRequest,
+) (*dto.OAuth2RevokeResponse, error) {
+	client, err := usecase.getClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
 	}
 
-	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	err = usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.DependOnClientConfidential)
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id")
-		}
+		return nil, err
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	if req.Token == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require token")
 	}
 
-	if !store.IsOpen {
-		return nil, xerror.Enrich(ErrRequestInvalid, "callback api closed for this authorization id")
+	// RFC 7009: invalid tokens do not cause an error response, the client
+	// cannot do anything with such tokens anyway.
+	token := dto.OAuth2GenericToken{}
+	ok, err := usecase.tokenEngine.Validate(ctx, req.Token, &token)
+	if err != nil || !ok {
+		xcontext.Logger(ctx).Debug("revoke-invalid-token", "err", err)
+		return &dto.OAuth2RevokeResponse{}, nil
 	}
 
-	store.IsOpen = false
-	if err := usecase.oauth2CodeRepo.SaveAuthorizationStore(ctx, store); err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-update-authorization-store")
+	metadata, err := token.OAuth2StandardClaims.To()
+	if err != nil {
+		xcontext.Logger(ctx).Debug("revoke-invalid-token", "err", err)
+		return &dto.OAuth2RevokeResponse{}, nil
 	}
 
-	var authResult *domain.OAuth2AuthenticationResult
-	if req.Success {
-		if _, err := usecase.userRepo.GetByID(ctx, req.UserID.Int64()); err != nil {
-			if errors.Is(err, database.ErrRecordNotFound) {
-				return nil, xerror.Enrich(ErrNotFound, "not found user with id %d", req.UserID)
-			}
+	// RFC 7009, section 2.1: the client can only revoke the tokens issued to
+	// it, the other tokens are ignored as invalid ones.
+	if token.ClientID != client.ID.String() {
+		xcontext.Logger(ctx).Debug("revoke-token-of-another-client", "cid", token.ClientID)
+		return &dto.OAuth2RevokeResponse{}, nil
+	}
 
-			return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", req.UserID)
+	if token.IsRefreshToken() {
+		if _, err := usecase.revokeRefreshTokenFamily(ctx, metadata.ID); err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-revoke-refresh-token-family", "jti", metadata.ID)
 		}
-
-		authResult = usecase.oauth2FlowDomain.CreateAuthenticationResultSuccess(
-			req.AuthorizationID, req.UserID, req.Username)
 	} else {
-		authResult = usecase.oauth2FlowDomain.CreateAuthenticationResultFailure(
-			req.AuthorizationID, req.Error)
-	}
-
-	if err := usecase.oauth2CodeRepo.SaveAuthenticationResult(ctx, authResult); err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-save-auth-result")
+		err = usecase.denylistRepo.Add(ctx, metadata.ID.Int64(), time.Unix(int64(metadata.ExpiresAt), 0))
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-deny-access-token", "jti", metadata.ID)
+		}
 	}
 
-	xcontext.Logger(ctx).Debug("saved-auth-result", "result", authResult.Ok, "uid", authResult.UserID)
-	return &dto.OAuth2AuthenticationCallbackResponse{AuthenticationID: authResult.ID}, nil
+	return &dto.OAuth2RevokeResponse{}, nil
 }
 
-func (usecase *OAuth2FlowUsecase) SessionUpdate(
+func (usecase *OAuth2FlowUsecase) Introspect(
 	ctx context.Context,
-	req *dto.OAuth2SessionUpdateRequest,
-) (*dto.OAuth2SessionUpdateResponse, error) {
-	authResult, err := usecase.oauth2CodeRepo.LoadAuthenticationResult(ctx, req.AuthenticationID)
+	req *dto.OAuth2IntrospectRequest,
+) (*dto.OAuth2IntrospectResponse, error) {
+	client, err := usecase.getClient(ctx, req.ClientID)
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrRequestInvalid, "invalid authentication id")
-		}
+		return nil, err
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-load-auth-result", "aid", req.AuthenticationID)
+	err = usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := usecase.oauth2CodeRepo.DeleteAuthenticationResult(ctx, req.AuthenticationID); err != nil {
-		xcontext.Logger(ctx).Warn("failed-to-delete-auth-result", "err", err, "aid", req.AuthenticationID)
+	if req.Token == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require token")
 	}
 
-	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, authResult.AuthorizationID)
+	inactive := &dto.OAuth2IntrospectResponse{Active: false}
+
+	token := dto.OAuth2GenericToken{}
+	ok, err := usecase.tokenEngine.Validate(ctx, req.Token, &token)
+	if err != nil || !ok {
+		xcontext.Logger(ctx).Debug("introspect-invalid-token", "err", err)
+		return inactive, nil
+	}
+
+	metadata, err := token.OAuth2StandardClaims.To()
 	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", authResult.AuthorizationID)
+		xcontext.Logger(ctx).Debug("introspect-invalid-token", "err", err)
+		return inactive, nil
 	}
 
-	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, authResult.AuthorizationID); err != nil {
-		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-store", "aid", authResult.AuthorizationID)
+	// Only access tokens and refresh tokens are issued to a client, the other
+	// tokens signed by the server (e.g. id tokens) are never active.
+	if token.ClientID == "" {
+		return inactive, nil
 	}
 
-	var session *domain.Session
-	if authResult.Ok {
-		session = usecase.oauth2FlowDomain.NewSession(authResult.UserID)
-	} else {
-		session = usecase.oauth2FlowDomain.InvalidateSession(domain.SessionStateFailedAuthentication)
+	certificateThumbprint, keyThumbprint := "", ""
+	if token.Confirmation != nil {
+		certificateThumbprint = token.Confirmation.CertificateThumbprint
+		keyThumbprint = token.Confirmation.KeyThumbprint
 	}
 
-	xcontext.Logger(ctx).Debug("save-session", "state", session.State)
-	if err = usecase.sessionRepo.Save(ctx, session); err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-save-session", "aid", authResult.AuthorizationID)
+	tokenType := TokenTypeAccessToken
+	if token.IsRefreshToken() {
+		tokenType = TokenTypeRefreshToken
+
+		// A refresh token is only active if it is the latest one of its
+		// family, rotated tokens have a smaller sequence number and revoked
+		// families have no record.
+		family, err := usecase.refreshTokenRepo.GetByRefreshTokenID(ctx, metadata.ID.Int64())
+		if err != nil {
+			if errors.Is(err, database.ErrRecordNotFound) {
+				return inactive, nil
+			}
+
+			return nil, ErrServer.Hide(err, "failed-to-get-refresh-token", "jti", metadata.ID)
+		}
+
+		if !usecase.oauth2FlowDomain.IsLatestRefreshToken(family, *token.SequenceNumber) {
+			return inactive, nil
+		}
+	} else {
+		revoked, err := usecase.denylistRepo.Contains(ctx, metadata.ID.Int64())
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-check-denylist", "jti", metadata.ID)
+		}
+
+		if revoked {
+			return inactive, nil
+		}
 	}
 
-	return dto.NewOAuth2SessionUpdateResponse(store), nil
+	return &dto.OAuth2IntrospectResponse{
+		Active:    true,
+		Scope:     token.Scope,
+		ClientID:  token.ClientID,
+		Subject:   metadata.Subject.String(),
+		ExpiresAt: metadata.ExpiresAt,
+		IssuedAt:  int(time.UnixMilli(metadata.ID.Time()).Unix()),
+		TokenID:   metadata.ID.String(),
+		TokenType: tokenType,
+
+		CertificateThumbprint: certificateThumbprint,
+		KeyThumbprint:         keyThumbprint,
+		Actor:                 token.Actor,
+	}, nil
 }
 
-func (usecase *OAuth2FlowUsecase) GetConsent(
+func (usecase *OAuth2FlowUsecase) CreateDPoPNonce(
 	ctx context.Context,
-	req *dto.OAuth2GetConsentRequest,
-) (*dto.OAuth2GetConsentResponse, error) {
-	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
-	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id")
-		}
+	req *dto.OAuth2CreateDPoPNonceRequest,
+) (*dto.OAuth2CreateDPoPNonceResponse, error) {
+	nonce := usecase.oauth2FlowDomain.CreateDPoPNonce()
+	if err := usecase.dpopNonceRepo.Add(ctx, nonce); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-dpop-nonce")
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	return &dto.OAuth2CreateDPoPNonceResponse{Nonce: nonce.Value}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) ValidateDPoPProof(
+	ctx context.Context,
+	req *dto.OAuth2ValidateDPoPProofRequest,
+) (*dto.OAuth2ValidateDPoPProofResponse, error) {
+	if req.DPoP.Proof == "" {
+		return nil, xerror.Enrich(ErrDPoPProofInvalid, "require a dpop proof")
 	}
 
-	client, err := usecase.oauth2ClientRepo.GetByID(ctx, store.ClientID.Int64())
+	proof, err := usecase.validateDPoPProof(ctx, &req.DPoP, req.AccessToken, false)
 	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-load-client", "cid", store.ClientID)
+		return nil, err
 	}
 
-	return dto.NewOAuth2GetConsentResponse(client, store.Scope), nil
+	if proof.KeyThumbprint != req.KeyThumbprint {
+		return nil, xerror.Enrich(ErrDPoPProofInvalid, "the access token is bound to another key")
+	}
+
+	return &dto.OAuth2ValidateDPoPProofResponse{}, nil
 }
 
-func (usecase *OAuth2FlowUsecase) UpdateConsent(
+func (usecase *OAuth2FlowUsecase) IsTokenRevoked(
 	ctx context.Context,
-	req *dto.OAuth2UpdateConsentRequest,
-) (*dto.OAUth2UpdateConsentResponse, error) {
-	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	req *dto.OAuth2IsTokenRevokedRequest,
+) (*dto.OAuth2IsTokenRevokedResponse, error) {
+	revoked, err := usecase.denylistRepo.Contains(ctx, req.TokenID.Int64())
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id %s", req.AuthorizationID)
-		}
-
-		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+		return nil, ErrServer.Hide(err, "failed-to-check-denylist", "jti", req.TokenID)
 	}
 
-	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, req.AuthorizationID); err != nil {
-		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-store", "aid", req.AuthorizationID)
-	}
+	return &dto.OAuth2IsTokenRevokedResponse{Revoked: revoked}, nil
+}
 
-	userID, err := usecase.getAuthenticatedUser(ctx)
+func (usecase *OAuth2FlowUsecase) DeviceAuthorization(
+	ctx context.Context,
+	req *dto.OAuth2DeviceAuthorizationRequest,
+) (*dto.OAuth2DeviceAuthorizationResponse, error) {
+	client, err := usecase.getClient(ctx, req.ClientID)
 	if err != nil {
 		return nil, err
 	}
 
-	var result *domain.OAuth2ConsentResult
+	err = usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.DependOnClientConfidential)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Accept {
-		userScope := domain.ScopeEngine.ParseScopes(req.UserScope)
-		result = usecase.oauth2ConsentDomain.CreateConsentAcceptedResult(userID, store.ClientID, userScope)
+	if err := usecase.oauth2ClientDomain.ValidateGrantType(client, GrantTypeDevice); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-grant-type").Enrich(ErrUnauthorizedClient).Error()
+	}
 
-		consent := usecase.oauth2ConsentDomain.CreateConsent(userID, store.ClientID, userScope)
-		if err := usecase.oauth2ConsentRepo.Upsert(ctx, consent); err != nil {
-			return nil, ErrServer.Hide(err, "failed-to-create-or-update-consent")
-		}
-	} else {
-		result = usecase.oauth2ConsentDomain.CreateConsentDeniedResult(userID, store.ClientID)
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
 	}
 
-	if err := usecase.oauth2ConsentRepo.SaveResult(ctx, result); err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-save-consent-result")
+	code := usecase.oauth2FlowDomain.CreateDeviceCode(client.ID, requestedScope)
+	if err := usecase.oauth2DeviceRepo.SaveDeviceCode(ctx, code); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-device-code")
 	}
 
-	return dto.NewOAUth2UpdateConsentResponse(store), nil
+	return dto.NewOAuth2DeviceAuthorizationResponse(code), nil
 }
 
-func (usecase *OAuth2FlowUsecase) handleAuthorizeCodeFlow(
+func (usecase *OAuth2FlowUsecase) DeviceVerify(
 	ctx context.Context,
-	req *dto.OAuth2AuthorizeRequest,
-	requestedScope scope.Scopes,
-) (*dto.OAuth2AuthorizeResponse, error) {
-	userID, err := usecase.getAuthenticatedUser(ctx)
+	req *dto.OAuth2DeviceVerifyRequest,
+) (*dto.OAuth2DeviceVerifyResponse, error) {
+	attempts, err := usecase.oauth2DeviceRepo.AddUserCodeAttempt(ctx, req.ClientIP, domain.UserCodeAttemptWindow)
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-add-user-code-attempt", "ip", req.ClientIP)
+	}
+
+	if attempts > domain.MaximumUserCodeAttempts {
+		return nil, xerror.Enrich(ErrTooManyRequests, "too many user codes were entered, try again later")
+	}
+
+	code, err := usecase.loadPendingDeviceCode(ctx, req.UserCode)
 	if err != nil {
 		return nil, err
 	}
 
-	if userID == 0 {
-		store, err := usecase.storeAuthorization(ctx, req, requestedScope)
+	session, err := usecase.getAuthenticatedSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The device verification reuses the authorization flow, the user will be
+	// redirected to the device verification page after the authentication and
+	// the consent.
+	authReq := &dto.OAuth2AuthorizeRequest{
+		ClientID: code.ClientID,
+		Scope:    code.Scope.String(),
+		UserCode: code.UserCode,
+	}
+
+	if session == nil {
+		store, err := usecase.storeAuthorization(ctx, authReq, code.Scope)
 		if err != nil {
 			return nil, err
 		}
 
-		return dto.NewOAuth2AuthorizeResponseRedirectToIdP(usecase.idpLoginURL, store.ID), nil
+		return dto.NewOAuth2DeviceVerifyResponseRedirect(
+			dto.NewOAuth2AuthorizeResponseRedirectToIdP(usecase.idpLoginURL, store.ID)), nil
 	}
 
-	resp, consentScope, err := usecase.validateConsentResult(ctx, userID.Int64(), req, requestedScope)
-	if err != nil || resp != nil {
-		return resp, err
+	resp, consentScope, err := usecase.validateConsentResult(ctx, session.UserID.Int64(), authReq, code.Scope)
+	if err != nil {
+		if errors.Is(err, ErrAuthorizationAccessDenied) {
+			usecase.oauth2FlowDomain.DenyDeviceCode(code)
+			if err := usecase.oauth2DeviceRepo.UpdateDeviceCodeStatus(ctx, code); err != nil {
+				xcontext.Logger(ctx).Warn("failed-to-save-denied-device-code", "err", err)
+			}
+		}
+
+		return nil, err
 	}
 
-	code := usecase.oauth2FlowDomain.CreateAuthorizationCode(
-		userID, req.ClientID, consentScope,
-		req.CodeChallenge, req.CodeChallengeMethod,
-	)
-	if err = usecase.oauth2CodeRepo.SaveAuthorizationCode(ctx, code); err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-save-authorization-code")
+	if resp != nil {
+		return dto.NewOAuth2DeviceVerifyResponseRedirect(resp), nil
+	}
+
+	// The consent alone does not approve the device, the user must confirm
+	// that the user code is displayed on their device. Otherwise, a phishing
+	// link of verification_uri_complete approves the device of the attacker
+	// silently (RFC 8628, section 5.4).
+	client, err := usecase.getClient(ctx, code.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	store := usecase.oauth2FlowDomain.CreateDeviceConfirmationStore(code, session.UserID, consentScope)
+	if err := usecase.oauth2CodeRepo.SaveAuthorizationStore(ctx, store); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-device-confirmation-store")
 	}
 
-	return dto.NewOAuth2AuthorizeResponseWithCode(code.Code), nil
+	return dto.NewOAuth2DeviceVerifyResponseConfirm(store, client), nil
 }
 
-func (usecase *OAuth2FlowUsecase) handleTokenCodeFlow(
+// DeviceConfirm approves or denies the device after the user has confirmed
+// the user code on the device verification page.
+func (usecase *OAuth2FlowUsecase) DeviceConfirm(
 	ctx context.Context,
-	req *dto.OAuth2TokenRequest,
-	client *domain.OAuth2Client,
-) (*dto.OAuth2TokenResponse, error) {
-	code, err := usecase.oauth2CodeRepo.LoadAuthorizationCode(ctx, req.Code)
+	req *dto.OAuth2DeviceConfirmRequest,
+) (*dto.OAuth2DeviceConfirmResponse, error) {
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
 	if err != nil {
 		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrTokenInvalidGrant, "invalid code")
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id %s", req.AuthorizationID)
 		}
 
-		return nil, ErrServer.Hide(err, "failed-to-load-code", "code", req.Code)
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
 	}
 
-	if err := usecase.oauth2CodeRepo.DeleteAuthorizationCode(ctx, req.Code); err != nil {
-		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-code", "err", err)
+	session, err := usecase.getAuthenticatedSession(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if code.CodeChallenge == "" {
-		err := usecase.oauth2ClientDomain.ValidateClient(
-			client, req.ClientID, req.ClientSecret, domain.RequireConfidential)
-		if err != nil {
-			return nil, xerror.Enrich(ErrClientInvalid, "failed due to invalid client credentials").
-				Hide(err, "validate-client-failed")
-		}
-	} else {
-		if !usecase.oauth2FlowDomain.ValidateCodeChallenge(req.CodeVerifier, code.CodeChallenge, code.CodeChallengeMethod) {
-			return nil, xerror.Enrich(ErrTokenInvalidGrant, "incorrect code verifier")
-		}
+	if session == nil {
+		return nil, xerror.Enrich(ErrAuthorizationAccessDenied, "the user is not authenticated")
 	}
 
-	user, err := usecase.userRepo.GetByID(ctx, code.UserID.Int64())
+	err = usecase.oauth2FlowDomain.ValidateDeviceConfirmationStore(store, session.UserID, req.UserCode)
 	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", code.UserID)
+		return nil, domainerr.Event(err, "failed-to-validate-device-confirmation").Enrich(ErrRequestInvalid).Error()
 	}
 
-	return usecase.completeRegularTokenFlow(ctx, "", code.Scope, user)
-}
+	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, req.AuthorizationID); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-delete-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	code, err := usecase.loadPendingDeviceCode(ctx, store.UserCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Accept {
+		usecase.oauth2FlowDomain.ApproveDeviceCode(code, session.UserID, store.Scope, session.AuthenticatedAt)
+	} else {
+		usecase.oauth2FlowDomain.DenyDeviceCode(code)
+	}
+
+	if err := usecase.oauth2DeviceRepo.UpdateDeviceCodeStatus(ctx, code); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "user code has already been used")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-save-device-code")
+	}
+
+	return dto.NewOAuth2DeviceConfirmResponse(req.Accept), nil
+}
+
+func (usecase *OAuth2FlowUsecase) loadPendingDeviceCode(ctx context.Context, userCode string) (*domain.OAuth2DeviceCode, error) {
+	code, err := usecase.oauth2DeviceRepo.LoadDeviceCodeByUserCode(ctx, domain.NormalizeUserCode(userCode))
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "user code is invalid or expired")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-device-code", "user_code", userCode)
+	}
+
+	if code.Status != domain.DeviceCodeStatusPending {
+		return nil, xerror.Enrich(ErrRequestInvalid, "user code has already been used")
+	}
+
+	return code, nil
+}
+
+func (usecase *OAuth2FlowUsecase) BackchannelAuthorize(
+	ctx context.Context,
+	req *dto.OAuth2BackchannelAuthorizeRequest,
+) (*dto.OAuth2BackchannelAuthorizeResponse, error) {
+	client, err := usecase.getClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.BackchannelTokenDeliveryMode == "" {
+		return nil, xerror.Enrich(ErrUnauthorizedClient, "the client has not registered backchannel authentication")
+	}
+
+	if err := usecase.oauth2ClientDomain.ValidateGrantType(client, GrantTypeCIBA); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-grant-type").Enrich(ErrUnauthorizedClient).Error()
+	}
+
+	if req.RequestedExpiry < 0 {
+		return nil, xerror.Enrich(ErrRequestInvalid, "requested expiry must be positive")
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	err = usecase.oauth2FlowDomain.ValidateBackchannelAuthenticationRequest(
+		client, requestedScope, req.BindingMessage, req.ClientNotificationToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrBindingMessageInvalid) {
+			return nil, domainerr.Event(err, "failed-to-validate-binding-message").Enrich(ErrBindingMessageInvalid).Error()
+		}
+
+		return nil, domainerr.Event(err, "failed-to-validate-backchannel-request").Enrich(ErrRequestInvalid).Error()
+	}
+
+	if req.LoginHint == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require login_hint")
+	}
+
+	user, err := usecase.userRepo.GetByUsername(ctx, req.LoginHint)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrUnknownUserID, "not found the user of the login hint")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "login_hint", req.LoginHint)
+	}
+
+	authReq := usecase.oauth2FlowDomain.CreateBackchannelAuthentication(client, user.ID, requestedScope,
+		req.BindingMessage, req.ClientNotificationToken, time.Duration(req.RequestedExpiry)*time.Second)
+	store := usecase.oauth2FlowDomain.CreateBackchannelAuthorizationStore(authReq)
+
+	if err := usecase.oauth2CodeRepo.SaveAuthorizationStore(ctx, store); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-authorization-store")
+	}
+
+	if err := usecase.backchannelRepo.Save(ctx, authReq); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-backchannel-authentication")
+	}
+
+	if err := usecase.userNotifier.NotifyUser(ctx, user, client, authReq); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-notify-user", "uid", user.ID)
+	}
+
+	return dto.NewOAuth2BackchannelAuthorizeResponse(authReq), nil
+}
+
+func (usecase *OAuth2FlowUsecase) AuthenticationCallback(
+	ctx context.Context,
+	req *dto.OAuth2AuthenticationCallbackRequest,
+) (*dto.OAuth2AuthenticationCallbackResponse, error) {
+	if req.Secret != usecase.idpSecret {
+		return nil, xerror.Enrich(ErrUnauthenticated, "incorrect idp secret")
+	}
+
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	if !store.IsOpen {
+		return nil, xerror.Enrich(ErrRequestInvalid, "callback api closed for this authorization id")
+	}
+
+	store.IsOpen = false
+	if err := usecase.oauth2CodeRepo.SaveAuthorizationStore(ctx, store); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-update-authorization-store")
+	}
+
+	var authResult *domain.OAuth2AuthenticationResult
+	if req.Success {
+		if _, err := usecase.userRepo.GetByID(ctx, req.UserID.Int64()); err != nil {
+			if errors.Is(err, database.ErrRecordNotFound) {
+				return nil, xerror.Enrich(ErrNotFound, "not found user with id %d", req.UserID)
+			}
+
+			return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", req.UserID)
+		}
+
+		authResult = usecase.oauth2FlowDomain.CreateAuthenticationResultSuccess(
+			req.AuthorizationID, req.UserID, req.Username)
+	} else {
+		authResult = usecase.oauth2FlowDomain.CreateAuthenticationResultFailure(
+			req.AuthorizationID, req.Error)
+	}
+
+	if err := usecase.oauth2CodeRepo.SaveAuthenticationResult(ctx, authResult); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-auth-result")
+	}
+
+	xcontext.Logger(ctx).Debug("saved-auth-result", "result", authResult.Ok, "uid", authResult.UserID)
+	return &dto.OAuth2AuthenticationCallbackResponse{AuthenticationID: authResult.ID}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) SessionUpdate(
+	ctx context.Context,
+	req *dto.OAuth2SessionUpdateRequest,
+) (*dto.OAuth2SessionUpdateResponse, error) {
+	authResult, err := usecase.oauth2CodeRepo.LoadAuthenticationResult(ctx, req.AuthenticationID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "invalid authentication id")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-auth-result", "aid", req.AuthenticationID)
+	}
+
+	if err := usecase.oauth2CodeRepo.DeleteAuthenticationResult(ctx, req.AuthenticationID); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-delete-auth-result", "err", err, "aid", req.AuthenticationID)
+	}
+
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, authResult.AuthorizationID)
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", authResult.AuthorizationID)
+	}
+
+	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, authResult.AuthorizationID); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-store", "aid", authResult.AuthorizationID)
+	}
+
+	var session *domain.Session
+	if authResult.Ok {
+		session = usecase.oauth2FlowDomain.NewSession(authResult.UserID)
+	} else {
+		session = usecase.oauth2FlowDomain.InvalidateSession(domain.SessionStateFailedAuthentication)
+	}
+
+	xcontext.Logger(ctx).Debug("save-session", "state", session.State)
+	if err = usecase.sessionRepo.Save(ctx, session); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-session", "aid", authResult.AuthorizationID)
+	}
+
+	resp := dto.NewOAuth2SessionUpdateResponse(store)
+	if store.Pushed {
+		resp.RequestURI, err = usecase.repushAuthorizationStore(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (usecase *OAuth2FlowUsecase) GetConsent(
+	ctx context.Context,
+	req *dto.OAuth2GetConsentRequest,
+) (*dto.OAuth2GetConsentResponse, error) {
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	client, err := usecase.oauth2ClientRepo.GetByID(ctx, store.ClientID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-load-client", "cid", store.ClientID)
+	}
+
+	return dto.NewOAuth2GetConsentResponse(client, store.Scope), nil
+}
+
+func (usecase *OAuth2FlowUsecase) UpdateConsent(
+	ctx context.Context,
+	req *dto.OAuth2UpdateConsentRequest,
+) (*dto.OAUth2UpdateConsentResponse, error) {
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id %s", req.AuthorizationID)
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	if store.AuthReqID != "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "this authorization is consented through the backchannel")
+	}
+
+	if store.UserID != 0 {
+		return nil, xerror.Enrich(ErrRequestInvalid, "this authorization is confirmed on the device page")
+	}
+
+	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, req.AuthorizationID); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	userID, err := usecase.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := usecase.saveConsentResult(ctx, userID, store.ClientID, req.Accept, req.UserScope); err != nil {
+		return nil, err
+	}
+
+	resp := dto.NewOAUth2UpdateConsentResponse(store)
+	if store.Pushed {
+		resp.RequestURI, err = usecase.repushAuthorizationStore(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// BackchannelConsent lets the authenticated user approve or deny the
+// backchannel authentication request which they are notified of. The decision
+// is recorded as the consent of the browser flows.
+func (usecase *OAuth2FlowUsecase) BackchannelConsent(
+	ctx context.Context,
+	req *dto.OAuth2BackchannelConsentRequest,
+) (*dto.OAuth2BackchannelConsentResponse, error) {
+	store, err := usecase.oauth2CodeRepo.LoadAuthorizationStore(ctx, req.AuthorizationID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "not found authorization id %s", req.AuthorizationID)
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	if store.AuthReqID == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "this authorization is not a backchannel authentication")
+	}
+
+	authReq, err := usecase.backchannelRepo.Load(ctx, store.AuthReqID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "the backchannel authentication is expired")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-backchannel-authentication", "aid", req.AuthorizationID)
+	}
+
+	userID := xcontext.RequestUserID(ctx)
+	if authReq.UserID != userID {
+		return nil, xerror.Enrich(ErrForbidden, "the backchannel authentication is not requested to this user")
+	}
+
+	// Any client holding an access token of the user could approve the
+	// request otherwise.
+	requiredScope := domain.ScopeEngine.New(domain.Actions.Write.Update, domain.Resources.Consent)
+	if !xcontext.Scope(ctx).Contains(requiredScope) {
+		return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", requiredScope)
+	}
+
+	if authReq.Status != domain.BackchannelAuthenticationStatusPending {
+		return nil, xerror.Enrich(ErrRequestInvalid, "the backchannel authentication has already been completed")
+	}
+
+	if err := usecase.saveConsentResult(ctx, userID, store.ClientID, req.Accept, req.UserScope); err != nil {
+		return nil, err
+	}
+
+	authorizeReq := &dto.OAuth2AuthorizeRequest{ClientID: store.ClientID, Scope: store.Scope.String()}
+	resp, consentScope, err := usecase.validateConsentResult(ctx, userID.Int64(), authorizeReq, store.Scope)
+	if err != nil && !errors.Is(err, ErrAuthorizationAccessDenied) {
+		return nil, err
+	}
+
+	if resp != nil {
+		return nil, ErrServer.Hide(errors.New("consent result is not found"), "failed-to-validate-consent-result")
+	}
+
+	if err != nil {
+		usecase.oauth2FlowDomain.DenyBackchannelAuthentication(authReq)
+	} else {
+		usecase.oauth2FlowDomain.ApproveBackchannelAuthentication(authReq, consentScope, time.Now())
+	}
+
+	if err := usecase.backchannelRepo.UpdateStatus(ctx, authReq); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestInvalid, "the backchannel authentication has already been completed")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-update-backchannel-authentication")
+	}
+
+	if err := usecase.oauth2CodeRepo.DeleteAuthorizationStore(ctx, req.AuthorizationID); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-store", "aid", req.AuthorizationID)
+	}
+
+	client, err := usecase.oauth2ClientRepo.GetByID(ctx, authReq.ClientID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", authReq.ClientID)
+	}
+
+	// The user has already decided, a client missing the notification can
+	// still poll the token endpoint. The user does not wait for the client.
+	if client.BackchannelTokenDeliveryMode == domain.BackchannelTokenDeliveryModePing {
+		go usecase.pingClient(context.WithoutCancel(ctx), client, authReq)
+	}
+
+	return &dto.OAuth2BackchannelConsentResponse{
+		Approved: authReq.Status == domain.BackchannelAuthenticationStatusApproved,
+	}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) pingClient(
+	ctx context.Context,
+	client *domain.OAuth2Client,
+	authReq *domain.OAuth2BackchannelAuthentication,
+) {
+	if err := usecase.clientNotifier.PingClient(ctx, client, authReq); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-ping-client", "cid", client.ID, "err", err)
+	}
+}
+
+func (usecase *OAuth2FlowUsecase) handleAuthorizeFlow(
+	ctx context.Context,
+	req *dto.OAuth2AuthorizeRequest,
+	client *domain.OAuth2Client,
+	requestedScope scope.Scopes,
+) (*dto.OAuth2AuthorizeResponse, error) {
+	session, err := usecase.getAuthenticatedSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		store, err := usecase.storeAuthorization(ctx, req, requestedScope)
+		if err != nil {
+			return nil, err
+		}
+
+		return dto.NewOAuth2AuthorizeResponseRedirectToIdP(usecase.idpLoginURL, store.ID), nil
+	}
+
+	resp, consentScope, err := usecase.validateConsentResult(ctx, session.UserID.Int64(), req, requestedScope)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	resp = &dto.OAuth2AuthorizeResponse{}
+	if hasResponseType(req.ResponseType, ResponseTypeCode) {
+		code := usecase.oauth2FlowDomain.CreateAuthorizationCode(
+			session.UserID, req.ClientID, consentScope,
+			req.RedirectURI, req.CodeChallenge, req.CodeChallengeMethod, req.Nonce,
+			session.AuthenticatedAt,
+		)
+		if err = usecase.oauth2CodeRepo.SaveAuthorizationCode(ctx, code); err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-save-authorization-code")
+		}
+
+		resp.Code = code.Code
+	}
+
+	if req.ResponseType == ResponseTypeCode {
+		return resp, nil
+	}
+
+	user, err := usecase.userRepo.GetByID(ctx, session.UserID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", session.UserID)
+	}
+
+	// The implicit flow never issues refresh tokens, the client must go
+	// through the authorization endpoint again to get a new access token.
+	if hasResponseType(req.ResponseType, ResponseTypeToken) {
+		accessToken := usecase.oauth2FlowDomain.CreateAccessToken(client.ID.String(), consentScope, user, client.ID)
+		accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-generate-access-token")
+		}
+
+		resp.AccessToken = accessTokenString
+		resp.TokenType = usecase.tokenEngine.Type()
+		resp.ExpiresIn = usecase.getExpiresIn(accessToken.Metadata)
+		resp.Scope = consentScope.String()
+	}
+
+	if hasResponseType(req.ResponseType, ResponseTypeIDToken) {
+		idToken := usecase.oauth2FlowDomain.CreateIDToken(
+			client.ID.String(), user, session.AuthenticatedAt, req.Nonce, resp.AccessToken, resp.Code)
+
+		resp.IDToken, err = usecase.tokenEngine.Generate(ctx, dto.OAuth2IDTokenFromDomain(idToken))
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-generate-id-token")
+		}
+	}
+
+	return resp, nil
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenCodeFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	code, err := usecase.oauth2CodeRepo.LoadAuthorizationCode(ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "invalid code")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-code", "code", req.Code)
+	}
+
+	if err := usecase.oauth2CodeRepo.DeleteAuthorizationCode(ctx, req.Code); err != nil {
+		xcontext.Logger(ctx).Warn("failed-to-delete-authorization-code", "err", err)
+	}
+
+	if code.ClientID != client.ID {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the code was not issued to this client")
+	}
+
+	if code.RedirectURI != req.RedirectURI {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "mismatched redirect uri")
+	}
+
+	// Confidential clients always authenticate themselves, the code of public
+	// clients is bound to them by PKCE instead.
+	confidentialRequirement := domain.DependOnClientConfidential
+	if code.CodeChallenge == "" {
+		confidentialRequirement = domain.RequireConfidential
+	}
+
+	err = usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, confidentialRequirement)
+	if err != nil {
+		return nil, err
+	}
+
+	if code.CodeChallenge != "" {
+		if !usecase.oauth2FlowDomain.ValidateCodeChallenge(req.CodeVerifier, code.CodeChallenge, code.CodeChallengeMethod) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "incorrect code verifier")
+		}
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := usecase.userRepo.GetByID(ctx, code.UserID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", code.UserID)
+	}
+
+	return usecase.completeRegularTokenFlow(ctx, aud, code.Scope, user, client, &req.OAuth2ClientAuthentication, proof, code.AuthTime, code.Nonce)
+}
 
 func (usecase *OAuth2FlowUsecase) handleTokenPasswordFlow(
 	ctx context.Context,
-	req *dto.OAuth2TokenRequest,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the user information.
+	user, err := usecase.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "invalid username or password")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "username", req.Username)
+	}
+
+	if err = usecase.userDomain.Validate(user.HashedPass, req.Password); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-user-credential").
+			EnrichWith(ErrTokenInvalidGrant, "invalid username or password").Error()
+
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return usecase.completeRegularTokenFlow(ctx, aud, requestedScope, user, client, &req.OAuth2ClientAuthentication, proof, time.Now(), "")
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenClientCredentialsFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedClientScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client acts on its own behalf, so there is no refresh token here, it
+	// can always request a new access token with its credentials.
+	accessToken := usecase.oauth2FlowDomain.CreateClientAccessToken(aud, requestedScope, client)
+	usecase.bindAccessToken(client, &req.OAuth2ClientAuthentication, proof, accessToken)
+	accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-generate-access-token")
+	}
+
+	return &dto.OAuth2TokenResponse{
+		AccessToken: accessTokenString,
+		TokenType:   usecase.getTokenType(accessToken),
+		ExpiresIn:   usecase.getExpiresIn(accessToken.Metadata),
+		Scope:       requestedScope.String(),
+	}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenRefreshTokenFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.DependOnClientConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the current refresh token
+	curRefreshToken := dto.OAuth2RefreshToken{}
+	ok, err := usecase.tokenEngine.Validate(ctx, req.RefreshToken, &curRefreshToken)
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-validate-refresh-token")
+	}
+
+	if !ok {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "refresh token is invalid or expired")
+	}
+
+	// Generate the next refresh token.
+	domainCurRefreshToken, err := curRefreshToken.To()
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-convert-refresh-token")
+	}
+
+	if domainCurRefreshToken.ClientID != client.ID {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the refresh token was not issued to this client")
+	}
+
+	if domainCurRefreshToken.KeyThumbprint != "" {
+		if proof == nil || proof.KeyThumbprint != domainCurRefreshToken.KeyThumbprint {
+			return nil, xerror.Enrich(ErrDPoPProofInvalid, "the refresh token is bound to another dpop key")
+		}
+	}
+
+	// The client can request a narrower scope for the access token, but the
+	// refresh token always keeps the originally granted scope.
+	accessTokenScope := domainCurRefreshToken.Scope
+	if req.Scope != "" {
+		accessTokenScope = domain.ScopeEngine.ParseScopes(req.Scope)
+		if err := usecase.oauth2FlowDomain.ValidateRefreshScope(accessTokenScope, domainCurRefreshToken); err != nil {
+			return nil, domainerr.Event(err, "failed-to-validate-refresh-scope").Enrich(ErrScopeInvalid).Error()
+		}
+	}
+
+	refreshToken := usecase.oauth2FlowDomain.NextRefreshToken(domainCurRefreshToken)
+
+	// Get the user.
+	user, err := usecase.userRepo.GetByID(ctx, refreshToken.Metadata.Subject.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", refreshToken.Metadata.Subject)
+	}
+
+	// The access token keeps the audience of the grant unless the client
+	// requests another resource.
+	aud := domainCurRefreshToken.Metadata.Audience
+	if req.Resource != "" {
+		aud, err = usecase.getAudience(client, req.Resource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Generate access token.
+	accessToken := usecase.oauth2FlowDomain.CreateAccessToken(aud, accessTokenScope, user, client.ID)
+	usecase.bindAccessToken(client, &req.OAuth2ClientAuthentication, proof, accessToken)
+
+	// Serialize both tokens.
+	accessTokenString, refreshTokenString, err := usecase.serializeAccessAndRefreshTokens(ctx, accessToken, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the seq number again.
+	err = usecase.refreshTokenRepo.UpdateByRefreshTokenID(
+		ctx,
+		domainCurRefreshToken.Metadata.ID.Int64(),
+		accessToken.Metadata.ID.Int64(),
+		domainCurRefreshToken.SequenceNumber,
+	)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			// The refresh token was already rotated or revoked. A rotated token
+			// being replayed means either the client or an attacker holds a
+			// stolen copy, so the whole family is revoked.
+			family, err := usecase.revokeRefreshTokenFamily(ctx, domainCurRefreshToken.Metadata.ID)
+			if err != nil {
+				xcontext.Logger(ctx).Warn("failed-to-revoke-refresh-token-family", "err", err)
+			}
+
+			if family != nil {
+				xcontext.Logger(ctx).Warn("refresh-token-reuse-detected",
+					"fid", family.ID, "uid", family.UserID, "cid", family.ClientID,
+					"seq", domainCurRefreshToken.SequenceNumber, "latest_seq", family.SequenceNumber)
+			}
+
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "refresh token was revoked")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-update-token")
+	}
+
+	return &dto.OAuth2TokenResponse{
+		AccessToken:  accessTokenString,
+		TokenType:    usecase.getTokenType(accessToken),
+		ExpiresIn:    usecase.getExpiresIn(accessToken.Metadata),
+		RefreshToken: refreshTokenString,
+		Scope:        accessTokenScope.String(),
+	}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenDeviceFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.DependOnClientConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := usecase.oauth2DeviceRepo.LoadDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenExpired, "device code is invalid or expired")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-device-code")
+	}
+
+	if code.ClientID != client.ID {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "device code was not issued to this client")
+	}
+
+	switch code.Status {
+	case domain.DeviceCodeStatusPending:
+		ok := usecase.oauth2FlowDomain.PollDeviceCode(code)
+		if err := usecase.oauth2DeviceRepo.SaveDeviceCodePoll(ctx, code); err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-save-device-code-poll")
+		}
+
+		if !ok {
+			return nil, xerror.Enrich(ErrTokenSlowDown, "polling too frequently, wait at least %s", code.Interval)
+		}
+
+		return nil, xerror.Enrich(ErrTokenAuthorizationPending, "the user has not completed the authorization yet")
+
+	case domain.DeviceCodeStatusDenied:
+		if err := usecase.oauth2DeviceRepo.DeleteDeviceCode(ctx, code); err != nil {
+			xcontext.Logger(ctx).Warn("failed-to-delete-device-code", "err", err)
+		}
+
+		return nil, xerror.Enrich(ErrAuthorizationAccessDenied, "user declined to grant access")
+	}
+
+	// Only the request which deletes the device code can redeem it.
+	if err := usecase.oauth2DeviceRepo.DeleteDeviceCode(ctx, code); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "device code has already been used")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-delete-device-code")
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := usecase.userRepo.GetByID(ctx, code.UserID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", code.UserID)
+	}
+
+	return usecase.completeRegularTokenFlow(ctx, aud, code.Scope, user, client, &req.OAuth2ClientAuthentication, proof, code.AuthTime, "")
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenBackchannelFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	authReq, err := usecase.backchannelRepo.Load(ctx, req.AuthReqID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenExpired, "auth_req_id is invalid or expired")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-backchannel-authentication")
+	}
+
+	if authReq.ClientID != client.ID {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "auth_req_id was not issued to this client")
+	}
+
+	switch authReq.Status {
+	case domain.BackchannelAuthenticationStatusPending:
+		ok := usecase.oauth2FlowDomain.PollBackchannelAuthentication(authReq)
+		if err := usecase.backchannelRepo.SavePoll(ctx, authReq); err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-save-backchannel-authentication")
+		}
+
+		if !ok {
+			return nil, xerror.Enrich(ErrTokenSlowDown, "polling too frequently, wait at least %s", authReq.Interval)
+		}
+
+		return nil, xerror.Enrich(ErrTokenAuthorizationPending, "the user has not completed the authorization yet")
+
+	case domain.BackchannelAuthenticationStatusDenied:
+		if err := usecase.backchannelRepo.Delete(ctx, authReq.AuthReqID); err != nil {
+			xcontext.Logger(ctx).Warn("failed-to-delete-backchannel-authentication", "err", err)
+		}
+
+		return nil, xerror.Enrich(ErrAuthorizationAccessDenied, "user declined to grant access")
+	}
+
+	// Only the request which deletes the backchannel authentication can
+	// redeem it.
+	if err := usecase.backchannelRepo.Delete(ctx, authReq.AuthReqID); err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "auth_req_id has already been used")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-delete-backchannel-authentication")
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := usecase.userRepo.GetByID(ctx, authReq.UserID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", authReq.UserID)
+	}
+
+	return usecase.completeRegularTokenFlow(ctx, aud, authReq.Scope, user, client, &req.OAuth2ClientAuthentication, proof, authReq.AuthTime, "")
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenExchangeFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.RequireConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RequestedTokenType != "" && req.RequestedTokenType != TokenTypeIdentifierAccessToken {
+		return nil, xerror.Enrich(ErrRequestInvalid, "not support requested token type %s", req.RequestedTokenType)
+	}
+
+	subjectToken, err := usecase.parseExchangedToken(ctx, "subject", req.SubjectToken, req.SubjectTokenType, proof, &req.OAuth2ClientAuthentication)
+	if err != nil {
+		return nil, err
+	}
+
+	var actorToken *domain.OAuth2AccessToken
+	if req.ActorToken != "" {
+		actorToken, err = usecase.parseExchangedToken(ctx, "actor", req.ActorToken, req.ActorTokenType, proof, &req.OAuth2ClientAuthentication)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := usecase.oauth2FlowDomain.ValidateActorChain(subjectToken); err != nil {
+			return nil, domainerr.Event(err, "failed-to-validate-actor-chain").Enrich(ErrTokenInvalidGrant).Error()
+		}
+	} else if req.ActorTokenType != "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require actor_token along with actor_token_type")
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	exchangedScope, err := usecase.oauth2FlowDomain.ValidateExchangeScope(requestedScope, subjectToken, client)
+	if err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-exchange-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	// Both the resource and the audience indicate where the token is used,
+	// but the token has only one audience.
+	target := req.Resource
+	if target == "" {
+		target = req.Audience
+	} else if req.Audience != "" && req.Audience != req.Resource {
+		return nil, xerror.Enrich(ErrTargetInvalid, "the resource and the audience must be the same if both are provided")
+	}
+
+	aud, err := usecase.getAudience(client, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no refresh token since the exchanged token is bounded by the
+	// lifetime of the subject token.
+	accessToken := usecase.oauth2FlowDomain.CreateExchangedAccessToken(aud, exchangedScope, subjectToken, actorToken, client)
+	usecase.bindAccessToken(client, &req.OAuth2ClientAuthentication, proof, accessToken)
+	accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-generate-access-token")
+	}
+
+	return &dto.OAuth2TokenResponse{
+		AccessToken:     accessTokenString,
+		TokenType:       usecase.getTokenType(accessToken),
+		ExpiresIn:       usecase.getExpiresIn(accessToken.Metadata),
+		Scope:           exchangedScope.String(),
+		IssuedTokenType: TokenTypeIdentifierAccessToken,
+	}, nil
+}
+
+func (usecase *OAuth2FlowUsecase) handleTokenJWTBearerFlow(
+	ctx context.Context,
+	req *dto.OAuth2TokenRequest,
+	client *domain.OAuth2Client,
+	proof *domain.OAuth2DPoPProof,
+) (*dto.OAuth2TokenResponse, error) {
+	err := usecase.authenticateClient(ctx, client, &req.OAuth2ClientAuthentication, domain.DependOnClientConfidential)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Assertion == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require assertion")
+	}
+
+	assertion := dto.OAuth2BearerAssertion{}
+	issuer, err := usecase.issuerEngine.Validate(ctx, req.Assertion, &assertion)
+	if err != nil {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the assertion is invalid").
+			Hide(err, "failed-to-validate-bearer-assertion", "cid", client.ID)
+	}
+
+	expiresAt := time.Unix(int64(assertion.ExpiresAt), 0)
+	err = usecase.oauth2FlowDomain.ValidateTrustedIssuer(issuer, client, assertion.IssuedTime(), expiresAt)
+	if err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-trusted-issuer").Enrich(ErrTokenInvalidGrant).Error()
+	}
+
+	if err := usecase.oauth2FlowDomain.ValidateAssertionAudience(assertion.Audience); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-assertion-audience").Enrich(ErrTokenInvalidGrant).Error()
+	}
+
+	user, err := usecase.getAssertedUser(ctx, issuer, assertion.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	aud, err := usecase.getAudience(client, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client can always present a new assertion, so there is no refresh
+	// token here.
+	accessToken := usecase.oauth2FlowDomain.CreateAccessToken(aud, requestedScope, user, client.ID)
+	usecase.bindAccessToken(client, &req.OAuth2ClientAuthentication, proof, accessToken)
+	accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-generate-access-token")
+	}
+
+	return &dto.OAuth2TokenResponse{
+		AccessToken: accessTokenString,
+		TokenType:   usecase.getTokenType(accessToken),
+		ExpiresIn:   usecase.getExpiresIn(accessToken.Metadata),
+		Scope:       requestedScope.String(),
+	}, nil
+}
+
+// getAssertedUser finds the local user identified by the subject of the
+// assertion, either by the user id or by the username.
+func (usecase *OAuth2FlowUsecase) getAssertedUser(
+	ctx context.Context,
+	issuer *domain.OAuth2TrustedIssuer,
+	subject string,
+) (*domain.User, error) {
+	var user *domain.User
+	var err error
+
+	switch issuer.UserMapping {
+	case domain.UserMappingUsername:
+		user, err = usecase.userRepo.GetByUsername(ctx, subject)
+	default:
+		userID, parseErr := snowflake.ParseString(subject)
+		if parseErr != nil {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "the subject of the assertion is not a user id")
+		}
+
+		user, err = usecase.userRepo.GetByID(ctx, userID.Int64())
+	}
+
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "not found the user of the assertion")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "iss", issuer.Issuer, "sub", subject)
+	}
+
+	return user, nil
+}
+
+// parseExchangedToken validates the subject token or the actor token of the
+// token exchange, only active access tokens issued by this server are
+// accepted. A sender-constrained token is only accepted if the client proves
+// the possession of the key which the token is bound to, otherwise the
+// exchange would strip the binding off a stolen token.
+func (usecase *OAuth2FlowUsecase) parseExchangedToken(
+	ctx context.Context,
+	name string,
+	token string,
+	tokenType string,
+	proof *domain.OAuth2DPoPProof,
+	auth *dto.OAuth2ClientAuthentication,
+) (*domain.OAuth2AccessToken, error) {
+	if token == "" {
+		return nil, xerror.Enrich(ErrRequestInvalid, "require %s_token", name)
+	}
+
+	if tokenType != TokenTypeIdentifierAccessToken {
+		return nil, xerror.Enrich(ErrRequestInvalid, "not support %s_token_type %s", name, tokenType)
+	}
+
+	claims := dto.OAuth2GenericToken{}
+	ok, err := usecase.tokenEngine.Validate(ctx, token, &claims)
+	if err != nil {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token is invalid", name).
+			Hide(err, "failed-to-validate-exchanged-token")
+	}
+
+	if !ok || claims.IsRefreshToken() {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token is invalid", name)
+	}
+
+	accessToken, err := claims.AccessToken()
+	if err != nil {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token is invalid", name).
+			Hide(err, "failed-to-convert-exchanged-token")
+	}
+
+	revoked, err := usecase.denylistRepo.Contains(ctx, accessToken.Metadata.ID.Int64())
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-check-denylist", "jti", accessToken.Metadata.ID)
+	}
+
+	if revoked {
+		return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token was revoked", name)
+	}
+
+	if accessToken.CertificateThumbprint != "" {
+		if auth.ClientCertificate == nil || auth.ClientCertificate.Thumbprint() != accessToken.CertificateThumbprint {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token is bound to another certificate", name)
+		}
+	}
+
+	if accessToken.KeyThumbprint != "" {
+		if proof == nil || proof.KeyThumbprint != accessToken.KeyThumbprint {
+			return nil, xerror.Enrich(ErrTokenInvalidGrant, "the %s token is bound to another dpop key", name)
+		}
+	}
+
+	return accessToken, nil
+}
+
+func (usecase *OAuth2FlowUsecase) serializeAccessAndRefreshTokens(
+	ctx context.Context,
+	accessToken *domain.OAuth2AccessToken,
+	refreshToken *domain.OAuth2RefreshToken,
+) (string, string, error) {
+	accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+	if err != nil {
+		return "", "", ErrServer.Hide(err, "failed-to-generate-access-token")
+	}
+
+	refreshTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2RefreshTokenFromDomain(refreshToken))
+	if err != nil {
+		return "", "", ErrServer.Hide(err, "failed-to-generate-refresh-token")
+	}
+
+	return accessTokenString, refreshTokenString, nil
+}
+
+func (usecase *OAuth2FlowUsecase) completeRegularTokenFlow(
+	ctx context.Context,
+	aud string,
+	scope scope.Scopes,
+	user *domain.User,
+	client *domain.OAuth2Client,
+	auth *dto.OAuth2ClientAuthentication,
+	proof *domain.OAuth2DPoPProof,
+	authTime time.Time,
+	nonce string,
+) (*dto.OAuth2TokenResponse, error) {
+	accessToken := usecase.oauth2FlowDomain.CreateAccessToken(aud, scope, user, client.ID)
+	usecase.bindAccessToken(client, auth, proof, accessToken)
+
+	refreshToken := usecase.oauth2FlowDomain.CreateRefreshToken(aud, scope, user.ID, client.ID)
+	if proof != nil {
+		usecase.oauth2FlowDomain.BindRefreshTokenKey(client, refreshToken, proof.KeyThumbprint)
+	}
+
+	// Serialize both tokens.
+	accessTokenString, refreshTokenString, err := usecase.serializeAccessAndRefreshTokens(ctx, accessToken, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store refresh token information.
+	family := usecase.oauth2FlowDomain.CreateRefreshTokenFamily(refreshToken, accessToken)
+	if err := usecase.refreshTokenRepo.Create(ctx, family); err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-save-refresh-token")
+	}
+
+	idTokenString := ""
+	if scope.Contains(domain.ScopeOpenID) {
+		idToken := usecase.oauth2FlowDomain.CreateIDToken(
+			client.ID.String(), user, authTime, nonce, accessTokenString, "")
+
+		idTokenString, err = usecase.tokenEngine.Generate(ctx, dto.OAuth2IDTokenFromDomain(idToken))
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-generate-id-token")
+		}
+	}
+
+	return &dto.OAuth2TokenResponse{
+		AccessToken:  accessTokenString,
+		TokenType:    usecase.getTokenType(accessToken),
+		ExpiresIn:    usecase.getExpiresIn(accessToken.Metadata),
+		RefreshToken: refreshTokenString,
+		Scope:        scope.String(),
+		IDToken:      idTokenString,
+	}, nil
+}
+
+// revokeRefreshTokenFamily deletes the refresh token family and denies the
+// latest access token minted from it. It returns nil if the family was
+// already revoked.
+func (usecase *OAuth2FlowUsecase) revokeRefreshTokenFamily(
+	ctx context.Context,
+	familyID snowflake.ID,
+) (*domain.OAuth2RefreshTokenFamily, error) {
+	family, err := usecase.refreshTokenRepo.GetByRefreshTokenID(ctx, familyID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if err := usecase.refreshTokenRepo.DeleteByRefreshTokenID(ctx, family.ID.Int64()); err != nil {
+		return nil, err
+	}
+
+	expiresAt := usecase.oauth2FlowDomain.AccessTokenExpiresAt(family)
+	if err := usecase.denylistRepo.Add(ctx, family.AccessTokenID.Int64(), expiresAt); err != nil {
+		return nil, err
+	}
+
+	return family, nil
+}
+
+// authenticateClient checks the credentials of the client with the token
+// endpoint auth method used in the request.
+func (usecase *OAuth2FlowUsecase) authenticateClient(
+	ctx context.Context,
 	client *domain.OAuth2Client,
-) (*dto.OAuth2TokenResponse, error) {
-
-	err := usecase.oauth2ClientDomain.ValidateClient(
-		client, req.ClientID, req.ClientSecret, domain.RequireConfidential)
-	if err != nil {
-		return nil, xerror.Enrich(ErrClientInvalid, "failed due to invalid client credentials").
-			Hide(err, "validate-client-failed")
+	auth *dto.OAuth2ClientAuthentication,
+	confidentialRequirement domain.ConfidentialRequirementType,
+) error {
+	// The adapter cannot tell the mutual-TLS methods apart, a client sending
+	// only its certificate uses the one it registered.
+	method := auth.AuthMethod
+	if method == domain.TokenEndpointAuthMethodNone && auth.ClientCertificate != nil &&
+		domain.IsTLSClientAuthMethod(client.TokenEndpointAuthMethod) {
+		method = client.TokenEndpointAuthMethod
 	}
 
-	// Get the user information.
-	user, err := usecase.userRepo.GetByUsername(ctx, req.Username)
-	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			return nil, xerror.Enrich(ErrTokenInvalidGrant, "invalid username or password")
+	// The secret without a method is accepted by whichever secret method the
+	// client registered.
+	if method == "" {
+		method = domain.TokenEndpointAuthMethodClientSecretBasic
+		if domain.IsSecretClientAuthMethod(client.TokenEndpointAuthMethod) {
+			method = client.TokenEndpointAuthMethod
 		}
+	}
 
-		return nil, ErrServer.Hide(err, "failed-to-get-user", "username", req.Username)
+	if err := usecase.oauth2ClientDomain.ValidateTokenEndpointAuthMethod(client, method); err != nil {
+		return xerror.Enrich(ErrClientInvalid, "failed due to invalid client authentication method").
+			Hide(err, "validate-token-endpoint-auth-method-failed", "cid", client.ID, "method", method)
 	}
 
-	if err = usecase.userDomain.Validate(user.HashedPass, req.Password); err != nil {
-		return nil, domainerr.Event(err, "failed-to-validate-user-credential").
-			EnrichWith(ErrTokenInvalidGrant, "invalid username or password").Error()
+	switch {
+	case method == domain.TokenEndpointAuthMethodPrivateKeyJWT:
+		return usecase.authenticateClientAssertion(ctx, client, auth)
 
-	}
+	case domain.IsTLSClientAuthMethod(method):
+		// The confidential requirement is satisfied since only confidential
+		// clients can register the mutual-TLS methods.
+		if auth.ClientCertificate == nil {
+			return xerror.Enrich(ErrClientInvalid, "require a client certificate")
+		}
 
-	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
-	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
-		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
-	}
+		err := usecase.oauth2ClientDomain.ValidateClientCertificate(
+			client, auth.ClientID, auth.ClientCertificate.Certificate, auth.ClientCertificate.Trusted)
+		if err != nil {
+			return xerror.Enrich(ErrClientInvalid, "failed due to invalid client certificate").
+				Hide(err, "validate-client-certificate-failed", "cid", client.ID)
+		}
+
+		return nil
+
+	default:
+		err := usecase.oauth2ClientDomain.ValidateClient(client, auth.ClientID, auth.ClientSecret, confidentialRequirement)
+		if err != nil {
+			return xerror.Enrich(ErrClientInvalid, "failed due to invalid client credentials").
+				Hide(err, "validate-client-failed")
+		}
 
-	return usecase.completeRegularTokenFlow(ctx, "", requestedScope, user)
+		return nil
+	}
 }
 
-func (usecase *OAuth2FlowUsecase) handleTokenRefreshTokenFlow(
+// authenticateClientAssertion authenticates the client by the assertion
+// instead of the secret (private_key_jwt), the confidential requirement is
+// satisfied since only confidential clients can register this method.
+func (usecase *OAuth2FlowUsecase) authenticateClientAssertion(
 	ctx context.Context,
-	req *dto.OAuth2TokenRequest,
 	client *domain.OAuth2Client,
-) (*dto.OAuth2TokenResponse, error) {
-	err := usecase.oauth2ClientDomain.ValidateClient(
-		client, req.ClientID, req.ClientSecret, domain.DependOnClientConfidential)
+	auth *dto.OAuth2ClientAuthentication,
+) error {
+	engine, err := usecase.jwksEngineFactory.New(client.JWKS)
 	if err != nil {
-		return nil, xerror.Enrich(ErrClientInvalid, "failed due to invalid client credentials").
-			Hide(err, "validate-client-failed")
+		return xerror.Enrich(ErrClientInvalid, "the keys of the client are invalid").
+			Hide(err, "failed-to-create-client-token-engine", "cid", client.ID)
 	}
 
-	// Check the current refresh token
-	curRefreshToken := dto.OAuth2RefreshToken{}
-	ok, err := usecase.tokenEngine.Validate(ctx, req.RefreshToken, &curRefreshToken)
+	assertion := dto.OAuth2ClientAssertion{}
+	ok, err := engine.Validate(ctx, auth.ClientAssertion, &assertion)
 	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-validate-refresh-token")
+		return xerror.Enrich(ErrClientInvalid, "the client assertion is invalid").
+			Hide(err, "failed-to-validate-client-assertion", "cid", client.ID)
 	}
 
 	if !ok {
-		return nil, xerror.Enrich(ErrTokenInvalidGrant, "refresh token is invalid or expired")
+		return xerror.Enrich(ErrClientInvalid, "the client assertion is invalid")
 	}
 
-	// Generate the next refresh token.
-	domainCurRefreshToken, err := curRefreshToken.To()
-	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-convert-refresh-token")
+	if assertion.Issuer != client.ID.String() || assertion.Subject != client.ID.String() {
+		return xerror.Enrich(ErrClientInvalid, "the client assertion must be issued by the client for itself")
 	}
 
-	refreshToken := usecase.oauth2FlowDomain.NextRefreshToken(domainCurRefreshToken)
+	if auth.ClientID != client.ID {
+		return xerror.Enrich(ErrClientInvalid, "mismatched client id")
+	}
 
-	// Get the user.
-	user, err := usecase.userRepo.GetByID(ctx, refreshToken.Metadata.Subject.Int64())
+	if err := usecase.oauth2FlowDomain.ValidateAssertionAudience(assertion.Audience); err != nil {
+		return domainerr.Event(err, "failed-to-validate-assertion-audience").Enrich(ErrClientInvalid).Error()
+	}
+
+	expiresAt := time.Unix(int64(assertion.ExpiresAt), 0)
+	ok, err = usecase.assertionRepo.Add(ctx, client.ID.Int64(), assertion.ID, expiresAt)
 	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", refreshToken.Metadata.Subject)
+		return ErrServer.Hide(err, "failed-to-add-client-assertion", "cid", client.ID)
 	}
 
-	// Generate access token.
-	accessToken := usecase.oauth2FlowDomain.CreateAccessToken(
-		domainCurRefreshToken.Metadata.Audience, domainCurRefreshToken.Scope, user)
+	if !ok {
+		return xerror.Enrich(ErrClientInvalid, "the client assertion was already used")
+	}
 
-	// Serialize both tokens.
-	accessTokenString, refreshTokenString, err := usecase.serializeAccessAndRefreshTokens(ctx, accessToken, refreshToken)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// bindAccessToken binds the access token to the certificate of the client if
+// the client authenticated itself by mutual-TLS, and to the key of the DPoP
+// proof if there is any.
+func (usecase *OAuth2FlowUsecase) bindAccessToken(
+	client *domain.OAuth2Client,
+	auth *dto.OAuth2ClientAuthentication,
+	proof *domain.OAuth2DPoPProof,
+	accessToken *domain.OAuth2AccessToken,
+) {
+	if auth.ClientCertificate != nil && domain.IsTLSClientAuthMethod(client.TokenEndpointAuthMethod) {
+		usecase.oauth2FlowDomain.BindCertificate(accessToken, auth.ClientCertificate.Certificate)
 	}
 
-	// Store the seq number again.
-	err = usecase.refreshTokenRepo.UpdateByRefreshTokenID(
-		ctx,
-		domainCurRefreshToken.Metadata.ID.Int64(),
-		accessToken.Metadata.ID.Int64(),
-		domainCurRefreshToken.SequenceNumber,
-	)
+	if proof != nil {
+		usecase.oauth2FlowDomain.BindKey(accessToken, proof.KeyThumbprint)
+	}
+}
+
+// validateDPoPProof returns nil if there is no proof. The access token is
+// only presented along with the proof at resource servers, while the nonce
+// is only required at the token endpoint.
+func (usecase *OAuth2FlowUsecase) validateDPoPProof(
+	ctx context.Context,
+	dpop *dto.OAuth2DPoP,
+	accessToken string,
+	requireNonce bool,
+) (*domain.OAuth2DPoPProof, error) {
+	if dpop.Proof == "" {
+		return nil, nil
+	}
+
+	claims := dto.OAuth2DPoPProof{}
+	keyThumbprint, err := usecase.dpopEngine.Validate(ctx, dpop.Proof, &claims)
 	if err != nil {
-		if errors.Is(err, database.ErrRecordNotFound) {
-			err = usecase.refreshTokenRepo.DeleteByRefreshTokenID(ctx, domainCurRefreshToken.Metadata.ID.Int64())
-			if err != nil {
-				xcontext.Logger(ctx).Warn("failed-to-delete-token", "err", err)
-			}
+		return nil, xerror.Enrich(ErrDPoPProofInvalid, "the dpop proof is invalid").
+			Hide(err, "failed-to-parse-dpop-proof")
+	}
 
-			return nil, xerror.Enrich(ErrTokenInvalidGrant, "refresh token was stolen")
+	proof := claims.To(keyThumbprint)
+	if err := usecase.oauth2FlowDomain.ValidateDPoPProof(proof, dpop.Method, dpop.URI, accessToken); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-dpop-proof").Enrich(ErrDPoPProofInvalid).Error()
+	}
+
+	if requireNonce {
+		if proof.Nonce == "" {
+			return nil, xerror.Enrich(ErrUseDPoPNonce, "require a nonce in the dpop proof")
 		}
 
-		return nil, ErrServer.Hide(err, "failed-to-update-token")
+		ok, err := usecase.dpopNonceRepo.Contains(ctx, proof.Nonce)
+		if err != nil {
+			return nil, ErrServer.Hide(err, "failed-to-check-dpop-nonce")
+		}
+
+		if !ok {
+			return nil, xerror.Enrich(ErrUseDPoPNonce, "the dpop nonce is invalid or expired")
+		}
 	}
 
-	return &dto.OAuth2TokenResponse{
-		AccessToken:  accessTokenString,
-		TokenType:    usecase.tokenEngine.Type(),
-		ExpiresIn:    usecase.getExpiresIn(accessToken.Metadata),
-		RefreshToken: refreshTokenString,
-		Scope:        domainCurRefreshToken.Scope.String(),
-	}, nil
+	expiresAt := proof.IssuedAt.Add(domain.DPoPProofLifetime)
+	ok, err := usecase.dpopProofRepo.Add(ctx, proof.KeyThumbprint, proof.ID, expiresAt)
+	if err != nil {
+		return nil, ErrServer.Hide(err, "failed-to-add-dpop-proof")
+	}
+
+	if !ok {
+		return nil, xerror.Enrich(ErrDPoPProofInvalid, "the dpop proof was already used")
+	}
+
+	return proof, nil
 }
 
-func (usecase *OAuth2FlowUsecase) serializeAccessAndRefreshTokens(
-	ctx context.Context,
-	accessToken *domain.OAuth2AccessToken,
-	refreshToken *domain.OAuth2RefreshToken,
-) (string, string, error) {
-	accessTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2AccessTokenFromDomain(accessToken))
+// getClient loads the client taking part in a flow. A disabled client is
+// rejected as if it did not exist.
+func (usecase *OAuth2FlowUsecase) getClient(ctx context.Context, clientID snowflake.ID) (*domain.OAuth2Client, error) {
+	client, err := usecase.oauth2ClientRepo.GetByID(ctx, clientID.Int64())
 	if err != nil {
-		return "", "", ErrServer.Hide(err, "failed-to-generate-access-token")
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrClientInvalid, "client is not found")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-client", "cid", clientID)
 	}
 
-	refreshTokenString, err := usecase.tokenEngine.Generate(ctx, dto.OAuth2RefreshTokenFromDomain(refreshToken))
-	if err != nil {
-		return "", "", ErrServer.Hide(err, "failed-to-generate-refresh-token")
+	if client.IsDisabled {
+		return nil, xerror.Enrich(ErrClientInvalid, "client is disabled")
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	return client, nil
 }
 
-func (usecase *OAuth2FlowUsecase) completeRegularTokenFlow(
-	ctx context.Context,
-	aud string,
-	scope scope.Scopes,
-	user *domain.User,
-) (*dto.OAuth2TokenResponse, error) {
-	accessToken := usecase.oauth2FlowDomain.CreateAccessToken(aud, scope, user)
-	refreshToken := usecase.oauth2FlowDomain.CreateRefreshToken(aud, scope, user.ID)
+func (usecase *OAuth2FlowUsecase) getTokenType(accessToken *domain.OAuth2AccessToken) string {
+	if accessToken.KeyThumbprint != "" {
+		return AccessTokenTypeDPoP
+	}
 
-	// Serialize both tokens.
-	accessTokenString, refreshTokenString, err := usecase.serializeAccessAndRefreshTokens(ctx, accessToken, refreshToken)
-	if err != nil {
-		return nil, err
+	return usecase.tokenEngine.Type()
+}
+
+// getAudience returns the requested resource indicator (RFC 8707) if it is
+// allowed for the client. Without the resource, the client itself is the
+// audience.
+func (usecase *OAuth2FlowUsecase) getAudience(client *domain.OAuth2Client, resource string) (string, error) {
+	if resource == "" {
+		return client.ID.String(), nil
 	}
 
-	// Store refresh token information.
-	err = usecase.refreshTokenRepo.Create(
-		ctx, refreshToken.Metadata.ID.Int64(), accessToken.Metadata.ID.Int64(), 0)
-	if err != nil {
-		return nil, ErrServer.Hide(err, "failed-to-save-refresh-token")
+	if err := usecase.oauth2ClientDomain.ValidateResource(client, resource); err != nil {
+		return "", domainerr.Event(err, "failed-to-validate-resource").Enrich(ErrTargetInvalid).Error()
 	}
 
-	return &dto.OAuth2TokenResponse{
-		AccessToken:  accessTokenString,
-		TokenType:    usecase.tokenEngine.Type(),
-		ExpiresIn:    usecase.getExpiresIn(accessToken.Metadata),
-		RefreshToken: refreshTokenString,
-		Scope:        scope.String(),
-	}, nil
+	return resource, nil
 }
 
 func (usecase *OAuth2FlowUsecase) getAuthenticatedUser(ctx context.Context) (snowflake.ID, error) {
+	session, err := usecase.getAuthenticatedSession(ctx)
+	if err != nil || session == nil {
+		return 0, err
+	}
+
+	return session.UserID, nil
+}
+
+// getAuthenticatedSession returns nil if the user has not authenticated yet.
+func (usecase *OAuth2FlowUsecase) getAuthenticatedSession(ctx context.Context) (*domain.Session, error) {
 	session, err := usecase.sessionRepo.Load(ctx)
 	if err == nil {
 		xcontext.Logger(ctx).Debug("session-state", "state", session.State, "expires_at", session.ExpiresAt)
@@ -543,7 +2056,7 @@ func (usecase *OAuth2FlowUsecase) getAuthenticatedUser(ctx context.Context) (sno
 	}
 
 	if err != nil || session.ExpiresAt.Before(time.Now()) || session.State == domain.SessionStateUnauthenticated {
-		return 0, nil
+		return nil, nil
 	}
 
 	if session.State == domain.SessionStateFailedAuthentication {
@@ -552,10 +2065,10 @@ func (usecase *OAuth2FlowUsecase) getAuthenticatedUser(ctx context.Context) (sno
 			xcontext.Logger(ctx).Warn("failed-to-save-invalidate-session", "err", err)
 		}
 
-		return 0, xerror.Enrich(ErrAuthorizationAccessDenied, "the user failed to authenticate")
+		return nil, xerror.Enrich(ErrAuthorizationAccessDenied, "the user failed to authenticate")
 	}
 
-	return session.UserID, nil
+	return session, nil
 }
 
 func (usecase *OAuth2FlowUsecase) storeAuthorization(
@@ -563,9 +2076,13 @@ func (usecase *OAuth2FlowUsecase) storeAuthorization(
 	req *dto.OAuth2AuthorizeRequest,
 	scope scope.Scopes,
 ) (*domain.OAuth2AuthorizationStore, error) {
+	// The parameters of a request object are pushed like a pushed
+	// authorization request, so that they are not exposed to the browser when
+	// the authorization endpoint is revisited.
 	store := usecase.oauth2FlowDomain.CreateAuthorizationStore(
-		req.ResponseType, req.ClientID, scope, req.RedirectURI,
-		req.State, req.CodeChallenge, req.CodeChallengeMethod,
+		req.ResponseType, req.ResponseMode, req.ClientID, scope, req.RedirectURI,
+		req.State, req.CodeChallenge, req.CodeChallengeMethod, req.Nonce, req.UserCode,
+		req.RequestURI != "" || req.Request != "",
 	)
 
 	if err := usecase.oauth2CodeRepo.SaveAuthorizationStore(ctx, store); err != nil {
@@ -575,6 +2092,36 @@ func (usecase *OAuth2FlowUsecase) storeAuthorization(
 	return store, nil
 }
 
+// saveConsentResult records the decision of the user, which is then checked
+// by validateConsentResult. The accepted scope is also remembered as the
+// consent of the user to the client.
+func (usecase *OAuth2FlowUsecase) saveConsentResult(
+	ctx context.Context,
+	userID, clientID snowflake.ID,
+	accept bool,
+	rawUserScope string,
+) error {
+	var result *domain.OAuth2ConsentResult
+
+	if accept {
+		userScope := domain.ScopeEngine.ParseScopes(rawUserScope)
+		result = usecase.oauth2ConsentDomain.CreateConsentAcceptedResult(userID, clientID, userScope)
+
+		consent := usecase.oauth2ConsentDomain.CreateConsent(userID, clientID, userScope)
+		if err := usecase.oauth2ConsentRepo.Upsert(ctx, consent); err != nil {
+			return ErrServer.Hide(err, "failed-to-create-or-update-consent")
+		}
+	} else {
+		result = usecase.oauth2ConsentDomain.CreateConsentDeniedResult(userID, clientID)
+	}
+
+	if err := usecase.oauth2ConsentRepo.SaveResult(ctx, result); err != nil {
+		return ErrServer.Hide(err, "failed-to-save-consent-result")
+	}
+
+	return nil
+}
+
 func (usecase *OAuth2FlowUsecase) validateConsentResult(
 	ctx context.Context,
 	userID int64,
@@ -656,6 +2203,210 @@ func (usecase *OAuth2FlowUsecase) redirectToConsentPage(
 	return dto.NewOAuth2AuthorizeResponseRedirectToConsent(store.ID), nil, nil
 }
 
+// validateAuthorizeRequest validates the parameters of the authorization
+// request against the client and returns the requested scope.
+func (usecase *OAuth2FlowUsecase) validateAuthorizeRequest(
+	req *dto.OAuth2AuthorizeRequest,
+	client *domain.OAuth2Client,
+) (scope.Scopes, error) {
+	if err := usecase.oauth2ClientDomain.ValidateRedirectURI(client, req.RedirectURI); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-redirect-uri").Enrich(ErrRedirectURIInvalid).Error()
+	}
+
+	requestedScope := domain.ScopeEngine.ParseScopes(req.Scope)
+	if err := usecase.oauth2FlowDomain.ValidateRequestedScope(requestedScope, client); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-requested-scope").Enrich(ErrScopeInvalid).Error()
+	}
+
+	req.ResponseType = normalizeResponseType(req.ResponseType)
+	switch req.ResponseType {
+	case ResponseTypeCode, ResponseTypeIDToken, ResponseTypeCodeIDToken:
+	case ResponseTypeToken:
+		if !client.AllowImplicitFlow {
+			return nil, xerror.Enrich(ErrUnauthorizedClient, "the client is not allowed to use implicit flow")
+		}
+	default:
+		return nil, xerror.Enrich(ErrRequestInvalid, "not support response type %s", req.ResponseType)
+	}
+
+	if err := usecase.oauth2ClientDomain.ValidateResponseType(client, req.ResponseType); err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-response-type").Enrich(ErrUnauthorizedClient).Error()
+	}
+
+	switch req.ResponseMode {
+	case "", ResponseModeFragment, ResponseModeFormPost:
+	case ResponseModeQuery:
+		// Tokens must never be sent in the query, they would leak to the
+		// server hosting the client and to the browser history.
+		if req.ResponseType != ResponseTypeCode {
+			return nil, xerror.Enrich(ErrRequestInvalid,
+				"response mode %s is not allowed for response type %s", req.ResponseMode, req.ResponseType)
+		}
+	default:
+		return nil, xerror.Enrich(ErrRequestInvalid, "not support response mode %s", req.ResponseMode)
+	}
+
+	if hasResponseType(req.ResponseType, ResponseTypeCode) {
+		method, err := usecase.oauth2FlowDomain.ValidatePKCE(client, req.CodeChallenge, req.CodeChallengeMethod)
+		if err != nil {
+			return nil, domainerr.Event(err, "failed-to-validate-pkce").Enrich(ErrRequestInvalid).Error()
+		}
+
+		req.CodeChallengeMethod = method
+	}
+
+	if hasResponseType(req.ResponseType, ResponseTypeIDToken) {
+		if !requestedScope.Contains(domain.ScopeOpenID) {
+			return nil, xerror.Enrich(ErrScopeInvalid, "require %s scope for response type %s",
+				domain.ScopeOpenID, req.ResponseType)
+		}
+
+		if req.Nonce == "" {
+			return nil, xerror.Enrich(ErrRequestInvalid, "require nonce for response type %s", req.ResponseType)
+		}
+	}
+
+	return requestedScope, nil
+}
+
+// resolveAuthorizeRequest loads the parameters of the pushed authorization
+// request if the request uri is provided, or merges the claims of the request
+// object over the parameters if the request object is provided.
+func (usecase *OAuth2FlowUsecase) resolveAuthorizeRequest(
+	ctx context.Context,
+	req *dto.OAuth2AuthorizeRequest,
+	consume bool,
+) (*dto.OAuth2AuthorizeRequest, *domain.OAuth2Client, error) {
+	if req.RequestURI != "" && req.Request != "" {
+		return nil, nil, xerror.Enrich(ErrRequestInvalid, "request and request_uri must not be used together")
+	}
+
+	if req.RequestURI != "" {
+		store, err := usecase.loadPushedAuthorizationStore(ctx, req.ClientID, req.RequestURI)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if consume {
+			if err := usecase.oauth2CodeRepo.DeletePushedAuthorizationStore(ctx, store.ID); err != nil {
+				return nil, nil, ErrServer.Hide(err, "failed-to-delete-pushed-authorization-store", "aid", store.ID)
+			}
+		}
+
+		// Only the pushed parameters are trusted, the others in the query are
+		// ignored.
+		req = dto.NewOAuth2AuthorizeRequestFromPushed(store, req.RequestURI)
+	}
+
+	client, err := usecase.getClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Request != "" {
+		req, err = usecase.parseRequestObject(ctx, req, client)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return req, client, nil
+}
+
+// parseRequestObject validates the request object with the keys registered
+// by the client, then merges its claims over the other parameters (RFC 9101).
+func (usecase *OAuth2FlowUsecase) parseRequestObject(
+	ctx context.Context,
+	req *dto.OAuth2AuthorizeRequest,
+	client *domain.OAuth2Client,
+) (*dto.OAuth2AuthorizeRequest, error) {
+	if client.JWKS == "" {
+		return nil, xerror.Enrich(ErrRequestObjectInvalid, "the client has not registered any key")
+	}
+
+	engine, err := usecase.jwksEngineFactory.New(client.JWKS)
+	if err != nil {
+		return nil, xerror.Enrich(ErrRequestObjectInvalid, "the keys of the client are invalid").
+			Hide(err, "failed-to-create-client-token-engine", "cid", client.ID)
+	}
+
+	obj := dto.OAuth2RequestObject{}
+	ok, err := engine.Validate(ctx, req.Request, &obj)
+	if err != nil {
+		return nil, xerror.Enrich(ErrRequestObjectInvalid, "the request object is invalid").
+			Hide(err, "failed-to-validate-request-object", "cid", client.ID)
+	}
+
+	if !ok {
+		return nil, xerror.Enrich(ErrRequestObjectInvalid, "the request object is invalid")
+	}
+
+	var expiresAt time.Time
+	if obj.ExpiresAt != 0 {
+		expiresAt = time.Unix(int64(obj.ExpiresAt), 0)
+	}
+
+	err = usecase.oauth2FlowDomain.ValidateRequestObject(client, obj.Issuer, obj.ClientID, obj.Audience, expiresAt)
+	if err != nil {
+		return nil, domainerr.Event(err, "failed-to-validate-request-object").Enrich(ErrRequestObjectInvalid).Error()
+	}
+
+	return obj.To(req), nil
+}
+
+func (usecase *OAuth2FlowUsecase) loadPushedAuthorizationStore(
+	ctx context.Context,
+	clientID snowflake.ID,
+	requestURI string,
+) (*domain.OAuth2AuthorizationStore, error) {
+	id, err := usecase.oauth2FlowDomain.ParseRequestURI(requestURI)
+	if err != nil {
+		return nil, domainerr.Event(err, "failed-to-parse-request-uri").Enrich(ErrRequestURIInvalid).Error()
+	}
+
+	store, err := usecase.oauth2CodeRepo.LoadPushedAuthorizationStore(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrRequestURIInvalid, "the request uri is invalid or expired")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-load-pushed-authorization-store", "aid", id)
+	}
+
+	if store.ClientID != clientID {
+		return nil, xerror.Enrich(ErrRequestURIInvalid, "the request uri was not pushed by the client")
+	}
+
+	return store, nil
+}
+
+// repushAuthorizationStore pushes the authorization store again when the
+// authorization endpoint is revisited after the user is authenticated or
+// gives consent, because the previous request uri was consumed.
+func (usecase *OAuth2FlowUsecase) repushAuthorizationStore(
+	ctx context.Context,
+	store *domain.OAuth2AuthorizationStore,
+) (string, error) {
+	pushed := usecase.oauth2FlowDomain.PushAuthorizationStore(store)
+	if err := usecase.oauth2CodeRepo.SavePushedAuthorizationStore(ctx, pushed); err != nil {
+		return "", ErrServer.Hide(err, "failed-to-save-pushed-authorization-store", "aid", store.ID)
+	}
+
+	return domain.RequestURIPrefix + pushed.ID, nil
+}
+
+// normalizeResponseType sorts the space-separated values of the response
+// type, so that "id_token code" is treated the same as "code id_token".
+func normalizeResponseType(responseType string) string {
+	values := strings.Fields(responseType)
+	slices.Sort(values)
+	return strings.Join(values, " ")
+}
+
+func hasResponseType(responseType, value string) bool {
+	return slices.Contains(strings.Fields(responseType), value)
+}
+
 func (usecase *OAuth2FlowUsecase) getExpiresIn(metadata *domain.OAuth2TokenMedata) int {
 	createdAt := time.UnixMilli(metadata.ID.Time())
 	expiresAt := time.Unix(int64(metadata.ExpiresAt), 0)
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xybor/todennus-backend/domain"
+	"github.com/xybor/todennus-backend/infras/database"
+	"github.com/xybor/todennus-backend/usecase/abstraction"
+	"github.com/xybor/todennus-backend/usecase/dto"
+	"github.com/xybor/x/xcontext"
+	"github.com/xybor/x/xerror"
+)
+
+type OIDCUsecase struct {
+	issuer      string
+	tokenEngine abstraction.TokenEngine
+
+	oauth2FlowDomain abstraction.OAuth2FlowDomain
+
+	userRepo abstraction.UserRepository
+}
+
+func NewOIDCUsecase(
+	issuer string,
+	tokenEngine abstraction.TokenEngine,
+	oauth2FlowDomain abstraction.OAuth2FlowDomain,
+	userRepo abstraction.UserRepository,
+) *OIDCUsecase {
+	return &OIDCUsecase{
+		issuer:           issuer,
+		tokenEngine:      tokenEngine,
+		oauth2FlowDomain: oauth2FlowDomain,
+		userRepo:         userRepo,
+	}
+}
+
+func (usecase *OIDCUsecase) GetDiscovery(
+	ctx context.Context,
+	req *dto.OIDCGetDiscoveryRequest,
+) (*dto.OIDCGetDiscoveryResponse, error) {
+	return &dto.OIDCGetDiscoveryResponse{
+		Issuer:                   usecase.issuer,
+		Scopes:                   domain.DefinedScopes(),
+		ResponseTypes:            SupportedResponseTypes,
+		ResponseModes:            SupportedResponseModes,
+		GrantTypes:               SupportedGrantTypes,
+		CodeChallengeMethods:     usecase.oauth2FlowDomain.CodeChallengeMethods(),
+		TokenEndpointAuthMethods: SupportedTokenEndpointAuthMethods,
+		SigningAlgorithms:        []string{usecase.tokenEngine.Algorithm()},
+
+		RequestObjectSigningAlgorithms: SupportedRequestObjectSigningAlgorithms,
+		DPoPSigningAlgorithms:          SupportedDPoPSigningAlgorithms,
+		BackchannelTokenDeliveryModes:  SupportedBackchannelTokenDeliveryModes,
+	}, nil
+}
+
+func (usecase *OIDCUsecase) GetJWKS(
+	ctx context.Context,
+	req *dto.OIDCGetJWKSRequest,
+) (*dto.OIDCGetJWKSResponse, error) {
+	return dto.NewOIDCGetJWKSResponse(usecase.tokenEngine.Algorithm(), usecase.tokenEngine.PublicKeys()), nil
+}
+
+func (usecase *OIDCUsecase) GetUserInfo(
+	ctx context.Context,
+	req *dto.OIDCGetUserInfoRequest,
+) (*dto.OIDCGetUserInfoResponse, error) {
+	if !xcontext.Scope(ctx).Contains(domain.ScopeOpenID) {
+		return nil, xerror.Enrich(ErrForbidden, "insufficient scope, require %s", domain.ScopeOpenID)
+	}
+
+	userID := xcontext.RequestUserID(ctx)
+	user, err := usecase.userRepo.GetByID(ctx, userID.Int64())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, xerror.Enrich(ErrUnauthenticated, "the token was not issued for a user")
+		}
+
+		return nil, ErrServer.Hide(err, "failed-to-get-user", "uid", userID)
+	}
+
+	return dto.NewOIDCGetUserInfoResponse(ctx, user), nil
+}
@@ -0,0 +1,43 @@
+package reqctx
+
+import (
+	"context"
+
+	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/usecase/dto"
+)
+
+type contextKey int
+
+const (
+	requestClientIDKey contextKey = iota
+	clientCertificateKey
+)
+
+// WithRequestClientID stores the client which the access token of the request
+// was issued to.
+func WithRequestClientID(ctx context.Context, clientID snowflake.ID) context.Context {
+	return context.WithValue(ctx, requestClientIDKey, clientID)
+}
+
+func RequestClientID(ctx context.Context) snowflake.ID {
+	if val := ctx.Value(requestClientIDKey); val != nil {
+		return val.(snowflake.ID)
+	}
+
+	return 0
+}
+
+// WithClientCertificate stores the certificate which the client presented in
+// the mutual-TLS connection.
+func WithClientCertificate(ctx context.Context, certificate *dto.OAuth2ClientCertificate) context.Context {
+	return context.WithValue(ctx, clientCertificateKey, certificate)
+}
+
+func ClientCertificate(ctx context.Context) *dto.OAuth2ClientCertificate {
+	if val := ctx.Value(clientCertificateKey); val != nil {
+		return val.(*dto.OAuth2ClientCertificate)
+	}
+
+	return nil
+}
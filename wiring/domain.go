@@ -16,7 +16,12 @@ type Domains struct {
 	abstraction.OAuth2ConsentDomain
 }
 
-func InitializeDomains(ctx context.Context, config *config.Config, infras *Infras) (*Domains, error) {
+func InitializeDomains(
+	ctx context.Context,
+	config *config.Config,
+	infras *Infras,
+	allowPlainCodeChallenge bool,
+) (*Domains, error) {
 	var err error
 	domains := &Domains{}
 
@@ -35,6 +40,7 @@ func InitializeDomains(ctx context.Context, config *config.Config, infras *Infra
 		time.Duration(config.Variable.Authentication.AccessTokenExpiration)*time.Second,
 		time.Duration(config.Variable.Authentication.RefreshTokenExpiration)*time.Second,
 		time.Duration(config.Variable.Authentication.IDTokenExpiration)*time.Second,
+		allowPlainCodeChallenge,
 	)
 	if err != nil {
 		return nil, err
@@ -4,21 +4,27 @@ import (
 	"context"
 
 	"github.com/xybor-x/snowflake"
+	"github.com/xybor/todennus-backend/infras/notification"
+	"github.com/xybor/todennus-backend/infras/token"
 	config "github.com/xybor/todennus-config"
 	"github.com/xybor/x/logging"
 	"github.com/xybor/x/session"
-	"github.com/xybor/x/token"
 	"github.com/xybor/x/xcontext"
 )
 
 type Infras struct {
-	Logger         logging.Logger
-	SnowflakeNode  int64
-	TokenEngine    token.Engine
-	SessionManager *session.Manager
+	Logger            logging.Logger
+	SnowflakeNode     int64
+	TokenEngine       *token.JWTEngine
+	JWKSEngineFactory *token.JWKSEngineFactory
+	DPoPProofEngine   *token.DPoPProofEngine
+	IssuerEngine      *token.TrustedIssuerEngine
+	UserNotifier      *notification.LogUserNotifier
+	ClientNotifier    *notification.HTTPClientNotifier
+	SessionManager    *session.Manager
 }
 
-func InitializeInfras(config *config.Config) (*Infras, error) {
+func InitializeInfras(config *config.Config, trustedIssuersPath string) (*Infras, error) {
 	infras := &Infras{}
 
 	// Logger
@@ -45,6 +51,17 @@ func InitializeInfras(config *config.Config) (*Infras, error) {
 	}
 
 	infras.TokenEngine = tokenEngine
+	infras.JWKSEngineFactory = token.NewJWKSEngineFactory()
+	infras.DPoPProofEngine = token.NewDPoPProofEngine()
+
+	infras.IssuerEngine = token.NewTrustedIssuerEngine()
+	if err := infras.IssuerEngine.LoadFile(trustedIssuersPath); err != nil {
+		return infras, err
+	}
+
+	infras.UserNotifier = notification.NewLogUserNotifier()
+	infras.ClientNotifier = notification.NewHTTPClientNotifier()
+
 	infras.SessionManager = session.NewManager("/", config.Variable.Session.Expiration)
 
 	return infras, nil
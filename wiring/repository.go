@@ -16,9 +16,15 @@ import (
 type Repositories struct {
 	abstraction.UserRepository
 	abstraction.RefreshTokenRepository
+	abstraction.OAuth2TokenDenylistRepository
 	abstraction.OAuth2ClientRepository
+	abstraction.OAuth2ClientAssertionRepository
+	abstraction.OAuth2DPoPProofRepository
+	abstraction.OAuth2DPoPNonceRepository
 	abstraction.SessionRepository
 	abstraction.OAuth2AuthorizationCodeRepository
+	abstraction.OAuth2DeviceCodeRepository
+	abstraction.OAuth2BackchannelAuthenticationRepository
 	abstraction.OAuth2ConsentRepository
 }
 
@@ -27,13 +33,19 @@ func InitializeRepositories(ctx context.Context, config *config.Config, db *Data
 
 	r.UserRepository = gorm.NewUserRepository(db.GormPostgres)
 	r.RefreshTokenRepository = gorm.NewRefreshTokenRepository(db.GormPostgres)
+	r.OAuth2TokenDenylistRepository = redis.NewOAuth2TokenDenylistRepository(db.Redis)
 	r.OAuth2ClientRepository = gorm.NewOAuth2ClientRepository(db.GormPostgres)
+	r.OAuth2ClientAssertionRepository = redis.NewOAuth2ClientAssertionRepository(db.Redis)
+	r.OAuth2DPoPProofRepository = redis.NewOAuth2DPoPProofRepository(db.Redis)
+	r.OAuth2DPoPNonceRepository = redis.NewOAuth2DPoPNonceRepository(db.Redis)
 	r.SessionRepository = gorm.NewSessionRepository(
 		session.NewCookieStore[model.SessionModel](
 			[]byte(config.Secret.Session.AuthenticationKey),
 			xcrypto.GenerateAESKeyFromPassword(config.Secret.Session.EncryptionKey, 32),
 		))
 	r.OAuth2AuthorizationCodeRepository = redis.NewOAuth2AuthorizationCodeRepository(db.Redis)
+	r.OAuth2DeviceCodeRepository = redis.NewOAuth2DeviceCodeRepository(db.Redis)
+	r.OAuth2BackchannelAuthenticationRepository = redis.NewOAuth2BackchannelAuthenticationRepository(db.Redis)
 	r.OAuth2ConsentRepository = composite.NewOAuth2ConsentRepository(db.GormPostgres, db.Redis)
 
 	return r, nil
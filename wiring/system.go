@@ -16,13 +16,20 @@ type System struct {
 	Usecases     *Usecases
 }
 
-func InitializeSystem(paths ...string) (*System, context.Context, error) {
+// InitializeSystem loads the config from the env files. The trusted issuers
+// file is optional, it lists the issuers for the JWT bearer grant. The plain
+// code challenge method of PKCE is rejected unless it is allowed explicitly.
+func InitializeSystem(
+	trustedIssuersPath string,
+	allowPlainCodeChallenge bool,
+	paths ...string,
+) (*System, context.Context, error) {
 	config, err := config.Load(sources(paths)...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load variable and secrets, err=%w", err)
 	}
 
-	infras, err := InitializeInfras(config)
+	infras, err := InitializeInfras(config, trustedIssuersPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to initialize context, err=%w", err)
 	}
@@ -30,7 +37,7 @@ func InitializeSystem(paths ...string) (*System, context.Context, error) {
 	ctx := context.Background()
 	ctx = WithInfras(ctx, infras)
 
-	domains, err := InitializeDomains(ctx, config, infras)
+	domains, err := InitializeDomains(ctx, config, infras, allowPlainCodeChallenge)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to initialize domains, err=%w", err)
 	}
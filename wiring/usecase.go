@@ -14,6 +14,7 @@ type Usecases struct {
 	abstraction.UserUsecase
 	abstraction.OAuth2Usecase
 	abstraction.OAuth2ClientUsecase
+	abstraction.OIDCUsecase
 }
 
 func InitializeUsecases(
@@ -34,6 +35,11 @@ func InitializeUsecases(
 
 	uc.OAuth2Usecase = usecase.NewOAuth2Usecase(
 		infras.TokenEngine,
+		infras.JWKSEngineFactory,
+		infras.DPoPProofEngine,
+		infras.IssuerEngine,
+		infras.UserNotifier,
+		infras.ClientNotifier,
 		config.Variable.OAuth2.IdPLoginURL,
 		config.Secret.OAuth2.IdPSecret,
 		domains.UserDomain,
@@ -42,9 +48,15 @@ func InitializeUsecases(
 		domains.OAuth2ConsentDomain,
 		repositories.UserRepository,
 		repositories.RefreshTokenRepository,
+		repositories.OAuth2TokenDenylistRepository,
 		repositories.OAuth2ClientRepository,
+		repositories.OAuth2ClientAssertionRepository,
+		repositories.OAuth2DPoPProofRepository,
+		repositories.OAuth2DPoPNonceRepository,
 		repositories.SessionRepository,
 		repositories.OAuth2AuthorizationCodeRepository,
+		repositories.OAuth2DeviceCodeRepository,
+		repositories.OAuth2BackchannelAuthenticationRepository,
 		repositories.OAuth2ConsentRepository,
 	)
 
@@ -56,5 +68,12 @@ func InitializeUsecases(
 		repositories.OAuth2ClientRepository,
 	)
 
+	uc.OIDCUsecase = usecase.NewOIDCUsecase(
+		config.Variable.Authentication.TokenIssuer,
+		infras.TokenEngine,
+		domains.OAuth2FlowDomain,
+		repositories.UserRepository,
+	)
+
 	return uc, nil
 }